@@ -0,0 +1,133 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/stateless"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/params"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+var blockTraceRunCommand = cli.Command{
+	Action:    blockTraceRunCmd,
+	Name:      "blocktrace-run",
+	Usage:     "re-executes blocks from their block traces and checks the results",
+	ArgsUsage: "<file> [<file>...]",
+	Description: `
+The blocktrace-run command re-executes the block of each given block trace on a
+partial zktrie state built only from the witness in the trace, and checks the
+resulting state root, withdraw trie root and receipts against the trace.
+
+The chain config is selected by the chain ID of the trace among the known Scroll
+networks, unless a genesis file is given with --prestate.`,
+}
+
+// BlockTraceResult contains the outcome of re-executing a block trace.
+type BlockTraceResult struct {
+	File             string      `json:"file"`
+	Number           uint64      `json:"number"`
+	Hash             common.Hash `json:"hash"`
+	Pass             bool        `json:"pass"`
+	Root             common.Hash `json:"stateRoot,omitempty"`
+	WithdrawTrieRoot common.Hash `json:"withdrawTrieRoot,omitempty"`
+	GasUsed          uint64      `json:"gasUsed"`
+	Error            string      `json:"error,omitempty"`
+}
+
+func blockTraceRunCmd(ctx *cli.Context) error {
+	if len(ctx.Args().First()) == 0 {
+		return errors.New("path-to-trace argument required")
+	}
+	// Configure the go-ethereum logger
+	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	glogger.Verbosity(log.Lvl(ctx.GlobalInt(VerbosityFlag.Name)))
+	log.Root().SetHandler(glogger)
+
+	var genesisConfig *params.ChainConfig
+	if ctx.GlobalString(GenesisFlag.Name) != "" {
+		genesisConfig = readGenesis(ctx.GlobalString(GenesisFlag.Name)).Config
+	}
+	var (
+		results = make([]BlockTraceResult, 0, ctx.NArg())
+		failed  int
+	)
+	for _, file := range ctx.Args() {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var trace types.BlockTrace
+		if err := json.Unmarshal(src, &trace); err != nil {
+			return fmt.Errorf("invalid block trace %s: %v", file, err)
+		}
+		if trace.Header == nil {
+			return fmt.Errorf("invalid block trace %s: missing header", file)
+		}
+		result := BlockTraceResult{File: file, Number: trace.Header.Number.Uint64(), Hash: trace.Header.Hash(), Pass: true}
+
+		config := genesisConfig
+		if config == nil {
+			config = scrollChainConfig(trace.ChainID)
+		}
+		if config == nil {
+			result.Pass, result.Error = false, fmt.Sprintf("unknown chain id %d", trace.ChainID)
+		} else {
+			res, err := stateless.Verify(config, &trace)
+			if res != nil {
+				result.Root, result.WithdrawTrieRoot, result.GasUsed = res.Root, res.WithdrawTrieRoot, res.GasUsed
+			}
+			if err != nil {
+				result.Pass, result.Error = false, err.Error()
+			}
+		}
+		if !result.Pass {
+			failed++
+		}
+		results = append(results, result)
+	}
+	out, _ := json.MarshalIndent(results, "", "  ")
+	fmt.Println(string(out))
+	if failed > 0 {
+		return fmt.Errorf("%d of %d block traces failed", failed, len(results))
+	}
+	return nil
+}
+
+// scrollChainConfig returns the config of the known Scroll network with the
+// given chain ID, or nil if there is none.
+func scrollChainConfig(chainID uint64) *params.ChainConfig {
+	for _, config := range []*params.ChainConfig{
+		params.ScrollMainnetChainConfig,
+		params.ScrollSepoliaChainConfig,
+		params.ScrollAlphaChainConfig,
+	} {
+		if config.ChainID.Uint64() == chainID {
+			return config
+		}
+	}
+	return nil
+}
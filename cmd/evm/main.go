@@ -220,6 +220,7 @@ func init() {
 		disasmCommand,
 		runCommand,
 		stateTestCommand,
+		blockTraceRunCommand,
 		stateTransitionCommand,
 		transactionCommand,
 		blockBuilderCommand,
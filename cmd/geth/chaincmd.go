@@ -26,6 +26,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	zkt "github.com/scroll-tech/zktrie/types"
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/scroll-tech/go-ethereum/cmd/utils"
@@ -40,6 +41,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/metrics"
 	"github.com/scroll-tech/go-ethereum/node"
+	"github.com/scroll-tech/go-ethereum/trie"
 )
 
 var (
@@ -390,11 +392,12 @@ func exportPreimages(ctx *cli.Context) error {
 	return nil
 }
 
-func parseDumpConfig(ctx *cli.Context, stack *node.Node) (*state.DumpConfig, ethdb.Database, common.Hash, error) {
-	db := utils.MakeChainDatabase(ctx, stack, true)
+// parseBlockHeader resolves the block header selected by the optional number or
+// hash argument, defaulting to the head header.
+func parseBlockHeader(ctx *cli.Context, db ethdb.Database) (*types.Header, error) {
 	var header *types.Header
 	if ctx.NArg() > 1 {
-		return nil, nil, common.Hash{}, fmt.Errorf("expected 1 argument (number or hash), got %d", ctx.NArg())
+		return nil, fmt.Errorf("expected 1 argument (number or hash), got %d", ctx.NArg())
 	}
 	if ctx.NArg() == 1 {
 		arg := ctx.Args().First()
@@ -403,17 +406,17 @@ func parseDumpConfig(ctx *cli.Context, stack *node.Node) (*state.DumpConfig, eth
 			if number := rawdb.ReadHeaderNumber(db, hash); number != nil {
 				header = rawdb.ReadHeader(db, hash, *number)
 			} else {
-				return nil, nil, common.Hash{}, fmt.Errorf("block %x not found", hash)
+				return nil, fmt.Errorf("block %x not found", hash)
 			}
 		} else {
 			number, err := strconv.Atoi(arg)
 			if err != nil {
-				return nil, nil, common.Hash{}, err
+				return nil, err
 			}
 			if hash := rawdb.ReadCanonicalHash(db, uint64(number)); hash != (common.Hash{}) {
 				header = rawdb.ReadHeader(db, hash, uint64(number))
 			} else {
-				return nil, nil, common.Hash{}, fmt.Errorf("header for block %d not found", number)
+				return nil, fmt.Errorf("header for block %d not found", number)
 			}
 		}
 	} else {
@@ -421,7 +424,16 @@ func parseDumpConfig(ctx *cli.Context, stack *node.Node) (*state.DumpConfig, eth
 		header = rawdb.ReadHeadHeader(db)
 	}
 	if header == nil {
-		return nil, nil, common.Hash{}, errors.New("no head block found")
+		return nil, errors.New("no head block found")
+	}
+	return header, nil
+}
+
+func parseDumpConfig(ctx *cli.Context, stack *node.Node) (*state.DumpConfig, ethdb.Database, common.Hash, error) {
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	header, err := parseBlockHeader(ctx, db)
+	if err != nil {
+		return nil, nil, common.Hash{}, err
 	}
 	startArg := common.FromHex(ctx.String(utils.StartKeyFlag.Name))
 	var start common.Hash
@@ -430,7 +442,15 @@ func parseDumpConfig(ctx *cli.Context, stack *node.Node) (*state.DumpConfig, eth
 	case 32:
 		start = common.BytesToHash(startArg)
 	case 20:
-		start = crypto.Keccak256Hash(startArg)
+		if isZktrieDatabase(db) {
+			key, err := zkt.ToSecureKeyBytes(startArg)
+			if err != nil {
+				return nil, nil, common.Hash{}, err
+			}
+			start = common.BytesToHash(key.Bytes())
+		} else {
+			start = crypto.Keccak256Hash(startArg)
+		}
 		log.Info("Converting start-address to hash", "address", common.BytesToAddress(startArg), "hash", start.Hex())
 	default:
 		return nil, nil, common.Hash{}, fmt.Errorf("invalid start argument: %x. 20 or 32 hex-encoded bytes required", startArg)
@@ -456,7 +476,8 @@ func dump(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	state, err := state.New(root, state.NewDatabase(db), nil)
+	config := &trie.Config{Preimages: true, Zktrie: isZktrieDatabase(db)}
+	state, err := state.New(root, state.NewDatabaseWithConfig(db, config), nil)
 	if err != nil {
 		return err
 	}
@@ -473,6 +494,17 @@ func dump(ctx *cli.Context) error {
 	return nil
 }
 
+// isZktrieDatabase returns whether the chain stored in the database commits to
+// its state with a zktrie, according to the stored chain config.
+func isZktrieDatabase(db ethdb.Database) bool {
+	genesis := rawdb.ReadCanonicalHash(db, 0)
+	if genesis == (common.Hash{}) {
+		return false
+	}
+	config := rawdb.ReadChainConfig(db, genesis)
+	return config != nil && config.Scroll.ZktrieEnabled()
+}
+
 // hashish returns true for strings that look like hashes.
 func hashish(x string) bool {
 	_, err := strconv.Atoi(x)
@@ -84,6 +84,7 @@ var (
 		utils.TxPoolNoLocalsFlag,
 		utils.TxPoolJournalFlag,
 		utils.TxPoolRejournalFlag,
+		utils.TxPoolPolicyFileFlag,
 		utils.TxPoolPriceLimitFlag,
 		utils.TxPoolPriceBumpFlag,
 		utils.TxPoolAccountSlotsFlag,
@@ -91,6 +92,11 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolCircuitCheckFlag,
+		utils.TxPoolCircuitCheckBudgetFlag,
+		utils.TxPoolPrivateLifetimeFlag,
+		utils.TxPoolEvictionRetentionFlag,
+		utils.TxPoolPrivateForwardFlag,
 		utils.SyncModeFlag,
 		utils.ExitWhenSyncedFlag,
 		utils.GCModeFlag,
@@ -132,6 +138,14 @@ var (
 		utils.MinerNoVerifyFlag,
 		utils.MinerStoreSkippedTxTracesFlag,
 		utils.MinerMaxAccountsNumFlag,
+		utils.MinerHALeaseFlag,
+		utils.MinerHALeaseHolderFlag,
+		utils.MinerHALeaseTimeoutFlag,
+		utils.MinerHALeaseServeFlag,
+		utils.MinerSignerFlag,
+		utils.MinerSignerTimeoutFlag,
+		utils.MinerPreconfirmationsFlag,
+		utils.MinerHeartbeatFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV5Flag,
@@ -167,6 +181,7 @@ var (
 		utils.L1EndpointFlag,
 		utils.L1ConfirmationsFlag,
 		utils.L1DeploymentBlockFlag,
+		utils.L1BacklogThresholdFlag,
 		utils.CircuitCapacityCheckEnabledFlag,
 		utils.CircuitCapacityCheckWorkersFlag,
 		utils.RollupVerifyEnabledFlag,
@@ -196,6 +211,10 @@ var (
 		utils.RPCGlobalGasCapFlag,
 		utils.RPCGlobalEVMTimeoutFlag,
 		utils.RPCGlobalTxFeeCapFlag,
+		utils.RPCTxForwardFlag,
+		utils.RPCTxForwardRetriesFlag,
+		utils.RPCTxForwardNoLocalFlag,
+		utils.RPCPreconfirmationsFlag,
 		utils.AllowUnprotectedTxs,
 		utils.MaxBlockRangeFlag,
 	}
@@ -254,6 +273,10 @@ func init() {
 		utils.ShowDeprecated,
 		// See snapshot.go
 		snapshotCommand,
+		// See zktriecmd.go
+		zktrieCommand,
+		// See statecmd.go
+		stateCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
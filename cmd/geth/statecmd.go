@@ -0,0 +1,91 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	cli "gopkg.in/urfave/cli.v1"
+
+	"github.com/scroll-tech/go-ethereum/cmd/utils"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/trie"
+)
+
+var (
+	stateCommand = cli.Command{
+		Name:        "state",
+		Usage:       "A set of commands operating on the world state",
+		Category:    "MISCELLANEOUS COMMANDS",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:      "convert",
+				Usage:     "Rebuild the state of a block in the other trie format (MPT or zktrie)",
+				ArgsUsage: "[? <blockHash> | <blockNum>]",
+				Action:    utils.MigrateFlags(convertState),
+				Category:  "MISCELLANEOUS COMMANDS",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+					utils.ScrollAlphaFlag,
+					utils.ScrollSepoliaFlag,
+					utils.ScrollFlag,
+				},
+				Description: `
+geth state convert [? <blockHash> | <blockNum>]
+will rebuild the full state of the given block in the trie format the chain is
+not using: an MPT state is converted into a zktrie state and vice versa. The
+converted state is written into the same database and verified account by
+account and slot by slot against the original before its root is printed.
+
+Trie keys are hashed differently in the two formats, so the node must have been
+run with --cache.preimages to record the preimages of all accounts and slots.
+
+The argument is interpreted as block number or hash. If none is provided, the
+latest block is used.
+`,
+			},
+		},
+	}
+)
+
+// convertState rebuilds the state of the selected block in the other trie format.
+func convertState(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, false)
+
+	header, err := parseBlockHeader(ctx, chaindb)
+	if err != nil {
+		return err
+	}
+	zktrie := isZktrieDatabase(chaindb)
+	src := state.NewDatabaseWithConfig(chaindb, &trie.Config{Preimages: true, Zktrie: zktrie})
+	dst := state.NewDatabaseWithConfig(chaindb, &trie.Config{Preimages: true, Zktrie: !zktrie})
+
+	log.Info("Converting state", "number", header.Number, "hash", header.Hash(), "root", header.Root, "zktrie", zktrie)
+	root, err := state.ConvertState(src, header.Root, dst)
+	if err != nil {
+		log.Error("Failed to convert state", "root", header.Root, "err", err)
+		return err
+	}
+	fmt.Printf("%#x\n", root)
+	return nil
+}
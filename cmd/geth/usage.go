@@ -102,6 +102,7 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.TxPoolNoLocalsFlag,
 			utils.TxPoolJournalFlag,
 			utils.TxPoolRejournalFlag,
+			utils.TxPoolPolicyFileFlag,
 			utils.TxPoolPriceLimitFlag,
 			utils.TxPoolPriceBumpFlag,
 			utils.TxPoolAccountSlotsFlag,
@@ -109,6 +110,11 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
 			utils.TxPoolLifetimeFlag,
+			utils.TxPoolCircuitCheckFlag,
+			utils.TxPoolCircuitCheckBudgetFlag,
+			utils.TxPoolPrivateLifetimeFlag,
+			utils.TxPoolEvictionRetentionFlag,
+			utils.TxPoolPrivateForwardFlag,
 		},
 	},
 	{
@@ -158,6 +164,10 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.RPCGlobalGasCapFlag,
 			utils.RPCGlobalEVMTimeoutFlag,
 			utils.RPCGlobalTxFeeCapFlag,
+			utils.RPCTxForwardFlag,
+			utils.RPCTxForwardRetriesFlag,
+			utils.RPCTxForwardNoLocalFlag,
+			utils.RPCPreconfirmationsFlag,
 			utils.AllowUnprotectedTxs,
 			utils.JSpathFlag,
 			utils.ExecFlag,
@@ -195,6 +205,14 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.MinerNoVerifyFlag,
 			utils.MinerStoreSkippedTxTracesFlag,
 			utils.MinerMaxAccountsNumFlag,
+			utils.MinerHALeaseFlag,
+			utils.MinerHALeaseHolderFlag,
+			utils.MinerHALeaseTimeoutFlag,
+			utils.MinerHALeaseServeFlag,
+			utils.MinerSignerFlag,
+			utils.MinerSignerTimeoutFlag,
+			utils.MinerPreconfirmationsFlag,
+			utils.MinerHeartbeatFlag,
 		},
 	},
 	{
@@ -0,0 +1,206 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	zktrie "github.com/scroll-tech/zktrie/trie"
+	cli "gopkg.in/urfave/cli.v1"
+
+	"github.com/scroll-tech/go-ethereum/cmd/utils"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto/codehash"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/trie"
+)
+
+var (
+	zktrieCommand = cli.Command{
+		Name:        "zktrie",
+		Usage:       "A set of commands operating on zktrie state",
+		Category:    "MISCELLANEOUS COMMANDS",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:      "verify",
+				Usage:     "Verify the integrity of the zktrie state with given root hash",
+				ArgsUsage: "<root>",
+				Action:    utils.MigrateFlags(verifyZktrie),
+				Category:  "MISCELLANEOUS COMMANDS",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+					utils.ScrollAlphaFlag,
+					utils.ScrollSepoliaFlag,
+					utils.ScrollFlag,
+				},
+				Description: `
+geth zktrie verify <state-root>
+will traverse the whole zktrie state from the given root, recompute the Poseidon
+hash of every account and storage trie node, check the encoding of every account
+and storage slot, and check that every referenced contract code is present and
+matches both its Keccak and Poseidon code hash. All missing or corrupt entries
+are reported instead of aborting at the first one. The default checking target
+is the HEAD state.
+`,
+			},
+		},
+	}
+)
+
+// zkCodeKey identifies a contract code by everything an account commits to, so
+// that accounts sharing a Keccak code hash but disagreeing on the rest are each
+// checked.
+type zkCodeKey struct {
+	keccak   common.Hash
+	poseidon common.Hash
+	size     uint64
+}
+
+// verifyZktrie walks the zktrie state and reports every missing or corrupt node,
+// account, storage slot and contract code it encounters.
+func verifyZktrie(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, true)
+	if !isZktrieDatabase(chaindb) {
+		log.Error("State is not stored in a zktrie")
+		return errors.New("not a zktrie database")
+	}
+	headBlock := rawdb.ReadHeadBlock(chaindb)
+	if headBlock == nil {
+		log.Error("Failed to load head block")
+		return errors.New("no head block")
+	}
+	if ctx.NArg() > 1 {
+		log.Error("Too many arguments given")
+		return errors.New("too many arguments")
+	}
+	var (
+		root common.Hash
+		err  error
+	)
+	if ctx.NArg() == 1 {
+		root, err = parseRoot(ctx.Args()[0])
+		if err != nil {
+			log.Error("Failed to resolve state root", "err", err)
+			return err
+		}
+		log.Info("Start verifying the zktrie state", "root", root)
+	} else {
+		root = headBlock.Root()
+		log.Info("Start verifying the zktrie state", "root", root, "number", headBlock.NumberU64())
+	}
+	var (
+		zkdb       = trie.NewZktrieDatabase(chaindb)
+		nodes      int
+		accounts   int
+		slots      int
+		codes      int
+		problems   int
+		checked    = make(map[zkCodeKey]bool)
+		lastReport time.Time
+		start      = time.Now()
+	)
+	onError := func(trieRoot common.Hash) func(*trie.ZkTrieNodeError) error {
+		return func(err *trie.ZkTrieNodeError) error {
+			problems++
+			log.Error("Corrupt zktrie node", "trie", trieRoot, "hash", err.Hash, "depth", len(err.Path), "err", err.Err)
+			return nil
+		}
+	}
+	onAccount := func(path []byte, leaf *zktrie.Node) error {
+		accounts++
+		key := common.BytesToHash(leaf.NodeKey.Bytes())
+		if len(leaf.ValuePreimage) != 5 || leaf.CompressedFlags != 8 {
+			problems++
+			log.Error("Invalid account encoding", "key", key, "fields", len(leaf.ValuePreimage), "flags", leaf.CompressedFlags)
+			return nil
+		}
+		acc, err := types.UnmarshalStateAccount(leaf.Data())
+		if err != nil {
+			problems++
+			log.Error("Invalid account encountered during traversal", "key", key, "err", err)
+			return nil
+		}
+		if acc.Root != (common.Hash{}) {
+			n, err := trie.VerifyZkTrie(zkdb, acc.Root, func(path []byte, leaf *zktrie.Node) error {
+				slots++
+				if len(leaf.ValuePreimage) != 1 || leaf.CompressedFlags != 1 {
+					problems++
+					log.Error("Invalid storage slot encoding", "account", key, "slot", common.BytesToHash(leaf.NodeKey.Bytes()),
+						"fields", len(leaf.ValuePreimage), "flags", leaf.CompressedFlags)
+				}
+				return nil
+			}, onError(acc.Root))
+			if err != nil {
+				return err
+			}
+			nodes += n
+		}
+		keccakCodeHash := common.BytesToHash(acc.KeccakCodeHash)
+		if keccakCodeHash == codehash.EmptyKeccakCodeHash {
+			if !bytes.Equal(acc.PoseidonCodeHash, codehash.EmptyPoseidonCodeHash.Bytes()) || acc.CodeSize != 0 {
+				problems++
+				log.Error("Inconsistent empty code", "key", key, "poseidon", common.BytesToHash(acc.PoseidonCodeHash), "size", acc.CodeSize)
+			}
+		} else if ck := (zkCodeKey{keccakCodeHash, common.BytesToHash(acc.PoseidonCodeHash), acc.CodeSize}); !checked[ck] {
+			checked[ck] = true
+			code := rawdb.ReadCode(chaindb, keccakCodeHash)
+			switch {
+			case len(code) == 0:
+				problems++
+				log.Error("Code is missing", "key", key, "hash", keccakCodeHash)
+			case codehash.KeccakCodeHash(code) != keccakCodeHash:
+				problems++
+				log.Error("Code keccak hash mismatch", "key", key, "hash", keccakCodeHash, "have", codehash.KeccakCodeHash(code))
+			case codehash.PoseidonCodeHash(code) != common.BytesToHash(acc.PoseidonCodeHash):
+				problems++
+				log.Error("Code poseidon hash mismatch", "key", key, "hash", common.BytesToHash(acc.PoseidonCodeHash), "have", codehash.PoseidonCodeHash(code))
+			case uint64(len(code)) != acc.CodeSize:
+				problems++
+				log.Error("Code size mismatch", "key", key, "size", acc.CodeSize, "have", len(code))
+			default:
+				codes++
+			}
+		}
+		if time.Since(lastReport) > time.Second*8 {
+			log.Info("Verifying zktrie state", "nodes", nodes, "accounts", accounts, "slots", slots, "codes", codes, "problems", problems, "elapsed", common.PrettyDuration(time.Since(start)))
+			lastReport = time.Now()
+		}
+		return nil
+	}
+	n, err := trie.VerifyZkTrie(zkdb, root, onAccount, onError(root))
+	if err != nil {
+		log.Error("Failed to verify zktrie state", "root", root, "err", err)
+		return err
+	}
+	nodes += n
+	if problems > 0 {
+		log.Error("Zktrie state is corrupt", "nodes", nodes, "accounts", accounts, "slots", slots, "codes", codes, "problems", problems, "elapsed", common.PrettyDuration(time.Since(start)))
+		return fmt.Errorf("found %d problems in zktrie state", problems)
+	}
+	log.Info("Zktrie state is complete", "nodes", nodes, "accounts", accounts, "slots", slots, "codes", codes, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -74,6 +74,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/p2p/nat"
 	"github.com/scroll-tech/go-ethereum/p2p/netutil"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/sync_service"
 	"github.com/scroll-tech/go-ethereum/rollup/tracing"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
@@ -367,6 +368,11 @@ var (
 		Usage: "Disk journal for local transaction to survive node restarts",
 		Value: core.DefaultTxPoolConfig.Journal,
 	}
+	TxPoolPolicyFileFlag = cli.StringFlag{
+		Name:  "txpool.policy",
+		Usage: "Disk file the sender admission policy of the pool is persisted to",
+		Value: core.DefaultTxPoolConfig.PolicyFile,
+	}
 	TxPoolRejournalFlag = cli.DurationFlag{
 		Name:  "txpool.rejournal",
 		Usage: "Time interval to regenerate the local transaction journal",
@@ -407,6 +413,29 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: ethconfig.Defaults.TxPool.Lifetime,
 	}
+	TxPoolCircuitCheckFlag = cli.BoolFlag{
+		Name:  "txpool.ccc",
+		Usage: "Reject transactions that overflow the circuit capacity of an empty block",
+	}
+	TxPoolCircuitCheckBudgetFlag = cli.Uint64Flag{
+		Name:  "txpool.ccc.budget",
+		Usage: "Maximum number of transactions checked against the circuit capacity per second, over which they are admitted unchecked (0 = unlimited)",
+		Value: ethconfig.Defaults.TxPool.CircuitCheckBudget,
+	}
+	TxPoolPrivateLifetimeFlag = cli.DurationFlag{
+		Name:  "txpool.private.lifetime",
+		Usage: "Maximum amount of time private transactions are kept before being dropped",
+		Value: ethconfig.Defaults.TxPool.PrivateLifetime,
+	}
+	TxPoolEvictionRetentionFlag = cli.DurationFlag{
+		Name:  "txpool.evictions.retention",
+		Usage: "Amount of time transactions dropped from the pool are remembered for status queries",
+		Value: ethconfig.Defaults.TxPool.EvictionRetention,
+	}
+	TxPoolPrivateForwardFlag = cli.StringFlag{
+		Name:  "txpool.private.forward",
+		Usage: "RPC endpoint of the sequencer private transactions are forwarded to, instead of being kept locally (default = the --rpc.txforward endpoints)",
+	}
 	// Performance tuning settings
 	CacheFlag = cli.IntFlag{
 		Name:  "cache",
@@ -506,6 +535,40 @@ var (
 		Usage: "Maximum number of accounts that miner will fetch the pending transactions of when building a new block",
 		Value: math.MaxInt,
 	}
+	MinerHALeaseFlag = cli.StringFlag{
+		Name:  "miner.ha.lease",
+		Usage: "Lease shared by highly available sequencers, as a file path or the RPC endpoint of a node serving it with --miner.ha.serve (only the holder produces blocks)",
+	}
+	MinerHALeaseHolderFlag = cli.StringFlag{
+		Name:  "miner.ha.holder",
+		Usage: "Name of this sequencer in the lease (default = hostname)",
+	}
+	MinerHALeaseTimeoutFlag = cli.DurationFlag{
+		Name:  "miner.ha.timeout",
+		Usage: "Duration of the sequencer lease, after which a standby sequencer takes over",
+		Value: ethconfig.Defaults.Miner.HALeaseTimeout,
+	}
+	MinerHALeaseServeFlag = cli.StringFlag{
+		Name:  "miner.ha.serve",
+		Usage: "Serve the sequencer lease stored in the given file over RPC in the lease namespace (enable with --http.api or --ws.api), for sequencers on other hosts",
+	}
+	MinerPreconfirmationsFlag = cli.BoolFlag{
+		Name:  "miner.preconfirmations",
+		Usage: "Publish signed preconfirmations of the transactions in the block being built (eth_subscribe \"preconfirmations\")",
+	}
+	MinerSignerFlag = cli.StringFlag{
+		Name:  "miner.signer",
+		Usage: "External signer (clef) endpoint sealing blocks instead of a local account, as an IPC path or HTTP/WS URL",
+	}
+	MinerSignerTimeoutFlag = cli.DurationFlag{
+		Name:  "miner.signer.timeout",
+		Usage: "Maximum time to wait for the signature of a block before retrying",
+		Value: ethconfig.Defaults.Miner.SignerTimeout,
+	}
+	MinerHeartbeatFlag = cli.DurationFlag{
+		Name:  "miner.heartbeat",
+		Usage: "Maximum time between blocks, after which a block is sealed even if empty (0 = no empty blocks)",
+	}
 	// Account settings
 	UnlockedAccountFlag = cli.StringFlag{
 		Name:  "unlock",
@@ -545,6 +608,23 @@ var (
 		Usage: "Sets a cap on transaction fee (in ether) that can be sent via the RPC APIs (0 = no cap)",
 		Value: ethconfig.Defaults.RPCTxFeeCap,
 	}
+	RPCTxForwardFlag = cli.StringSliceFlag{
+		Name:  "rpc.txforward",
+		Usage: "Comma separated list of sequencer RPC endpoints the transactions submitted over RPC are forwarded to",
+	}
+	RPCTxForwardRetriesFlag = cli.IntFlag{
+		Name:  "rpc.txforward.retries",
+		Usage: "Number of retries when a sequencer cannot be reached to forward a transaction",
+		Value: ethconfig.Defaults.TxForwardRetries,
+	}
+	RPCTxForwardNoLocalFlag = cli.BoolFlag{
+		Name:  "rpc.txforward.nolocal",
+		Usage: "Only validate forwarded transactions without adding them to the local pool",
+	}
+	RPCPreconfirmationsFlag = cli.StringFlag{
+		Name:  "rpc.preconfirmations",
+		Usage: "Sequencer WebSocket or IPC endpoint whose preconfirmations are verified and relayed to local subscribers",
+	}
 	// Logging and debug settings
 	EthStatsURLFlag = cli.StringFlag{
 		Name:  "ethstats",
@@ -841,6 +921,11 @@ var (
 		Name:  "l1.sync.startblock",
 		Usage: "L1 block height to start syncing from. Should be set to the L1 message queue deployment block number.",
 	}
+	L1BacklogThresholdFlag = cli.DurationFlag{
+		Name:  "l1.backlog.threshold",
+		Usage: "Age of the oldest L1 message not included or skipped by the chain beyond which the backlog is alerted",
+		Value: sync_service.DefaultBacklogThreshold,
+	}
 
 	// Circuit capacity check settings
 	CircuitCapacityCheckEnabledFlag = cli.BoolFlag{
@@ -1469,6 +1554,9 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolJournalFlag.Name) {
 		cfg.Journal = ctx.GlobalString(TxPoolJournalFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolPolicyFileFlag.Name) {
+		cfg.PolicyFile = ctx.GlobalString(TxPoolPolicyFileFlag.Name)
+	}
 	if ctx.GlobalIsSet(TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.GlobalDuration(TxPoolRejournalFlag.Name)
 	}
@@ -1493,6 +1581,18 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolCircuitCheckFlag.Name) {
+		cfg.CircuitCheck = ctx.GlobalBool(TxPoolCircuitCheckFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolCircuitCheckBudgetFlag.Name) {
+		cfg.CircuitCheckBudget = ctx.GlobalUint64(TxPoolCircuitCheckBudgetFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolPrivateLifetimeFlag.Name) {
+		cfg.PrivateLifetime = ctx.GlobalDuration(TxPoolPrivateLifetimeFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolEvictionRetentionFlag.Name) {
+		cfg.EvictionRetention = ctx.GlobalDuration(TxPoolEvictionRetentionFlag.Name)
+	}
 }
 
 func setEthash(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1548,6 +1648,30 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.GlobalIsSet(MinerMaxAccountsNumFlag.Name) {
 		cfg.MaxAccountsNum = ctx.GlobalInt(MinerMaxAccountsNumFlag.Name)
 	}
+	if ctx.GlobalIsSet(MinerHALeaseFlag.Name) {
+		cfg.HALease = ctx.GlobalString(MinerHALeaseFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerHALeaseHolderFlag.Name) {
+		cfg.HALeaseHolder = ctx.GlobalString(MinerHALeaseHolderFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerHALeaseTimeoutFlag.Name) {
+		cfg.HALeaseTimeout = ctx.GlobalDuration(MinerHALeaseTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerHALeaseServeFlag.Name) {
+		cfg.HALeaseServe = ctx.GlobalString(MinerHALeaseServeFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerPreconfirmationsFlag.Name) {
+		cfg.Preconfirmations = ctx.GlobalBool(MinerPreconfirmationsFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerSignerFlag.Name) {
+		cfg.Signer = ctx.GlobalString(MinerSignerFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerSignerTimeoutFlag.Name) {
+		cfg.SignerTimeout = ctx.GlobalDuration(MinerSignerTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerHeartbeatFlag.Name) {
+		cfg.HeartbeatInterval = ctx.GlobalDuration(MinerHeartbeatFlag.Name)
+	}
 	if ctx.GlobalIsSet(LegacyMinerGasTargetFlag.Name) {
 		log.Warn("The generic --miner.gastarget flag is deprecated and will be removed in the future!")
 	}
@@ -1668,6 +1792,24 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	setCircuitCapacityCheck(ctx, cfg)
 	setEnableRollupVerify(ctx, cfg)
 	setMaxBlockRange(ctx, cfg)
+	if ctx.GlobalIsSet(RPCTxForwardFlag.Name) {
+		cfg.TxForwardURLs = ctx.GlobalStringSlice(RPCTxForwardFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCTxForwardRetriesFlag.Name) {
+		cfg.TxForwardRetries = ctx.GlobalInt(RPCTxForwardRetriesFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCTxForwardNoLocalFlag.Name) {
+		cfg.TxForwardNoLocal = ctx.GlobalBool(RPCTxForwardNoLocalFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCPreconfirmationsFlag.Name) {
+		cfg.PreconfirmationsURL = ctx.GlobalString(RPCPreconfirmationsFlag.Name)
+	}
+	if ctx.GlobalIsSet(L1BacklogThresholdFlag.Name) {
+		cfg.L1MessageBacklogThreshold = ctx.GlobalDuration(L1BacklogThresholdFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolPrivateForwardFlag.Name) {
+		cfg.PrivateTxForward = ctx.GlobalString(TxPoolPrivateForwardFlag.Name)
+	}
 	if ctx.GlobalIsSet(ShadowforkPeersFlag.Name) {
 		cfg.ShadowForkPeerIDs = ctx.GlobalStringSlice(ShadowforkPeersFlag.Name)
 		log.Info("Shadow fork peers", "ids", cfg.ShadowForkPeerIDs)
@@ -17,12 +17,14 @@
 package clique
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/consensus"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/rlp"
 	"github.com/scroll-tech/go-ethereum/rpc"
@@ -229,3 +231,77 @@ func (api *API) GetSigner(rlpOrBlockNr *blockNumberOrHashOrRLP) (common.Address,
 	}
 	return api.clique.Author(header)
 }
+
+// equivocation is the evidence that a signer sealed two different headers at the
+// same height. Headers are RLP encoded, so that their signer can be recovered
+// with GetSigner or on-chain.
+type equivocation struct {
+	Signer  common.Address  `json:"signer"`
+	Number  hexutil.Uint64  `json:"number"`
+	Headers []hexutil.Bytes `json:"headers"`
+}
+
+func newEquivocation(evidence *rawdb.Equivocation) (*equivocation, error) {
+	headers := make([]hexutil.Bytes, len(evidence.Headers))
+	for i, header := range evidence.Headers {
+		enc, err := rlp.EncodeToBytes(header)
+		if err != nil {
+			return nil, err
+		}
+		headers[i] = enc
+	}
+	return &equivocation{
+		Signer:  evidence.Signer,
+		Number:  hexutil.Uint64(evidence.Number),
+		Headers: headers,
+	}, nil
+}
+
+// GetEquivocations returns the evidence of the signer equivocations detected by
+// the node, from the given height onwards if any.
+func (api *API) GetEquivocations(from *hexutil.Uint64) ([]*equivocation, error) {
+	var start uint64
+	if from != nil {
+		start = uint64(*from)
+	}
+	evidences := api.clique.Equivocations(start)
+	result := make([]*equivocation, 0, len(evidences))
+	for _, evidence := range evidences {
+		ev, err := newEquivocation(evidence)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ev)
+	}
+	return result, nil
+}
+
+// Equivocations creates a subscription that is notified with the evidence of
+// every signer equivocation detected by the node.
+func (api *API) Equivocations(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		evidences := make(chan *rawdb.Equivocation, 16)
+		sub := api.clique.SubscribeEquivocations(evidences)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case evidence := <-evidences:
+				if ev, err := newEquivocation(evidence); err == nil {
+					notifier.Notify(rpcSub.ID, ev)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
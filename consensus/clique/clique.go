@@ -35,11 +35,15 @@ import (
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/consensus"
 	"github.com/scroll-tech/go-ethereum/consensus/misc"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/event"
 	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
 	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/rlp"
 	"github.com/scroll-tech/go-ethereum/rpc"
@@ -50,8 +54,16 @@ const (
 	checkpointInterval = 1024 // Number of blocks after which to save the vote snapshot to the database
 	inmemorySnapshots  = 128  // Number of recent vote snapshots to keep in memory
 	inmemorySignatures = 4096 // Number of recent block signatures to keep in memory
+	inmemorySeals      = 4096 // Number of recent headers to keep in memory for equivocation detection
+	inmemorySealSigs   = 16   // Number of recent signatures of sealed headers to keep in memory for reuse
 
 	wiggleTime = 500 * time.Millisecond // Random delay (per signer) to allow concurrent signers
+
+	defaultSignerRotationLead = 64 // Default minimum lead of L1 signer set updates, see params.CliqueConfig
+
+	// DefaultSignTimeout is the default time to wait for the signature of a
+	// block, which may be produced by a remote signer.
+	DefaultSignTimeout = 2 * time.Second
 )
 
 // Clique proof-of-authority protocol constants.
@@ -139,6 +151,19 @@ var (
 	// errRecentlySigned is returned if a header is signed by an authorized entity
 	// that already signed a header recently, thus is temporarily not allowed to.
 	errRecentlySigned = errors.New("recently signed")
+
+	// errSignTimeout is returned if the signer does not sign a block in time.
+	errSignTimeout = errors.New("timed out waiting for block signature")
+
+	// errSignStopped is returned if sealing is stopped before the signer signs
+	// a block.
+	errSignStopped = errors.New("sealing stopped while waiting for block signature")
+)
+
+var (
+	signTimer        = metrics.NewRegisteredTimer("clique/seal/sign", nil)
+	signFailureMeter = metrics.NewRegisteredMeter("clique/seal/sign/failures", nil)
+	signTimeoutMeter = metrics.NewRegisteredMeter("clique/seal/sign/timeouts", nil)
 )
 
 // SignerFn hashes and signs the data to be signed by a backing account.
@@ -177,12 +202,20 @@ type Clique struct {
 
 	recents    *lru.ARCCache // Snapshots for recent block to speed up reorgs
 	signatures *lru.ARCCache // Signatures of recent blocks to speed up mining
+	seals      *lru.ARCCache // Recent headers by height and signer to detect equivocations
+
+	equivocationFeed event.Feed // Feed of detected signer equivocations
 
 	proposals map[common.Address]bool // Current list of proposals we are pushing
 
-	signer common.Address // Ethereum address of the signing key
-	signFn SignerFn       // Signer function to authorize hashes with
-	lock   sync.RWMutex   // Protects the signer and proposals fields
+	signer      common.Address // Ethereum address of the signing key
+	signFn      SignerFn       // Signer function to authorize hashes with
+	signTimeout time.Duration  // Maximum time to wait for the signer function
+	lock        sync.RWMutex   // Protects the signer and proposals fields
+
+	signing     map[common.Hash]*signRequest // Signature requests in flight by seal hash
+	sealSigs    *lru.ARCCache                // Signatures of recently sealed headers by seal hash
+	signingLock sync.Mutex                   // Protects the signing field
 
 	// The fields below are for testing only
 	fakeDiff bool // Skip difficulty verifications
@@ -200,13 +233,19 @@ func New(config *params.CliqueConfig, db ethdb.Database) *Clique {
 	// Allocate the snapshot caches and create the engine
 	recents, _ := lru.NewARC(inmemorySnapshots)
 	signatures, _ := lru.NewARC(inmemorySignatures)
+	seals, _ := lru.NewARC(inmemorySeals)
+	sealSigs, _ := lru.NewARC(inmemorySealSigs)
 
 	return &Clique{
-		config:     &conf,
-		db:         db,
-		recents:    recents,
-		signatures: signatures,
-		proposals:  make(map[common.Address]bool),
+		config:      &conf,
+		db:          db,
+		recents:     recents,
+		signatures:  signatures,
+		seals:       seals,
+		proposals:   make(map[common.Address]bool),
+		signTimeout: DefaultSignTimeout,
+		signing:     make(map[common.Hash]*signRequest),
+		sealSigs:    sealSigs,
 	}
 }
 
@@ -366,7 +405,7 @@ func (c *Clique) verifyCascadingFields(chain consensus.ChainHeaderReader, header
 		}
 	}
 	// All basic checks passed, verify the seal and return
-	return c.verifySeal(snap, header, parents)
+	return c.verifySeal(chain, snap, header, parents)
 }
 
 // snapshot retrieves the authorization snapshot at a given point in time.
@@ -384,6 +423,14 @@ func (c *Clique) snapshot(chain consensus.ChainHeaderReader, number uint64, hash
 			snap = newSnapshot(c.config, c.signatures, number, hash, []common.Address{c.config.ShadowForkSigner})
 			break
 		}
+		// Signer set updates announced on L1 replace the signers regardless of
+		// votes. Check them before any cached snapshot, as they may be synced
+		// from L1 after the parent of their start block was processed.
+		if signers := c.signerUpdate(chain, number, hash, parents); signers != nil {
+			log.Debug("Applying L1 signer set update", "number", number+1, "signers", signers)
+			snap = newSnapshot(c.config, c.signatures, number, hash, signers)
+			break
+		}
 
 		// If an in-memory snapshot was found, use that
 		if s, ok := c.recents.Get(hash); ok {
@@ -458,6 +505,97 @@ func (c *Clique) snapshot(chain consensus.ChainHeaderReader, number uint64, hash
 	return snap, err
 }
 
+// signerUpdate returns the signer set announced on L1 to be authorized from the
+// block after the given parent onwards, or nil if the signers do not change at
+// this block.
+//
+// An update only applies if the chain committed to the L1 block announcing it
+// at least the rotation lead before its start block. All nodes verifying the
+// block have then synced it from L1, and agree on ignoring the late ones.
+func (c *Clique) signerUpdate(chain consensus.ChainHeaderReader, parent uint64, hash common.Hash, parents []*types.Header) []common.Address {
+	number := parent + 1
+	if c.config.SignerRotationBlock == nil || number < *c.config.SignerRotationBlock {
+		return nil
+	}
+	update := rawdb.ReadSignerUpdate(c.db, number)
+	if update == nil {
+		return nil
+	}
+	lead := c.config.SignerRotationLead
+	if lead == 0 {
+		lead = defaultSignerRotationLead
+	}
+	if number <= lead {
+		log.Warn("Ignoring L1 signer set update without lead", "number", number, "lead", lead)
+		return nil
+	}
+	// Find the block committing to L1 on the chain of the parent
+	header := c.ancestor(chain, parent, hash, parents, number-lead)
+	if header == nil {
+		log.Warn("Ignoring L1 signer set update with unknown ancestor", "number", number, "ancestor", number-lead)
+		return nil
+	}
+	if !chain.Config().Scroll.IsL1MessageInclusion(header.Number) {
+		log.Warn("Ignoring L1 signer set update on chain not committing to L1", "number", number)
+		return nil
+	}
+	height, err := core.L1InclusionHeight(header)
+	if err != nil {
+		log.Warn("Ignoring L1 signer set update on chain not committing to L1", "number", number, "err", err)
+		return nil
+	}
+	if height < update.L1Block {
+		log.Warn("Ignoring late L1 signer set update", "number", number, "l1Block", update.L1Block, "committed", height, "at", header.Number)
+		return nil
+	}
+	return update.Signers
+}
+
+// ancestor returns the header with the given number on the chain ending at the
+// given block, picking from the explicit parents first.
+func (c *Clique) ancestor(chain consensus.ChainHeaderReader, number uint64, hash common.Hash, parents []*types.Header, target uint64) *types.Header {
+	for {
+		var header *types.Header
+		if len(parents) > 0 && parents[len(parents)-1].Hash() == hash {
+			header, parents = parents[len(parents)-1], parents[:len(parents)-1]
+		} else {
+			header = chain.GetHeader(hash, number)
+		}
+		if header == nil || number == target {
+			return header
+		}
+		number, hash = number-1, header.ParentHash
+	}
+}
+
+// InvalidateSnapshots drops the snapshots cached in memory and stored on disk
+// for the blocks from the given number onwards, so that they are regenerated
+// with a signer set update synced from L1 after they were created.
+func (c *Clique) InvalidateSnapshots(chain consensus.ChainHeaderReader, from uint64) {
+	c.recents.Purge()
+
+	head := chain.CurrentHeader().Number.Uint64()
+	for number := (from + checkpointInterval - 1) / checkpointInterval * checkpointInterval; number <= head; number += checkpointInterval {
+		if header := chain.GetHeaderByNumber(number); header != nil {
+			if err := c.db.Delete(append([]byte("clique-"), header.Hash().Bytes()...)); err != nil {
+				log.Error("Failed to delete voting snapshot", "number", number, "err", err)
+			}
+		}
+	}
+	log.Info("Invalidated voting snapshots", "from", from)
+}
+
+// IsSigner returns whether the address is authorized to sign the block after the
+// given header.
+func (c *Clique) IsSigner(chain consensus.ChainHeaderReader, header *types.Header, signer common.Address) bool {
+	snap, err := c.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return false
+	}
+	_, authorized := snap.Signers[signer]
+	return authorized
+}
+
 // VerifyUncles implements consensus.Engine, always returning an error for any
 // uncles as this consensus mechanism doesn't permit uncles.
 func (c *Clique) VerifyUncles(chain consensus.ChainReader, block *types.Block) error {
@@ -471,7 +609,7 @@ func (c *Clique) VerifyUncles(chain consensus.ChainReader, block *types.Block) e
 // consensus protocol requirements. The method accepts an optional list of parent
 // headers that aren't yet part of the local blockchain to generate the snapshots
 // from.
-func (c *Clique) verifySeal(snap *Snapshot, header *types.Header, parents []*types.Header) error {
+func (c *Clique) verifySeal(chain consensus.ChainHeaderReader, snap *Snapshot, header *types.Header, parents []*types.Header) error {
 	// Verifying the genesis block is not supported
 	number := header.Number.Uint64()
 	if number == 0 {
@@ -485,6 +623,8 @@ func (c *Clique) verifySeal(snap *Snapshot, header *types.Header, parents []*typ
 	if _, ok := snap.Signers[signer]; !ok {
 		return errUnauthorizedSigner
 	}
+	c.checkEquivocation(chain, signer, header)
+
 	for seen, recent := range snap.Recents {
 		if recent == signer {
 			// Signer is among recents, only fail if the current block doesn't shift it out
@@ -601,6 +741,18 @@ func (c *Clique) Authorize(signer common.Address, signFn SignerFn) {
 	c.signFn = signFn
 }
 
+// SetSignTimeout sets the maximum time to wait for the signature of a block,
+// after which sealing fails. Non-positive timeouts restore the default.
+func (c *Clique) SetSignTimeout(timeout time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if timeout <= 0 {
+		timeout = DefaultSignTimeout
+	}
+	c.signTimeout = timeout
+}
+
 // Seal implements consensus.Engine, attempting to create a sealed block using
 // the local signing credentials.
 func (c *Clique) Seal(chain consensus.ChainHeaderReader, block *types.Block, results chan<- *types.Block, stop <-chan struct{}) error {
@@ -617,7 +769,7 @@ func (c *Clique) Seal(chain consensus.ChainHeaderReader, block *types.Block, res
 	}
 	// Don't hold the signer fields for the entire sealing procedure
 	c.lock.RLock()
-	signer, signFn := c.signer, c.signFn
+	signer, signFn, signTimeout := c.signer, c.signFn, c.signTimeout
 	c.lock.RUnlock()
 
 	// Bail out if we're unauthorized to sign a block
@@ -647,7 +799,7 @@ func (c *Clique) Seal(chain consensus.ChainHeaderReader, block *types.Block, res
 		log.Trace("Out-of-turn signing requested", "wiggle", common.PrettyDuration(wiggle))
 	}
 	// Sign all the things!
-	sighash, err := signFn(accounts.Account{Address: signer}, accounts.MimetypeClique, CliqueRLP(header))
+	sighash, err := c.sign(signFn, signer, header, signTimeout, stop)
 	if err != nil {
 		return err
 	}
@@ -673,6 +825,74 @@ func (c *Clique) Seal(chain consensus.ChainHeaderReader, block *types.Block, res
 	return nil
 }
 
+// signRequest is a signature request in flight to the signer function.
+type signRequest struct {
+	done chan struct{} // Closed once the signer function returned
+	sig  []byte
+	err  error
+}
+
+// sign requests the signature of a clique header from the signer function. The
+// signer may be remote, so the request is abandoned once the timeout elapses or
+// sealing is stopped. Abandoned requests are not sent again for the same header:
+// sealing it again waits for the outstanding request, or reuses its signature,
+// since signers may refuse to sign twice at the same height.
+func (c *Clique) sign(signFn SignerFn, signer common.Address, header *types.Header, timeout time.Duration, stop <-chan struct{}) ([]byte, error) {
+	sealHash := SealHash(header)
+	if sig, ok := c.sealSigs.Get(sealHash); ok {
+		log.Debug("Reusing block signature", "sealhash", sealHash)
+		return sig.([]byte), nil
+	}
+	expiry := time.NewTimer(timeout)
+	defer expiry.Stop()
+
+	c.signingLock.Lock()
+	req := c.signing[sealHash]
+	if req == nil {
+		req = &signRequest{done: make(chan struct{})}
+		c.signing[sealHash] = req
+
+		data := CliqueRLP(header)
+		go func() {
+			start := time.Now()
+			sig, err := signFn(accounts.Account{Address: signer}, accounts.MimetypeClique, data)
+			signTimer.UpdateSince(start)
+
+			switch {
+			case err != nil:
+				signFailureMeter.Mark(1)
+				req.err = fmt.Errorf("%w: %v", consensus.ErrSignerUnavailable, err)
+			case len(sig) != extraSeal:
+				signFailureMeter.Mark(1)
+				req.err = fmt.Errorf("%w: invalid signature length %d", consensus.ErrSignerUnavailable, len(sig))
+			default:
+				req.sig = sig
+				c.sealSigs.Add(sealHash, sig)
+			}
+			c.signingLock.Lock()
+			delete(c.signing, sealHash)
+			c.signingLock.Unlock()
+			close(req.done)
+		}()
+	} else {
+		log.Info("Waiting for outstanding block signature", "sealhash", sealHash)
+	}
+	c.signingLock.Unlock()
+
+	select {
+	case <-req.done:
+		return req.sig, req.err
+
+	case <-expiry.C:
+		signTimeoutMeter.Mark(1)
+		log.Warn("Block signer unresponsive", "signer", signer, "sealhash", sealHash, "timeout", common.PrettyDuration(timeout))
+		return nil, fmt.Errorf("%w: %w", consensus.ErrSignerUnavailable, errSignTimeout)
+
+	case <-stop:
+		return nil, errSignStopped
+	}
+}
+
 // CalcDifficulty is the difficulty adjustment algorithm. It returns the difficulty
 // that a new block should have:
 // * DIFF_NOTURN(2) if BLOCK_NUMBER % SIGNER_COUNT != SIGNER_INDEX
@@ -18,16 +18,23 @@ package clique
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
 	"math/big"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/scroll-tech/go-ethereum/accounts"
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/consensus"
 	"github.com/scroll-tech/go-ethereum/core"
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/core/vm"
 	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/params"
 )
 
@@ -214,3 +221,273 @@ func TestShadowFork(t *testing.T) {
 		t.Fatalf("unexpected forked chain height")
 	}
 }
+
+// Tests that signer set updates announced on L1 replace the signers at their
+// start block if the chain committed to their L1 block early enough, and that
+// cached snapshots are regenerated once an update is synced.
+func TestL1SignerRotation(t *testing.T) {
+	// Updates are ignored by engines without signer rotation
+	if height, _ := testL1SignerRotation(t, false, 7); height != 3 {
+		t.Fatalf("chain height without rotation mismatch: have %d, want %d", height, 3)
+	}
+	// Updates apply if committed to at least the rotation lead before
+	height, rotation := testL1SignerRotation(t, true, 7)
+	if height != 8 {
+		t.Fatalf("rotated chain height mismatch: have %d, want %d", height, 8)
+	}
+	// Late updates are ignored, blocks signed by the announced signer rejected
+	if height, _ := testL1SignerRotation(t, true, 6); height != 3 {
+		t.Fatalf("chain height with late update mismatch: have %d, want %d", height, 3)
+	}
+	// Updates synced after the snapshots of later blocks were created replace
+	// the signers once the snapshots are invalidated
+	head := rotation.chain.CurrentHeader()
+	if snap, err := rotation.engine.snapshot(rotation.chain, head.Number.Uint64(), head.Hash(), nil); err != nil || !reflect.DeepEqual(snap.signers(), []common.Address{rotation.rotated}) {
+		t.Fatalf("rotated signers mismatch: have %v (%v), want %v", snap.signers(), err, rotation.rotated)
+	}
+	rawdb.WriteSignerUpdate(rotation.db, head.Number.Uint64(), &rawdb.SignerUpdate{L1Block: 7, Signers: []common.Address{rotation.rotated, rotation.original}})
+	rotation.engine.InvalidateSnapshots(rotation.chain, head.Number.Uint64())
+
+	snap, err := rotation.engine.snapshot(rotation.chain, head.Number.Uint64(), head.Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to regenerate snapshot: %v", err)
+	}
+	if _, ok := snap.Signers[rotation.original]; !ok || len(snap.Signers) != 2 {
+		t.Fatalf("updated signers mismatch: have %v, want %v", snap.signers(), []common.Address{rotation.rotated, rotation.original})
+	}
+}
+
+// rotationTester is a chain rotated to a signer by an L1 update.
+type rotationTester struct {
+	db                ethdb.Database
+	chain             *core.BlockChain
+	engine            *Clique
+	original, rotated common.Address
+}
+
+// testL1SignerRotation inserts a chain whose blocks from height 4 onwards are
+// signed by the signer announced in L1 block 7, the blocks from height 2
+// onwards committing to the given L1 height. It returns the height reached.
+func testL1SignerRotation(t *testing.T, rotation bool, committed uint64) (uint64, *rotationTester) {
+	config := *params.AllCliqueProtocolChanges
+	config.Scroll.L1Config = &params.L1Config{L1MessageInclusionBlock: new(uint64)}
+
+	engineConf := *config.Clique
+	engineConf.Epoch = 2
+	if rotation {
+		engineConf.SignerRotationBlock = new(uint64)
+		engineConf.SignerRotationLead = 2
+	}
+	rotatedKey, _ := crypto.HexToECDSA(strings.Repeat("11", 32))
+	rotatedAddr := crypto.PubkeyToAddress(rotatedKey.PublicKey)
+	rotationHeight := uint64(4)
+
+	// Initialize a Clique chain with a single signer, rotated by an L1 update
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		engine = New(&engineConf, db)
+		signer = new(types.HomesteadSigner)
+	)
+	genspec := &core.Genesis{
+		Config:    &config,
+		ExtraData: make([]byte, extraVanity+common.AddressLength+extraSeal),
+		Alloc: map[common.Address]core.GenesisAccount{
+			addr: {Balance: big.NewInt(10000000000000000)},
+		},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	copy(genspec.ExtraData[extraVanity:], addr[:])
+	genesis := genspec.MustCommit(db)
+	rawdb.WriteSignerUpdate(db, rotationHeight, &rawdb.SignerUpdate{L1Block: 7, Signers: []common.Address{rotatedAddr}})
+	rawdb.WriteSyncedL1BlockNumber(db, 7)
+
+	// Generate a batch of blocks, each properly signed
+	chain, _ := core.NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+	t.Cleanup(chain.Stop)
+
+	blocks, _ := core.GenerateChain(&config, genesis, engine, db, 8, func(i int, block *core.BlockGen) {
+		// The chain maker doesn't have access to a chain, so the difficulty will be
+		// lets unset (nil). Set it here to the correct value.
+		block.SetDifficulty(diffInTurn)
+
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(addr), common.Address{0x00}, new(big.Int), params.TxGas, block.BaseFee(), nil), signer, key)
+		if err != nil {
+			panic(err)
+		}
+		block.AddTxWithChain(chain, tx)
+	})
+	for i, block := range blocks {
+		header := block.Header()
+		if i > 0 {
+			header.ParentHash = blocks[i-1].Hash()
+		}
+
+		signingAddr, signingKey := addr, key
+		if header.Number.Uint64() >= rotationHeight {
+			// start signing with the signer announced on L1
+			signingAddr, signingKey = rotatedAddr, rotatedKey
+		}
+
+		header.Extra = make([]byte, extraVanity)
+		if header.Number.Uint64() >= 2 {
+			binary.BigEndian.PutUint64(header.Extra, committed)
+		}
+		if header.Number.Uint64()%engineConf.Epoch == 0 {
+			header.Extra = append(header.Extra, signingAddr.Bytes()...)
+		}
+		header.Extra = append(header.Extra, bytes.Repeat([]byte{0}, extraSeal)...)
+
+		sig, _ := crypto.Sign(SealHash(header).Bytes(), signingKey)
+		copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+		blocks[i] = block.WithSeal(header)
+	}
+	chain.InsertChain(blocks)
+
+	return chain.CurrentHeader().Number.Uint64(), &rotationTester{db: db, chain: chain, engine: engine, original: addr, rotated: rotatedAddr}
+}
+
+func TestSignTimeout(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		engine = New(params.AllCliqueProtocolChanges.Clique, rawdb.NewMemoryDatabase())
+		stop   = make(chan struct{})
+	)
+	header := func(number int64) *types.Header {
+		return &types.Header{Number: big.NewInt(number), Extra: make([]byte, extraVanity+extraSeal)}
+	}
+	signFn := func(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), key)
+	}
+	if sig, err := engine.sign(signFn, addr, header(1), time.Second, stop); err != nil || len(sig) != extraSeal {
+		t.Fatalf("failed to sign header: sig %x, err %v", sig, err)
+	}
+	// Unresponsive signers time out, or give up once sealing is stopped
+	var (
+		requests = make(chan struct{}, 4)
+		release  = make(chan struct{})
+	)
+	unresponsive := func(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+		requests <- struct{}{}
+		<-release
+		return signFn(account, mimeType, data)
+	}
+	if _, err := engine.sign(unresponsive, addr, header(2), 50*time.Millisecond, stop); !errors.Is(err, errSignTimeout) {
+		t.Fatalf("sign error mismatch: have %v, want %v", err, errSignTimeout)
+	}
+	if _, err := engine.sign(unresponsive, addr, header(2), 50*time.Millisecond, stop); !errors.Is(err, errSignTimeout) {
+		t.Fatalf("sign error mismatch: have %v, want %v", err, errSignTimeout)
+	}
+	closed := make(chan struct{})
+	close(closed)
+	if _, err := engine.sign(unresponsive, addr, header(2), time.Minute, closed); err != errSignStopped {
+		t.Fatalf("sign error mismatch: have %v, want %v", err, errSignStopped)
+	}
+	// Sealing the header again waits for the outstanding request, and reuses its
+	// signature afterwards, without requesting it again
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+	sig, err := engine.sign(unresponsive, addr, header(2), time.Second, stop)
+	if err != nil || len(sig) != extraSeal {
+		t.Fatalf("failed to wait for outstanding signature: sig %x, err %v", sig, err)
+	}
+	if reused, err := engine.sign(unresponsive, addr, header(2), time.Second, stop); err != nil || !bytes.Equal(reused, sig) {
+		t.Fatalf("reused signature mismatch: have %x (%v), want %x", reused, err, sig)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("signature requests mismatch: have %d, want %d", len(requests), 1)
+	}
+	// Malformed signatures are rejected, and requested again
+	short := func(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+		return make([]byte, extraSeal-1), nil
+	}
+	if _, err := engine.sign(short, addr, header(3), time.Second, stop); !errors.Is(err, consensus.ErrSignerUnavailable) {
+		t.Fatalf("sign error mismatch: have %v, want %v", err, consensus.ErrSignerUnavailable)
+	}
+	if sig, err := engine.sign(signFn, addr, header(3), time.Second, stop); err != nil || len(sig) != extraSeal {
+		t.Fatalf("failed to sign header again: sig %x, err %v", sig, err)
+	}
+}
+
+// Tests that headers sealed by the same signer at the same height are recorded
+// as equivocation evidence.
+func TestEquivocation(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		engine = New(params.AllCliqueProtocolChanges.Clique, db)
+	)
+	engine.fakeDiff = true
+
+	genspec := &core.Genesis{
+		ExtraData: make([]byte, extraVanity+common.AddressLength+extraSeal),
+		BaseFee:   big.NewInt(params.InitialBaseFee),
+	}
+	copy(genspec.ExtraData[extraVanity:], addr[:])
+	genesis := genspec.MustCommit(db)
+
+	chain, _ := core.NewBlockChain(db, nil, params.AllCliqueProtocolChanges, engine, vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	// Seal two different blocks at height 1 with the same signer
+	seal := func(extra byte) *types.Block {
+		blocks, _ := core.GenerateChain(params.AllCliqueProtocolChanges, genesis, engine, db, 1, func(i int, block *core.BlockGen) {
+			block.SetDifficulty(diffInTurn)
+		})
+		header := blocks[0].Header()
+		header.Extra = append(make([]byte, extraVanity-1), extra)
+		header.Extra = append(header.Extra, make([]byte, extraSeal)...)
+
+		sig, _ := crypto.Sign(SealHash(header).Bytes(), key)
+		copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+		return blocks[0].WithSeal(header)
+	}
+	first, second := seal(1), seal(2)
+	if _, err := chain.InsertChain(types.Blocks{first}); err != nil {
+		t.Fatalf("failed to insert block: %v", err)
+	}
+	if evidences := engine.Equivocations(0); len(evidences) != 0 {
+		t.Fatalf("unexpected equivocations: %v", evidences)
+	}
+	evidences := make(chan *rawdb.Equivocation, 1)
+	sub := engine.SubscribeEquivocations(evidences)
+	defer sub.Unsubscribe()
+
+	// The conflicting header is detected against the canonical chain
+	if err := engine.VerifyHeader(chain, second.Header(), true); err != nil {
+		t.Fatalf("failed to verify header: %v", err)
+	}
+	select {
+	case evidence := <-evidences:
+		if evidence.Signer != addr || evidence.Number != 1 || len(evidence.Headers) != 2 ||
+			evidence.Headers[0].Hash() != first.Hash() || evidence.Headers[1].Hash() != second.Hash() {
+			t.Fatalf("equivocation mismatch: %+v", evidence)
+		}
+	default:
+		t.Fatalf("equivocation not reported")
+	}
+	// The evidence is persisted and the signer is recoverable from both headers
+	stored := engine.Equivocations(0)
+	if len(stored) != 1 {
+		t.Fatalf("equivocations mismatch: have %d, want 1", len(stored))
+	}
+	for i, header := range stored[0].Headers {
+		if signer, err := engine.Author(header); err != nil || signer != addr {
+			t.Fatalf("header %d signer mismatch: have %v, want %v, err %v", i, signer, addr, err)
+		}
+	}
+	// Verifying the same headers again does not report them twice
+	if err := engine.VerifyHeader(chain, first.Header(), true); err != nil {
+		t.Fatalf("failed to verify header: %v", err)
+	}
+	select {
+	case evidence := <-evidences:
+		t.Fatalf("equivocation reported twice: %+v", evidence)
+	default:
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package clique
+
+import (
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/consensus"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/event"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+)
+
+var equivocationMeter = metrics.NewRegisteredMeter("clique/equivocations", nil)
+
+// sealKey identifies the header sealed by a signer at a height.
+type sealKey struct {
+	number uint64
+	signer common.Address
+}
+
+// checkEquivocation records evidence if the signer of the header already sealed
+// a different header at the same height, either recently verified or canonical.
+// Clique resolves such forks like any other, but signing two blocks at one height
+// is a provable misbehavior of the signer.
+func (c *Clique) checkEquivocation(chain consensus.ChainHeaderReader, signer common.Address, header *types.Header) {
+	var (
+		number = header.Number.Uint64()
+		key    = sealKey{number: number, signer: signer}
+		other  *types.Header
+	)
+	if cached, ok := c.seals.Get(key); ok {
+		other = cached.(*types.Header)
+	} else {
+		c.seals.Add(key, header)
+		if canonical := chain.GetHeaderByNumber(number); canonical != nil {
+			if author, err := ecrecover(canonical, c.signatures); err == nil && author == signer {
+				other = canonical
+			}
+		}
+	}
+	if other == nil || other.Hash() == header.Hash() {
+		return
+	}
+	if rawdb.ReadEquivocation(c.db, number, signer) != nil {
+		return
+	}
+	evidence := &rawdb.Equivocation{
+		Signer:  signer,
+		Number:  number,
+		Headers: []*types.Header{types.CopyHeader(other), types.CopyHeader(header)},
+	}
+	rawdb.WriteEquivocation(c.db, evidence)
+	equivocationMeter.Mark(1)
+
+	log.Error("Clique signer equivocated", "signer", signer, "number", number, "first", other.Hash(), "second", header.Hash())
+	c.equivocationFeed.Send(evidence)
+}
+
+// Equivocations returns the evidence of all equivocations recorded from the
+// given height onwards.
+func (c *Clique) Equivocations(from uint64) []*rawdb.Equivocation {
+	return rawdb.ReadEquivocations(c.db, from)
+}
+
+// SubscribeEquivocations delivers the evidence of equivocations as they are
+// detected.
+func (c *Clique) SubscribeEquivocations(ch chan<- *rawdb.Equivocation) event.Subscription {
+	return c.equivocationFeed.Subscribe(ch)
+}
@@ -51,4 +51,18 @@ var (
 	// ErrUnknownL1Message is returned if a block contains an L1 message that does not
 	// match the corresponding message in the node's local database.
 	ErrUnknownL1Message = errors.New("unknown L1 message")
+
+	// ErrInvalidL1InclusionHeight is returned if a block under the L1 message
+	// inclusion rule does not commit to an L1 block height and queue index, or
+	// commits to a lower height than its parent.
+	ErrInvalidL1InclusionHeight = errors.New("invalid L1 inclusion height")
+
+	// ErrL1MessageNotIncluded is returned if a block under the L1 message inclusion
+	// rule leaves an L1 message emitted up to its L1 block height unprocessed.
+	ErrL1MessageNotIncluded = errors.New("L1 message not included")
+
+	// ErrSignerUnavailable is returned when sealing a block fails because the
+	// signer did not produce a valid signature in time. Sealing may be retried
+	// once the signer recovers.
+	ErrSignerUnavailable = errors.New("block signer unavailable")
 )
@@ -0,0 +1,23 @@
+package misc
+
+import (
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
+)
+
+// ApplyFeynmanHardFork modifies the state database according to the Feynman hard-fork rules,
+// marking in the L1GasPriceOracle contract that the L1 data fee is charged for the
+// estimated compressed size of transactions.
+//
+// Unlike Curie, Feynman does not upgrade the bytecode of the contract. Its getL1Fee
+// and getL1GasUsed views keep following the Curie fee model, so from Feynman on
+// they are deprecated and no longer match the fee charged by the node. Use the
+// scroll_estimateL1DataFee RPC method instead.
+func ApplyFeynmanHardFork(statedb *state.StateDB) {
+	log.Info("Applying Feynman hard fork")
+
+	// initialize new storage slots
+	statedb.SetState(rcfg.L1GasPriceOracleAddress, rcfg.IsFeynmanSlot, common.BytesToHash([]byte{1}))
+}
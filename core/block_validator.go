@@ -119,8 +119,9 @@ func (v *BlockValidator) ValidateL1Messages(block *types.Block) error {
 		validateL1MessagesTimer.Update(time.Since(t0))
 	}(time.Now())
 
-	// skip DB read if the block contains no L1 messages
-	if !block.ContainsL1Messages() {
+	// skip DB read if the block contains no L1 messages and need not include any
+	inclusionRule := v.config.Scroll.IsL1MessageInclusion(block.Number())
+	if !block.ContainsL1Messages() && !inclusionRule {
 		return nil
 	}
 
@@ -197,10 +198,11 @@ func (v *BlockValidator) ValidateL1Messages(block *types.Block) error {
 		}
 	}
 
-	// TODO: consider adding a rule to enforce L1Config.NumL1MessagesPerBlock.
-	// If there are L1 messages available, sequencer nodes should include them.
-	// However, this is hard to enforce as different nodes might have different views of L1.
-
+	// Different nodes might have different views of L1, so inclusion is only
+	// enforced up to the L1 block height committed to by the sequencer.
+	if inclusionRule {
+		return v.validateL1Inclusion(block, queueIndex)
+	}
 	return nil
 }
 
@@ -45,6 +45,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/metrics"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/fees"
 	"github.com/scroll-tech/go-ethereum/trie"
 )
 
@@ -1189,7 +1190,7 @@ func (bc *BlockChain) writeBlockWithoutState(block *types.Block, td *big.Int) (e
 		numProcessed := uint64(block.NumL1MessagesProcessed(*queueIndex))
 		// do not overwrite the index written by the miner worker
 		if index := rawdb.ReadFirstQueueIndexNotInL2Block(bc.db, block.Hash()); index == nil {
-			newIndex := *queueIndex + numProcessed
+			newIndex := bc.firstQueueIndexNotInBlock(block, *queueIndex)
 			log.Trace(
 				"Blockchain.writeBlockWithoutState WriteFirstQueueIndexNotInL2Block",
 				"number", block.Number(),
@@ -1278,6 +1279,7 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	rawdb.WriteBlock(blockBatch, block)
 	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
 	rawdb.WritePreimages(blockBatch, state.Preimages())
+	rawdb.WriteL1FeeParams(blockBatch, block.Hash(), fees.ReadL1FeeParams(state))
 
 	queueIndex := rawdb.ReadFirstQueueIndexNotInL2Block(bc.db, block.ParentHash())
 	if queueIndex == nil {
@@ -1288,7 +1290,7 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	numProcessed := uint64(block.NumL1MessagesProcessed(*queueIndex))
 	// do not overwrite the index written by the miner worker
 	if index := rawdb.ReadFirstQueueIndexNotInL2Block(bc.db, block.Hash()); index == nil {
-		newIndex := *queueIndex + numProcessed
+		newIndex := bc.firstQueueIndexNotInBlock(block, *queueIndex)
 		log.Trace(
 			"Blockchain.writeBlockWithState WriteFirstQueueIndexNotInL2Block",
 			"number", block.Number(),
@@ -3790,3 +3790,73 @@ func TestCurieTransition(t *testing.T) {
 		}
 	}
 }
+
+func TestL1FeeParamsPersisted(t *testing.T) {
+	// (we make a deep copy to avoid interference with other tests)
+	var config *params.ChainConfig
+	b, _ := json.Marshal(params.AllEthashProtocolChanges)
+	json.Unmarshal(b, &config)
+	config.CurieBlock = big.NewInt(2)
+	config.DarwinTime = nil
+	config.DarwinV2Time = nil
+
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		gspec   = &Genesis{Config: config}
+		genesis = gspec.MustCommit(db)
+	)
+	blockchain, _ := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 2, nil)
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+
+	// The genesis is committed without going through the block processing
+	assert.Nil(t, rawdb.ReadL1FeeParams(db, genesis.Hash()))
+
+	// The Curie fork initializes the blob base fee and scalars in its block
+	for i, block := range blocks {
+		params := rawdb.ReadL1FeeParams(db, block.Hash())
+		if params == nil {
+			t.Fatalf("block %d: missing L1 fee params", block.NumberU64())
+		}
+		statedb, _ := state.New(block.Root(), state.NewDatabase(db), nil)
+		want := statedb.GetState(rcfg.L1GasPriceOracleAddress, rcfg.L1BlobBaseFeeSlot).Big()
+		assert.Zero(t, want.Cmp(params.L1BlobBaseFee))
+		assert.Equal(t, i > 0, params.L1BlobBaseFee.Sign() > 0)
+	}
+}
+
+func TestFeynmanTransition(t *testing.T) {
+	// (we make a deep copy to avoid interference with other tests)
+	var config *params.ChainConfig
+	b, _ := json.Marshal(params.AllEthashProtocolChanges)
+	json.Unmarshal(b, &config)
+	config.CurieBlock = big.NewInt(1)
+	config.FeynmanBlock = big.NewInt(3)
+	config.DarwinTime = nil
+	config.DarwinV2Time = nil
+
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		gspec   = &Genesis{Config: config}
+		genesis = gspec.MustCommit(db)
+	)
+	blockchain, _ := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 4, nil)
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, block := range blocks {
+		statedb, _ := state.New(block.Root(), state.NewDatabase(db), nil)
+		isFeynman := statedb.GetState(rcfg.L1GasPriceOracleAddress, rcfg.IsFeynmanSlot)
+		if block.NumberU64() < config.FeynmanBlock.Uint64() {
+			assert.Equal(t, common.Hash{}, isFeynman)
+		} else {
+			assert.Equal(t, common.BytesToHash([]byte{1}), isFeynman)
+		}
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/rollup/fees"
+	"github.com/scroll-tech/go-ethereum/trie"
 )
 
 // BlockGen creates blocks for testing.
@@ -243,6 +244,9 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		if config.CurieBlock != nil && config.CurieBlock.Cmp(b.header.Number) == 0 {
 			misc.ApplyCurieHardFork(statedb)
 		}
+		if config.FeynmanBlock != nil && config.FeynmanBlock.Cmp(b.header.Number) == 0 {
+			misc.ApplyFeynmanHardFork(statedb)
+		}
 		// Execute any user modifications to the block
 		if gen != nil {
 			gen(i, b)
@@ -264,7 +268,7 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		return nil, nil
 	}
 	for i := 0; i < n; i++ {
-		statedb, err := state.New(parent.Root(), state.NewDatabase(db), nil)
+		statedb, err := state.New(parent.Root(), state.NewDatabaseWithConfig(db, &trie.Config{Zktrie: config.Scroll.ZktrieEnabled()}), nil)
 		if err != nil {
 			panic(err)
 		}
@@ -103,4 +103,16 @@ var (
 
 	// ErrSenderNoEOA is returned if the sender of a transaction is a contract.
 	ErrSenderNoEOA = errors.New("sender not an eoa")
+
+	// ErrConditionNotMet is returned if the preconditions of a conditional
+	// transaction do not hold.
+	ErrConditionNotMet = errors.New("transaction conditions not met")
+
+	// ErrSequencerMaintenance is returned if a transaction is submitted while
+	// the sequencer is in maintenance.
+	ErrSequencerMaintenance = errors.New("sequencer in maintenance")
+
+	// ErrPrivateTxUnsupported is returned if a private transaction is submitted
+	// to a node that is neither the sequencer nor forwarding them to it.
+	ErrPrivateTxUnsupported = errors.New("private transactions not supported by this node")
 )
@@ -18,6 +18,7 @@ package core
 
 import (
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
 )
 
@@ -44,3 +45,15 @@ type ChainHeadEvent struct{ Block *types.Block }
 
 // NewL1MsgsEvent is posted when we receive some new messages from L1.
 type NewL1MsgsEvent struct{ Count int }
+
+// PendingBlockEvent is posted while the sequencer builds a block: once for every
+// transaction appended to it, and once more when the block is sealed or aborted.
+type PendingBlockEvent struct {
+	Header    *types.Header                         // Header of the block, incomplete until sealed
+	Index     int                                   // Index of the appended transaction
+	Tx        *types.Transaction                    // Appended transaction, nil once sealed or aborted
+	Receipt   *types.Receipt                        // Receipt of the appended transaction
+	StateDiff map[common.Address]*state.AccountDiff // Accounts modified by the appended transaction
+	Hash      common.Hash                           // Hash of the sealed block
+	Aborted   bool                                  // Whether the block was abandoned
+}
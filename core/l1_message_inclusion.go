@@ -0,0 +1,192 @@
+package core
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/consensus"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// L1InclusionDataLength is the length of the L1 inclusion data committed to at
+// the start of the extra data of headers under the L1 message inclusion rule:
+// an L1 block height followed by the first L1 message queue index left
+// unprocessed by the chain up to the header.
+const L1InclusionDataLength = 16
+
+// l1BlockTime is the shortest time between two L1 blocks.
+const l1BlockTime = 12 * time.Second
+
+// L1InclusionHeight returns the L1 block height the header commits to under the
+// L1 message inclusion rule.
+func L1InclusionHeight(header *types.Header) (uint64, error) {
+	if len(header.Extra) < L1InclusionDataLength {
+		return 0, consensus.ErrInvalidL1InclusionHeight
+	}
+	return binary.BigEndian.Uint64(header.Extra[:8]), nil
+}
+
+// L1InclusionQueueIndex returns the first L1 message queue index the header
+// commits to leave unprocessed under the L1 message inclusion rule. Unlike the
+// included messages, it accounts for the messages skipped at the end of the block.
+func L1InclusionQueueIndex(header *types.Header) (uint64, error) {
+	if len(header.Extra) < L1InclusionDataLength {
+		return 0, consensus.ErrInvalidL1InclusionHeight
+	}
+	return binary.BigEndian.Uint64(header.Extra[8:L1InclusionDataLength]), nil
+}
+
+// SetL1Inclusion commits the header to an L1 block height and to the first L1
+// message queue index left unprocessed. The extra data must start with
+// L1InclusionDataLength bytes reserved for them.
+func SetL1Inclusion(header *types.Header, height uint64, queueIndex uint64) {
+	binary.BigEndian.PutUint64(header.Extra[:8], height)
+	binary.BigEndian.PutUint64(header.Extra[8:L1InclusionDataLength], queueIndex)
+}
+
+// MaxL1InclusionHeight returns the highest L1 block height a block can commit to
+// if nextQueueIndex is the first L1 message left unprocessed after it, given the
+// committed height of its parent. Heights beyond the local L1 sync progress
+// cannot be verified and are never returned.
+func MaxL1InclusionHeight(db ethdb.Reader, parentHeight uint64, nextQueueIndex uint64) uint64 {
+	height := parentHeight
+	if synced := rawdb.ReadSyncedL1BlockNumber(db); synced != nil && *synced > height {
+		height = *synced
+	}
+	if l1Block := rawdb.ReadL1MessageBlockNumber(db, nextQueueIndex); l1Block != nil {
+		if *l1Block <= height {
+			height = *l1Block - 1
+		}
+	} else if rawdb.ReadL1MessageRLP(db, nextQueueIndex) != nil {
+		// The L1 block of the message is not backfilled yet
+		height = parentHeight
+	}
+	if height < parentHeight {
+		height = parentHeight
+	}
+	return height
+}
+
+// L1InclusionLagging reports whether a block committing to the given L1 height
+// lags more than maxLag blocks behind the local L1 head. The head is moved back
+// by the L1 blocks that could have been built since the block, so that blocks
+// imported late are not held against a newer L1 head. As the L1 head differs per
+// node, it only serves to warn and never decides the validity of a block.
+func L1InclusionLagging(height, synced, maxLag uint64, blockTime uint64, now time.Time) bool {
+	if maxLag == 0 {
+		return false
+	}
+	var elapsed uint64
+	if age := now.Sub(time.Unix(int64(blockTime), 0)); age > 0 {
+		elapsed = uint64(age / l1BlockTime)
+	}
+	return synced > height+maxLag+elapsed
+}
+
+// firstQueueIndexNotInBlock returns the first L1 message queue index not
+// processed by the chain up to the given block, given the one of its parent.
+// Blocks under the L1 message inclusion rule commit to it, so that the messages
+// skipped at their end are known to every node.
+func (bc *BlockChain) firstQueueIndexNotInBlock(block *types.Block, parentIndex uint64) uint64 {
+	if bc.chainConfig.Scroll.IsL1MessageInclusion(block.Number()) {
+		if index, err := L1InclusionQueueIndex(block.Header()); err == nil {
+			return index
+		}
+	}
+	return parentIndex + uint64(block.NumL1MessagesProcessed(parentIndex))
+}
+
+// VerifyL1Inclusion checks the L1 inclusion data a block under the L1 message
+// inclusion rule commits to, the way its validation does, without recording
+// the skipped messages. The sequencer uses it to never seal a block rejected
+// by the other nodes.
+func (bc *BlockChain) VerifyL1Inclusion(block *types.Block) error {
+	parentIndex := rawdb.ReadFirstQueueIndexNotInL2Block(bc.db, block.ParentHash())
+	if parentIndex == nil {
+		return consensus.ErrMissingL1MessageData
+	}
+	return bc.verifyL1Inclusion(block.Header(), *parentIndex+uint64(block.NumL1MessagesProcessed(*parentIndex)))
+}
+
+// verifyL1Inclusion checks that a header under the L1 message inclusion rule
+// leaves no L1 message emitted up to its committed L1 block height unprocessed,
+// given the queue index following the messages included in its block. The
+// messages between that index and the committed one are skipped by the block.
+// Only chain data and the L1 messages are used, so that every node reaches the
+// same verdict once synced up to the committed height.
+func (bc *BlockChain) verifyL1Inclusion(header *types.Header, processed uint64) error {
+	height, err := L1InclusionHeight(header)
+	if err != nil {
+		return err
+	}
+	next, err := L1InclusionQueueIndex(header)
+	if err != nil {
+		return err
+	}
+	// The genesis block commits to no height, even with the rule enabled from it
+	if parent := bc.GetHeader(header.ParentHash, header.Number.Uint64()-1); parent != nil && parent.Number.Sign() > 0 && bc.chainConfig.Scroll.IsL1MessageInclusion(parent.Number) {
+		parentHeight, err := L1InclusionHeight(parent)
+		if err != nil {
+			return err
+		}
+		if height < parentHeight {
+			return fmt.Errorf("%w: %d below parent %d", consensus.ErrInvalidL1InclusionHeight, height, parentHeight)
+		}
+	}
+	if next < processed {
+		return fmt.Errorf("%w: committed queue index %d below processed %d", consensus.ErrInvalidL1MessageOrder, next, processed)
+	}
+	// All the messages up to the committed height must be known locally, as
+	// well as the ones skipped at the end of the block
+	synced := rawdb.ReadSyncedL1BlockNumber(bc.db)
+	if synced == nil || *synced < height {
+		return consensus.ErrMissingL1MessageData
+	}
+	if next > processed && rawdb.ReadL1MessageRLP(bc.db, next-1) == nil {
+		return consensus.ErrMissingL1MessageData
+	}
+	l1Block := rawdb.ReadL1MessageBlockNumber(bc.db, next)
+	if l1Block == nil && rawdb.ReadL1MessageRLP(bc.db, next) != nil {
+		// The message was synced by an older version, wait for its L1 block
+		// to be backfilled by the sync service
+		return consensus.ErrMissingL1MessageData
+	}
+	if l1Block != nil && *l1Block <= height {
+		return fmt.Errorf("%w: queue index %d from L1 block %d, committed height %d", consensus.ErrL1MessageNotIncluded, next, *l1Block, height)
+	}
+	return nil
+}
+
+// validateL1Inclusion checks the L1 inclusion data of a block under the L1
+// message inclusion rule, given the queue index following the messages it
+// includes, and records the messages skipped at the end of the block.
+func (v *BlockValidator) validateL1Inclusion(block *types.Block, processed uint64) error {
+	if err := v.bc.verifyL1Inclusion(block.Header(), processed); err != nil {
+		return err
+	}
+	next, _ := L1InclusionQueueIndex(block.Header())
+	if next == processed {
+		return nil
+	}
+	blockHash := block.Hash()
+	it := rawdb.IterateL1MessagesFrom(v.bc.db, processed)
+	defer it.Release()
+
+	for index := processed; index < next; index++ {
+		if !it.Next() {
+			if err := it.Error(); err != nil {
+				log.Error("Unexpected DB error in ValidateL1Messages", "err", err, "queueIndex", index)
+			}
+			return consensus.ErrMissingL1MessageData
+		}
+		l1msg := it.L1Message()
+		skippedTx := types.NewTx(&l1msg)
+		log.Debug("Skipped L1 message", "queueIndex", index, "tx", skippedTx.Hash().String(), "block", blockHash.String())
+		rawdb.WriteSkippedTransaction(v.bc.db, skippedTx, nil, "unknown", block.NumberU64(), &blockHash)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/consensus"
+	"github.com/scroll-tech/go-ethereum/consensus/ethash"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/core/vm"
+	"github.com/scroll-tech/go-ethereum/params"
+)
+
+func TestL1InclusionHeight(t *testing.T) {
+	header := &types.Header{Extra: []byte{1, 2, 3}}
+	if _, err := L1InclusionHeight(header); !errors.Is(err, consensus.ErrInvalidL1InclusionHeight) {
+		t.Fatalf("short extra: have %v, want %v", err, consensus.ErrInvalidL1InclusionHeight)
+	}
+	if _, err := L1InclusionQueueIndex(header); !errors.Is(err, consensus.ErrInvalidL1InclusionHeight) {
+		t.Fatalf("short extra: have %v, want %v", err, consensus.ErrInvalidL1InclusionHeight)
+	}
+	header.Extra = append(make([]byte, L1InclusionDataLength), "vanity"...)
+	SetL1Inclusion(header, 1234, 56)
+	if height, err := L1InclusionHeight(header); err != nil || height != 1234 {
+		t.Fatalf("height mismatch: have %d (%v), want 1234", height, err)
+	}
+	if index, err := L1InclusionQueueIndex(header); err != nil || index != 56 {
+		t.Fatalf("queue index mismatch: have %d (%v), want 56", index, err)
+	}
+	if string(header.Extra[L1InclusionDataLength:]) != "vanity" {
+		t.Fatalf("vanity overwritten: %x", header.Extra)
+	}
+}
+
+func TestMaxL1InclusionHeight(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+
+	// Nothing synced yet, the parent height is kept
+	if height := MaxL1InclusionHeight(db, 5, 0); height != 5 {
+		t.Fatalf("no sync: have %d, want 5", height)
+	}
+	rawdb.WriteSyncedL1BlockNumber(db, 100)
+	rawdb.WriteL1MessageBlockNumber(db, 0, 10)
+	rawdb.WriteL1MessageBlockNumber(db, 1, 10)
+	rawdb.WriteL1MessageBlockNumber(db, 2, 20)
+	rawdb.WriteL1Message(db, types.L1MessageTx{QueueIndex: 3})
+
+	tests := []struct {
+		parent, next, want uint64
+	}{
+		{0, 0, 9},     // first message unprocessed
+		{0, 2, 19},    // messages of block 10 processed
+		{0, 3, 0},     // L1 block of the message not backfilled
+		{0, 4, 100},   // all synced messages processed
+		{15, 1, 15},   // never below the parent
+		{150, 4, 150}, // parent ahead of the local sync
+	}
+	for i, tt := range tests {
+		if height := MaxL1InclusionHeight(db, tt.parent, tt.next); height != tt.want {
+			t.Errorf("test %d: have %d, want %d", i, height, tt.want)
+		}
+	}
+}
+
+func TestL1InclusionLagging(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tests := []struct {
+		height, synced, maxLag, blockTime uint64
+		want                              bool
+	}{
+		{10, 100, 0, 1000, false},  // unbounded
+		{90, 100, 10, 1000, false}, // within the lag
+		{89, 100, 10, 1000, true},  // beyond the lag
+		{89, 100, 10, 988, false},  // one L1 block may have been built since
+		{88, 100, 10, 988, true},   // but not two
+		{89, 100, 10, 1100, true},  // blocks from the future are not excused
+	}
+	for i, tt := range tests {
+		if lagging := L1InclusionLagging(tt.height, tt.synced, tt.maxLag, tt.blockTime, now); lagging != tt.want {
+			t.Errorf("test %d: have %v, want %v", i, lagging, tt.want)
+		}
+	}
+}
+
+// Tests that blocks under the L1 message inclusion rule must process the messages
+// emitted up to their committed L1 height, and that the messages skipped at the
+// end of a block are counted through its committed queue index.
+func TestL1MessageInclusionRule(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		engine = ethash.NewFaker()
+	)
+	config := *params.AllEthashProtocolChanges
+	l1Config := *config.Scroll.L1Config
+	l1Config.NumL1MessagesPerBlock = 2
+	l1Config.L1MessageInclusionBlock = new(uint64)
+	config.Scroll.L1Config = &l1Config
+
+	genesis := (&Genesis{Config: &config, BaseFee: big.NewInt(params.InitialBaseFee)}).MustCommit(db)
+
+	msgs := []types.L1MessageTx{
+		{QueueIndex: 0, Gas: 21016, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}},
+		{QueueIndex: 1, Gas: 21016, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}},
+		{QueueIndex: 2, Gas: 21016, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}},
+	}
+	rawdb.WriteL1Messages(db, msgs)
+	rawdb.WriteL1MessageBlockNumber(db, 0, 10)
+	rawdb.WriteL1MessageBlockNumber(db, 1, 20)
+	rawdb.WriteSyncedL1BlockNumber(db, 25)
+
+	blockchain, _ := NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+
+	generate := func(parent *types.Block, height, next uint64, txs ...types.L1MessageTx) *types.Block {
+		blocks, _ := GenerateChain(&config, parent, engine, db, 1, func(_ int, b *BlockGen) {
+			extra := make([]byte, L1InclusionDataLength)
+			binary.BigEndian.PutUint64(extra, height)
+			binary.BigEndian.PutUint64(extra[8:], next)
+			b.SetExtra(extra)
+			for i := range txs {
+				b.AddTxWithChain(blockchain, types.NewTx(&txs[i]))
+			}
+		})
+		return blocks[0]
+	}
+	// Leaving a message emitted up to the committed height is rejected
+	if _, err := blockchain.InsertChain(types.Blocks{generate(genesis, 15, 0)}); !errors.Is(err, consensus.ErrL1MessageNotIncluded) {
+		t.Fatalf("unprocessed message error mismatch: have %v, want %v", err, consensus.ErrL1MessageNotIncluded)
+	}
+	// Processing it is accepted
+	block := generate(genesis, 15, 1, msgs[0])
+	if _, err := blockchain.InsertChain(types.Blocks{block}); err != nil {
+		t.Fatalf("failed to insert block: %v", err)
+	}
+	// The committed height cannot go backwards
+	if _, err := blockchain.InsertChain(types.Blocks{generate(block, 14, 1)}); !errors.Is(err, consensus.ErrInvalidL1InclusionHeight) {
+		t.Fatalf("decreasing height error mismatch: have %v, want %v", err, consensus.ErrInvalidL1InclusionHeight)
+	}
+	// The committed queue index cannot precede the included messages
+	if _, err := blockchain.InsertChain(types.Blocks{generate(block, 15, 1, msgs[1])}); !errors.Is(err, consensus.ErrInvalidL1MessageOrder) {
+		t.Fatalf("decreasing queue index error mismatch: have %v, want %v", err, consensus.ErrInvalidL1MessageOrder)
+	}
+	// A block skipping the next message at its end waits for the L1 block of
+	// the one after it to be backfilled
+	skipped := generate(block, 25, 2)
+	if _, err := blockchain.InsertChain(types.Blocks{skipped}); err != nil {
+		t.Fatalf("failed to queue block: %v", err)
+	}
+	if head := blockchain.CurrentBlock().Hash(); head != block.Hash() {
+		t.Fatalf("block inserted before the backfill: head %x", head)
+	}
+	rawdb.WriteL1MessageBlockNumber(db, 2, 30)
+	blockchain.procFutureBlocks()
+
+	if head := blockchain.CurrentBlock().Hash(); head != skipped.Hash() {
+		t.Fatalf("block not inserted after the backfill: head %x, want %x", head, skipped.Hash())
+	}
+	if index := rawdb.ReadFirstQueueIndexNotInL2Block(db, skipped.Hash()); index == nil || *index != 2 {
+		t.Fatalf("first queue index not in block mismatch: have %v, want 2", index)
+	}
+	if rawdb.ReadSkippedTransaction(db, types.NewTx(&msgs[1]).Hash()) == nil {
+		t.Fatalf("skipped message not recorded")
+	}
+	// The skipped message cannot be included afterwards
+	if _, err := blockchain.InsertChain(types.Blocks{generate(skipped, 25, 2, msgs[1])}); !errors.Is(err, consensus.ErrInvalidL1MessageOrder) {
+		t.Fatalf("skipped message inclusion error mismatch: have %v, want %v", err, consensus.ErrInvalidL1MessageOrder)
+	}
+}
@@ -0,0 +1,69 @@
+package rawdb
+
+import (
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+// Equivocation is the evidence that a clique signer sealed two different headers
+// at the same height. Both headers carry the signature of the signer.
+type Equivocation struct {
+	Signer  common.Address
+	Number  uint64
+	Headers []*types.Header
+}
+
+// WriteEquivocation stores the evidence of an equivocation, replacing any other
+// evidence of the same signer at the same height.
+func WriteEquivocation(db ethdb.KeyValueWriter, evidence *Equivocation) {
+	bytes, err := rlp.EncodeToBytes(evidence)
+	if err != nil {
+		log.Crit("Failed to RLP encode equivocation", "signer", evidence.Signer, "number", evidence.Number, "err", err)
+	}
+	if err := db.Put(EquivocationKey(evidence.Number, evidence.Signer), bytes); err != nil {
+		log.Crit("Failed to store equivocation", "signer", evidence.Signer, "number", evidence.Number, "err", err)
+	}
+}
+
+// ReadEquivocation retrieves the evidence of an equivocation of the signer at
+// the given height, or nil if none was recorded.
+func ReadEquivocation(db ethdb.Reader, number uint64, signer common.Address) *Equivocation {
+	data, err := db.Get(EquivocationKey(number, signer))
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("Failed to read equivocation from database", "signer", signer, "number", number, "err", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	var evidence Equivocation
+	if err := rlp.DecodeBytes(data, &evidence); err != nil {
+		log.Crit("Invalid equivocation RLP", "signer", signer, "number", number, "data", data, "err", err)
+	}
+	return &evidence
+}
+
+// ReadEquivocations retrieves the evidence of all recorded equivocations from
+// the given height onwards, ordered by height.
+func ReadEquivocations(db ethdb.Iteratee, from uint64) []*Equivocation {
+	it := db.NewIterator(equivocationPrefix, encodeBigEndian(from))
+	defer it.Release()
+
+	var evidences []*Equivocation
+	for it.Next() {
+		if len(it.Key()) != len(equivocationPrefix)+8+common.AddressLength {
+			continue
+		}
+		var evidence Equivocation
+		if err := rlp.DecodeBytes(it.Value(), &evidence); err != nil {
+			log.Crit("Invalid equivocation RLP", "key", it.Key(), "err", err)
+		}
+		evidences = append(evidences, &evidence)
+	}
+	return evidences
+}
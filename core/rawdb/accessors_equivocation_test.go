@@ -0,0 +1,42 @@
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+)
+
+func TestReadWriteEquivocation(t *testing.T) {
+	db := NewMemoryDatabase()
+	signer := common.HexToAddress("0x01")
+
+	if evidence := ReadEquivocation(db, 100, signer); evidence != nil {
+		t.Fatalf("unexpected equivocation: %v", evidence)
+	}
+	for _, number := range []uint64{200, 100} {
+		WriteEquivocation(db, &Equivocation{
+			Signer: signer,
+			Number: number,
+			Headers: []*types.Header{
+				{Number: new(big.Int).SetUint64(number), Extra: []byte{1}},
+				{Number: new(big.Int).SetUint64(number), Extra: []byte{2}},
+			},
+		})
+	}
+	evidence := ReadEquivocation(db, 100, signer)
+	if evidence == nil || evidence.Signer != signer || len(evidence.Headers) != 2 || evidence.Headers[1].Extra[0] != 2 {
+		t.Fatalf("equivocation mismatch: %+v", evidence)
+	}
+	if evidence := ReadEquivocation(db, 100, common.HexToAddress("0x02")); evidence != nil {
+		t.Fatalf("unexpected equivocation: %v", evidence)
+	}
+	// Evidences are ordered by height
+	if evidences := ReadEquivocations(db, 0); len(evidences) != 2 || evidences[0].Number != 100 || evidences[1].Number != 200 {
+		t.Fatalf("equivocations mismatch: %v", evidences)
+	}
+	if evidences := ReadEquivocations(db, 101); len(evidences) != 1 || evidences[0].Number != 200 {
+		t.Fatalf("equivocations mismatch: %v", evidences)
+	}
+}
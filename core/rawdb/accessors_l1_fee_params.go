@@ -0,0 +1,44 @@
+package rawdb
+
+import (
+	"bytes"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+// WriteL1FeeParams writes the L1GasPriceOracle parameters in the state after
+// the block to the database. These are the parameters the L1 data fees of the
+// children of the block are derived from.
+func WriteL1FeeParams(db ethdb.KeyValueWriter, l2BlockHash common.Hash, params *types.L1FeeParams) {
+	if params == nil {
+		return
+	}
+	data, err := rlp.EncodeToBytes(params)
+	if err != nil {
+		log.Crit("Failed to RLP encode L1 fee params", "err", err)
+	}
+	if err := db.Put(l1FeeParamsKey(l2BlockHash), data); err != nil {
+		log.Crit("Failed to store L1 fee params", "err", err)
+	}
+}
+
+// ReadL1FeeParams retrieves the L1GasPriceOracle parameters after the block,
+// or nil if they were not stored, e.g. for blocks imported without state.
+func ReadL1FeeParams(db ethdb.Reader, l2BlockHash common.Hash) *types.L1FeeParams {
+	data, err := db.Get(l1FeeParamsKey(l2BlockHash))
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("Failed to load L1 fee params", "l2BlockHash", l2BlockHash.String(), "err", err)
+	}
+	params := new(types.L1FeeParams)
+	if err := rlp.Decode(bytes.NewReader(data), params); err != nil {
+		log.Crit("Invalid L1 fee params RLP", "l2BlockHash", l2BlockHash.String(), "data", data, "err", err)
+	}
+	return params
+}
@@ -0,0 +1,30 @@
+package rawdb
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+)
+
+func TestReadL1FeeParams(t *testing.T) {
+	l2BlockHash := common.BigToHash(big.NewInt(10))
+	params := &types.L1FeeParams{
+		L1BaseFee:     big.NewInt(1),
+		Overhead:      big.NewInt(2),
+		Scalar:        big.NewInt(3),
+		L1BlobBaseFee: big.NewInt(4),
+		CommitScalar:  big.NewInt(5),
+		BlobScalar:    big.NewInt(6),
+	}
+	db := NewMemoryDatabase()
+	if got := ReadL1FeeParams(db, l2BlockHash); got != nil {
+		t.Fatalf("unexpected L1 fee params before write: %v", got)
+	}
+	WriteL1FeeParams(db, l2BlockHash, params)
+	if got := ReadL1FeeParams(db, l2BlockHash); got == nil || !reflect.DeepEqual(params, got) {
+		t.Fatalf("L1 fee params mismatch: want %v, got %v", params, got)
+	}
+}
@@ -107,6 +107,55 @@ func WriteL1Messages(db ethdb.KeyValueWriter, l1Msgs []types.L1MessageTx) {
 	}
 }
 
+// WriteL1MessageBlockNumber writes the number of the L1 block that emitted an L1 message.
+func WriteL1MessageBlockNumber(db ethdb.KeyValueWriter, queueIndex uint64, l1BlockNumber uint64) {
+	if err := db.Put(L1MessageBlockKey(queueIndex), encodeBigEndian(l1BlockNumber)); err != nil {
+		log.Crit("Failed to store L1 message block number", "queueIndex", queueIndex, "err", err)
+	}
+}
+
+// ReadL1MessageBlockNumber retrieves the number of the L1 block that emitted an
+// L1 message, or nil if unknown, e.g. for messages synced by older versions.
+func ReadL1MessageBlockNumber(db ethdb.Reader, queueIndex uint64) *uint64 {
+	data, err := db.Get(L1MessageBlockKey(queueIndex))
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("Failed to read L1 message block number from database", "queueIndex", queueIndex, "err", err)
+	}
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteL1MessageBlockBackfill writes the highest L1 block whose L1 messages may
+// still lack their L1 block number, as they were synced by an older version.
+func WriteL1MessageBlockBackfill(db ethdb.KeyValueWriter, l1BlockNumber uint64) {
+	if err := db.Put(l1MessageBlockBackfillKey, encodeBigEndian(l1BlockNumber)); err != nil {
+		log.Crit("Failed to update L1 message block backfill progress", "err", err)
+	}
+}
+
+// ReadL1MessageBlockBackfill retrieves the highest L1 block whose L1 messages may
+// still lack their L1 block number, or nil if the backfill was never set up.
+func ReadL1MessageBlockBackfill(db ethdb.Reader) *uint64 {
+	data, err := db.Get(l1MessageBlockBackfillKey)
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("Failed to read L1 message block backfill progress from database", "err", err)
+	}
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
 // ReadL1MessageRLP retrieves an L1 message in its raw RLP database encoding.
 func ReadL1MessageRLP(db ethdb.Reader, queueIndex uint64) rlp.RawValue {
 	data, err := db.Get(L1MessageKey(queueIndex))
@@ -0,0 +1,46 @@
+package rawdb
+
+import (
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+// SignerUpdate is a sequencer signer set announced on L1.
+type SignerUpdate struct {
+	L1Block uint64           // L1 block announcing the update
+	Signers []common.Address // Signers authorized from the L2 start block onwards
+}
+
+// WriteSignerUpdate writes the sequencer signer set authorized from the given
+// L2 block onwards, as announced on L1.
+func WriteSignerUpdate(db ethdb.KeyValueWriter, startBlock uint64, update *SignerUpdate) {
+	bytes, err := rlp.EncodeToBytes(update)
+	if err != nil {
+		log.Crit("Failed to RLP encode signer update", "startBlock", startBlock, "err", err)
+	}
+	if err := db.Put(SignerUpdateKey(startBlock), bytes); err != nil {
+		log.Crit("Failed to store signer update", "startBlock", startBlock, "err", err)
+	}
+}
+
+// ReadSignerUpdate retrieves the sequencer signer set authorized from the given
+// L2 block onwards, or nil if the signers do not change at this block.
+func ReadSignerUpdate(db ethdb.Reader, startBlock uint64) *SignerUpdate {
+	data, err := db.Get(SignerUpdateKey(startBlock))
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("Failed to read signer update from database", "startBlock", startBlock, "err", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	var update SignerUpdate
+	if err := rlp.DecodeBytes(data, &update); err != nil {
+		log.Crit("Invalid signer update RLP", "startBlock", startBlock, "data", data, "err", err)
+	}
+	return &update
+}
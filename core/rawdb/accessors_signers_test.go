@@ -0,0 +1,25 @@
+package rawdb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+)
+
+func TestReadWriteSignerUpdate(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if update := ReadSignerUpdate(db, 100); update != nil {
+		t.Fatalf("unexpected signer update: %v", update)
+	}
+	update := &SignerUpdate{L1Block: 10, Signers: []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}}
+	WriteSignerUpdate(db, 100, update)
+
+	if got := ReadSignerUpdate(db, 100); !reflect.DeepEqual(got, update) {
+		t.Fatalf("signer update mismatch: have %v, want %v", got, update)
+	}
+	if got := ReadSignerUpdate(db, 101); got != nil {
+		t.Fatalf("unexpected signer update: %v", got)
+	}
+}
@@ -0,0 +1,98 @@
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+// TxEvictionRecord is the stored record of a transaction dropped from the
+// transaction pool without being included in a block.
+type TxEvictionRecord struct {
+	Reason  string
+	Added   uint64 // Unix time in milliseconds the transaction was first seen
+	Evicted uint64 // Unix time in milliseconds the transaction was dropped
+}
+
+// WriteTxEviction stores the eviction record of a transaction, replacing any
+// previous one.
+func WriteTxEviction(db ethdb.KeyValueStore, hash common.Hash, record *TxEvictionRecord) {
+	DeleteTxEviction(db, hash)
+
+	data, err := rlp.EncodeToBytes(record)
+	if err != nil {
+		log.Crit("Failed to RLP encode tx eviction", "err", err)
+	}
+	if err := db.Put(txEvictionKey(hash), data); err != nil {
+		log.Crit("Failed to store tx eviction", "err", err)
+	}
+	if err := db.Put(txEvictionIndexKey(record.Evicted, hash), nil); err != nil {
+		log.Crit("Failed to store tx eviction index", "err", err)
+	}
+}
+
+// ReadTxEviction retrieves the eviction record of a transaction, or nil if it
+// was not evicted or the record was pruned.
+func ReadTxEviction(db ethdb.KeyValueReader, hash common.Hash) *TxEvictionRecord {
+	data, err := db.Get(txEvictionKey(hash))
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("Failed to load tx eviction", "hash", hash.String(), "err", err)
+	}
+	record := new(TxEvictionRecord)
+	if err := rlp.Decode(bytes.NewReader(data), record); err != nil {
+		log.Crit("Invalid tx eviction RLP", "hash", hash.String(), "data", data, "err", err)
+	}
+	return record
+}
+
+// DeleteTxEviction removes the eviction record of a transaction, if any.
+func DeleteTxEviction(db ethdb.KeyValueStore, hash common.Hash) {
+	record := ReadTxEviction(db, hash)
+	if record == nil {
+		return
+	}
+	if err := db.Delete(txEvictionIndexKey(record.Evicted, hash)); err != nil {
+		log.Crit("Failed to delete tx eviction index", "err", err)
+	}
+	if err := db.Delete(txEvictionKey(hash)); err != nil {
+		log.Crit("Failed to delete tx eviction", "err", err)
+	}
+}
+
+// PruneTxEvictions removes the eviction records older than the given Unix time
+// in milliseconds, and returns the number of records removed.
+func PruneTxEvictions(db ethdb.KeyValueStore, before uint64) int {
+	it := db.NewIterator(txEvictionIndexPrefix, nil)
+	defer it.Release()
+
+	batch := db.NewBatch()
+	pruned := 0
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(txEvictionIndexPrefix)+8+common.HashLength {
+			continue
+		}
+		if binary.BigEndian.Uint64(key[len(txEvictionIndexPrefix):]) >= before {
+			break
+		}
+		hash := common.BytesToHash(key[len(txEvictionIndexPrefix)+8:])
+		if err := batch.Delete(txEvictionKey(hash)); err != nil {
+			log.Crit("Failed to delete tx eviction", "err", err)
+		}
+		if err := batch.Delete(common.CopyBytes(key)); err != nil {
+			log.Crit("Failed to delete tx eviction index", "err", err)
+		}
+		pruned++
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to prune tx evictions", "err", err)
+	}
+	return pruned
+}
@@ -0,0 +1,42 @@
+package rawdb
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+)
+
+func TestTxEvictions(t *testing.T) {
+	db := NewMemoryDatabase()
+	old, recent := common.BigToHash(big.NewInt(1)), common.BigToHash(big.NewInt(2))
+
+	if got := ReadTxEviction(db, old); got != nil {
+		t.Fatalf("unexpected tx eviction before write: %v", got)
+	}
+	WriteTxEviction(db, old, &TxEvictionRecord{Reason: "replaced", Added: 1, Evicted: 100})
+	record := &TxEvictionRecord{Reason: "underpriced", Added: 2, Evicted: 200}
+	WriteTxEviction(db, recent, record)
+	if got := ReadTxEviction(db, recent); !reflect.DeepEqual(got, record) {
+		t.Fatalf("tx eviction mismatch: want %v, got %v", record, got)
+	}
+	// Evicting a transaction again replaces its record and index entry
+	WriteTxEviction(db, old, &TxEvictionRecord{Reason: "replaced", Added: 1, Evicted: 300})
+	if pruned := PruneTxEvictions(db, 250); pruned != 1 {
+		t.Fatalf("pruned tx evictions mismatch: want 1, got %d", pruned)
+	}
+	if got := ReadTxEviction(db, recent); got != nil {
+		t.Fatalf("tx eviction not pruned: %v", got)
+	}
+	if got := ReadTxEviction(db, old); got == nil || got.Evicted != 300 {
+		t.Fatalf("reevicted tx eviction mismatch: %v", got)
+	}
+	DeleteTxEviction(db, old)
+	if got := ReadTxEviction(db, old); got != nil {
+		t.Fatalf("tx eviction not deleted: %v", got)
+	}
+	if pruned := PruneTxEvictions(db, 1000); pruned != 0 {
+		t.Fatalf("stale tx eviction index entries: %d", pruned)
+	}
+}
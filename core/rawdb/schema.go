@@ -107,9 +107,17 @@ var (
 	syncedL1BlockNumberKey            = []byte("LastSyncedL1BlockNumber")
 	l1MessageLegacyPrefix             = []byte("l1")
 	l1MessagePrefix                   = []byte("L1") // l1MessagePrefix + queueIndex (uint64 big endian) -> L1MessageTx
-	firstQueueIndexNotInL2BlockPrefix = []byte("q")  // firstQueueIndexNotInL2BlockPrefix + L2 block hash -> enqueue index
+	l1MessageBlockPrefix              = []byte("mb") // l1MessageBlockPrefix + queueIndex (uint64 big endian) -> L1 block number
+	l1MessageBlockBackfillKey         = []byte("L1MessageBlockBackfill")
+	firstQueueIndexNotInL2BlockPrefix = []byte("q") // firstQueueIndexNotInL2BlockPrefix + L2 block hash -> enqueue index
 	highestSyncedQueueIndexKey        = []byte("HighestSyncedQueueIndex")
 
+	// Scroll sequencer signer set updates
+	signerUpdatePrefix = []byte("su") // signerUpdatePrefix + L2 start block (uint64 big endian) -> signers
+
+	// Clique signer equivocation evidence
+	equivocationPrefix = []byte("eq") // equivocationPrefix + block number (uint64 big endian) + signer -> equivocation
+
 	// Scroll rollup event store
 	rollupEventSyncedL1BlockNumberKey = []byte("R-LastRollupEventSyncedL1BlockNumber")
 	batchChunkRangesPrefix            = []byte("R-bcr")
@@ -121,10 +129,17 @@ var (
 	// Row consumption
 	rowConsumptionPrefix = []byte("rc") // rowConsumptionPrefix + hash -> row consumption by block
 
+	// L1 data fee parameters
+	l1FeeParamsPrefix = []byte("fp") // l1FeeParamsPrefix + hash -> L1GasPriceOracle parameters after the block
+
 	// Skipped transactions
 	numSkippedTransactionsKey    = []byte("NumberOfSkippedTransactions")
 	skippedTransactionPrefix     = []byte("skip") // skippedTransactionPrefix + tx hash -> skipped transaction
 	skippedTransactionHashPrefix = []byte("sh")   // skippedTransactionHashPrefix + index -> tx hash
+
+	// Transaction pool evictions
+	txEvictionPrefix      = []byte("xe") // txEvictionPrefix + tx hash -> eviction record
+	txEvictionIndexPrefix = []byte("xi") // txEvictionIndexPrefix + eviction time (uint64 big endian) + tx hash -> nil
 )
 
 // Use the updated "L1" prefix on all new networks
@@ -277,6 +292,21 @@ func L1MessageKey(queueIndex uint64) []byte {
 	return append(l1MessagePrefix, encodeBigEndian(queueIndex)...)
 }
 
+// L1MessageBlockKey = l1MessageBlockPrefix + queueIndex (uint64 big endian)
+func L1MessageBlockKey(queueIndex uint64) []byte {
+	return append(l1MessageBlockPrefix, encodeBigEndian(queueIndex)...)
+}
+
+// SignerUpdateKey = signerUpdatePrefix + L2 start block (uint64 big endian)
+func SignerUpdateKey(startBlock uint64) []byte {
+	return append(signerUpdatePrefix, encodeBigEndian(startBlock)...)
+}
+
+// EquivocationKey = equivocationPrefix + block number (uint64 big endian) + signer
+func EquivocationKey(number uint64, signer common.Address) []byte {
+	return append(append(equivocationPrefix, encodeBigEndian(number)...), signer.Bytes()...)
+}
+
 // FirstQueueIndexNotInL2BlockKey = firstQueueIndexNotInL2BlockPrefix + L2 block hash
 func FirstQueueIndexNotInL2BlockKey(l2BlockHash common.Hash) []byte {
 	return append(firstQueueIndexNotInL2BlockPrefix, l2BlockHash.Bytes()...)
@@ -287,6 +317,21 @@ func rowConsumptionKey(hash common.Hash) []byte {
 	return append(rowConsumptionPrefix, hash.Bytes()...)
 }
 
+// l1FeeParamsKey = l1FeeParamsPrefix + hash
+func l1FeeParamsKey(hash common.Hash) []byte {
+	return append(l1FeeParamsPrefix, hash.Bytes()...)
+}
+
+// txEvictionKey = txEvictionPrefix + hash
+func txEvictionKey(hash common.Hash) []byte {
+	return append(txEvictionPrefix, hash.Bytes()...)
+}
+
+// txEvictionIndexKey = txEvictionIndexPrefix + evicted (uint64 big endian) + hash
+func txEvictionIndexKey(evicted uint64, hash common.Hash) []byte {
+	return append(append(txEvictionIndexPrefix, encodeBlockNumber(evicted)...), hash.Bytes()...)
+}
+
 func isNotFoundErr(err error) bool {
 	return errors.Is(err, leveldb.ErrNotFound) || errors.Is(err, memorydb.ErrMemorydbNotFound)
 }
@@ -0,0 +1,234 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto/codehash"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+	"github.com/scroll-tech/go-ethereum/trie"
+)
+
+// convertCommitInterval is the number of accounts after which the state being
+// rebuilt by ConvertState is flushed to disk to bound memory usage.
+const convertCommitInterval = 10000
+
+// ConvertState rebuilds the state with the given root from the source database
+// into the destination database, which may commit to its state with a different
+// trie format (MPT or zktrie), and returns the root of the rebuilt state. Trie
+// keys are hashed differently in the two formats, so the preimages of every
+// account address and storage slot of the source state must be available. The
+// rebuilt state is checked against the source before returning.
+func ConvertState(src Database, srcRoot common.Hash, dst Database) (common.Hash, error) {
+	var (
+		accounts int
+		slots    int
+		start    = time.Now()
+		logged   = time.Now()
+	)
+	statedb, err := New(common.Hash{}, dst, nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	flush := func() (common.Hash, error) {
+		root, err := statedb.Commit(false)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if err := dst.TrieDB().Commit(root, false, nil); err != nil {
+			return common.Hash{}, err
+		}
+		statedb, err = New(root, dst, nil)
+		return root, err
+	}
+	err = iterateAccounts(src, srcRoot, func(addr common.Address, addrHash common.Hash, acc *types.StateAccount) error {
+		statedb.CreateAccount(addr)
+		statedb.SetNonce(addr, acc.Nonce)
+		statedb.SetBalance(addr, acc.Balance)
+		if !bytes.Equal(acc.KeccakCodeHash, codehash.EmptyKeccakCodeHash.Bytes()) {
+			code, err := src.ContractCode(addrHash, common.BytesToHash(acc.KeccakCodeHash))
+			if err != nil {
+				return fmt.Errorf("missing code %x of account %x: %v", acc.KeccakCodeHash, addr, err)
+			}
+			statedb.SetCode(addr, code)
+		}
+		err := iterateStorage(src, addrHash, acc.Root, func(key, value common.Hash) error {
+			statedb.SetState(addr, key, value)
+			slots++
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to convert storage of account %x: %v", addr, err)
+		}
+		accounts++
+		if accounts%convertCommitInterval == 0 {
+			if _, err := flush(); err != nil {
+				return err
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Converting state", "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		return nil
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	root, err := flush()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Converted state", "root", root, "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	if err := VerifyConvertedState(src, srcRoot, dst, root); err != nil {
+		return common.Hash{}, err
+	}
+	return root, nil
+}
+
+// VerifyConvertedState checks that two states hold exactly the same accounts,
+// contract code and storage slots, regardless of the trie format each of them
+// is committed with.
+func VerifyConvertedState(src Database, srcRoot common.Hash, dst Database, dstRoot common.Hash) error {
+	statedb, err := New(dstRoot, dst, nil)
+	if err != nil {
+		return err
+	}
+	var accounts, slots int
+	err = iterateAccounts(src, srcRoot, func(addr common.Address, addrHash common.Hash, acc *types.StateAccount) error {
+		if !statedb.Exist(addr) {
+			return fmt.Errorf("account %x missing", addr)
+		}
+		if nonce := statedb.GetNonce(addr); nonce != acc.Nonce {
+			return fmt.Errorf("account %x nonce mismatch: have %d, want %d", addr, nonce, acc.Nonce)
+		}
+		if balance := statedb.GetBalance(addr); balance.Cmp(acc.Balance) != 0 {
+			return fmt.Errorf("account %x balance mismatch: have %v, want %v", addr, balance, acc.Balance)
+		}
+		if hash := statedb.GetKeccakCodeHash(addr); !bytes.Equal(hash.Bytes(), acc.KeccakCodeHash) {
+			return fmt.Errorf("account %x keccak code hash mismatch: have %x, want %x", addr, hash, acc.KeccakCodeHash)
+		}
+		if hash := statedb.GetPoseidonCodeHash(addr); !bytes.Equal(hash.Bytes(), acc.PoseidonCodeHash) {
+			return fmt.Errorf("account %x poseidon code hash mismatch: have %x, want %x", addr, hash, acc.PoseidonCodeHash)
+		}
+		var count int
+		err := iterateStorage(src, addrHash, acc.Root, func(key, value common.Hash) error {
+			if have := statedb.GetState(addr, key); have != value {
+				return fmt.Errorf("account %x slot %x mismatch: have %x, want %x", addr, key, have, value)
+			}
+			count++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if have := countLeaves(statedb.StorageTrie(addr)); have != count {
+			return fmt.Errorf("account %x slot count mismatch: have %d, want %d", addr, have, count)
+		}
+		accounts++
+		slots += count
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if have := countLeaves(statedb.trie); have != accounts {
+		return fmt.Errorf("account count mismatch: have %d, want %d", have, accounts)
+	}
+	log.Info("Verified converted state", "root", dstRoot, "accounts", accounts, "slots", slots)
+	return nil
+}
+
+// iterateAccounts invokes fn for every account of the state with the given root.
+func iterateAccounts(db Database, root common.Hash, fn func(addr common.Address, addrHash common.Hash, acc *types.StateAccount) error) error {
+	tr, err := db.OpenTrie(root)
+	if err != nil {
+		return err
+	}
+	it := trie.NewIterator(tr.NodeIterator(nil))
+	for it.Next() {
+		acc, err := decodeStateAccount(db, it.Value)
+		if err != nil {
+			return fmt.Errorf("invalid account %x: %v", it.Key, err)
+		}
+		preimage := tr.GetKey(it.Key)
+		if preimage == nil {
+			return fmt.Errorf("missing preimage of account key %x", it.Key)
+		}
+		if err := fn(common.BytesToAddress(preimage), common.BytesToHash(it.Key), acc); err != nil {
+			return err
+		}
+	}
+	return it.Err
+}
+
+// iterateStorage invokes fn for every slot of the storage trie with the given root.
+func iterateStorage(db Database, addrHash, root common.Hash, fn func(key, value common.Hash) error) error {
+	tr, err := db.OpenStorageTrie(addrHash, root)
+	if err != nil {
+		return err
+	}
+	it := trie.NewIterator(tr.NodeIterator(nil))
+	for it.Next() {
+		value := it.Value
+		if !db.TrieDB().Zktrie {
+			if _, value, _, err = rlp.Split(it.Value); err != nil {
+				return fmt.Errorf("invalid slot %x: %v", it.Key, err)
+			}
+		}
+		preimage := tr.GetKey(it.Key)
+		if preimage == nil {
+			return fmt.Errorf("missing preimage of slot key %x", it.Key)
+		}
+		if err := fn(common.BytesToHash(preimage), common.BytesToHash(value)); err != nil {
+			return err
+		}
+	}
+	return it.Err
+}
+
+// decodeStateAccount decodes an account leaf in the trie format of the database.
+func decodeStateAccount(db Database, blob []byte) (*types.StateAccount, error) {
+	if db.TrieDB().Zktrie {
+		return types.UnmarshalStateAccount(blob)
+	}
+	acc := new(types.StateAccount)
+	if err := rlp.DecodeBytes(blob, acc); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// countLeaves returns the number of leaves in the given trie.
+func countLeaves(tr Trie) int {
+	if tr == nil {
+		return 0
+	}
+	var count int
+	it := trie.NewIterator(tr.NodeIterator(nil))
+	for it.Next() {
+		count++
+	}
+	return count
+}
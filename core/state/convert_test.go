@@ -0,0 +1,78 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/trie"
+)
+
+func TestConvertState(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	mpt := NewDatabaseWithConfig(db, &trie.Config{Preimages: true})
+	zk := NewDatabaseWithConfig(db, &trie.Config{Preimages: true, Zktrie: true})
+
+	statedb, _ := New(common.Hash{}, mpt, nil)
+	for i := byte(1); i <= 50; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		statedb.SetBalance(addr, big.NewInt(int64(i)*1000))
+		statedb.SetNonce(addr, uint64(i))
+		if i%5 == 0 {
+			statedb.SetCode(addr, []byte{i, i, i})
+			for j := byte(1); j <= i; j++ {
+				statedb.SetState(addr, common.BytesToHash([]byte{j}), common.BytesToHash([]byte{i, j}))
+			}
+		}
+	}
+	// Empty accounts must survive the conversion as well.
+	statedb.CreateAccount(common.BytesToAddress([]byte{0xff}))
+	mptRoot, err := statedb.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := mpt.TrieDB().Commit(mptRoot, false, nil); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+
+	zkRoot, err := ConvertState(mpt, mptRoot, zk)
+	if err != nil {
+		t.Fatalf("failed to convert to zktrie: %v", err)
+	}
+	if zkRoot == mptRoot {
+		t.Fatal("zktrie root equals mpt root")
+	}
+	// Converting back must reproduce the original state root exactly.
+	backRoot, err := ConvertState(zk, zkRoot, mpt)
+	if err != nil {
+		t.Fatalf("failed to convert to mpt: %v", err)
+	}
+	if backRoot != mptRoot {
+		t.Fatalf("round trip root mismatch: have %x, want %x", backRoot, mptRoot)
+	}
+
+	// Tampering with the converted state must be detected.
+	tampered, _ := New(zkRoot, zk, nil)
+	tampered.SetState(common.BytesToAddress([]byte{5}), common.BytesToHash([]byte{1}), common.Hash{})
+	tamperedRoot, _ := tampered.Commit(false)
+	if err := VerifyConvertedState(mpt, mptRoot, zk, tamperedRoot); err == nil {
+		t.Fatal("tampered state passed verification")
+	}
+}
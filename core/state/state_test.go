@@ -96,6 +96,50 @@ func TestDump(t *testing.T) {
 	}
 }
 
+func TestDumpZktrie(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	sdb, _ := New(common.Hash{}, NewDatabaseWithConfig(db, &trie.Config{Preimages: true, Zktrie: true}), nil)
+
+	addr1, addr2 := common.BytesToAddress([]byte{0x01}), common.BytesToAddress([]byte{0x02})
+	sdb.AddBalance(addr1, big.NewInt(22))
+	sdb.SetState(addr1, common.BytesToHash([]byte{0x0a}), common.BytesToHash([]byte{0x0b}))
+	sdb.SetCode(addr2, []byte{3, 3, 3})
+	root, _ := sdb.Commit(false)
+	if err := sdb.Database().TrieDB().Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+
+	sdb, _ = New(root, sdb.Database(), nil)
+	dump := sdb.RawDump(nil)
+	if len(dump.Accounts) != 2 {
+		t.Fatalf("account count mismatch: have %d, want 2", len(dump.Accounts))
+	}
+	if have := dump.Accounts[addr1].Balance; have != "22" {
+		t.Errorf("balance mismatch: have %s, want 22", have)
+	}
+	if have := dump.Accounts[addr1].Storage[common.BytesToHash([]byte{0x0a})]; have != "0b" {
+		t.Errorf("storage mismatch: have %q, want 0b", have)
+	}
+	if have := dump.Accounts[addr2].Code; !bytes.Equal(have, []byte{3, 3, 3}) {
+		t.Errorf("code mismatch: have %x", have)
+	}
+
+	// Paginated dumps must cover every account exactly once.
+	first := sdb.IteratorDump(&DumpConfig{Max: 1})
+	if first.Next == nil {
+		t.Fatal("missing next key after first page")
+	}
+	second := sdb.IteratorDump(&DumpConfig{Start: first.Next, Max: 1})
+	for addr := range first.Accounts {
+		if _, ok := second.Accounts[addr]; ok {
+			t.Errorf("account %x returned twice", addr)
+		}
+	}
+	if len(first.Accounts)+len(second.Accounts) != 2 || second.Next != nil {
+		t.Errorf("pagination mismatch: pages %d+%d, next %x", len(first.Accounts), len(second.Accounts), second.Next)
+	}
+}
+
 func TestNull(t *testing.T) {
 	s := newStateTest()
 	address := common.HexToAddress("0x823140710bf13990e4500136726d8b55")
@@ -27,6 +27,7 @@ import (
 	zkt "github.com/scroll-tech/zktrie/types"
 
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/state/snapshot"
 	"github.com/scroll-tech/go-ethereum/core/types"
@@ -100,6 +101,10 @@ type StateDB struct {
 	// Transient storage
 	transientStorage transientStorage
 
+	// Accounts and storage slots modified by the last finalised transaction,
+	// nil unless tracking is enabled with TrackTxDiffs
+	txDiff map[common.Address][]common.Hash
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        *journal
@@ -767,6 +772,12 @@ func (s *StateDB) Copy() *StateDB {
 	for hash, preimage := range s.preimages {
 		state.preimages[hash] = preimage
 	}
+	if s.txDiff != nil {
+		state.txDiff = make(map[common.Address][]common.Hash, len(s.txDiff))
+		for addr, slots := range s.txDiff {
+			state.txDiff[addr] = slots
+		}
+	}
 	// Do we need to copy the access list? In practice: No. At the start of a
 	// transaction, the access list is empty. In practice, we only ever copy state
 	// _between_ transactions/blocks, never in the middle of a transaction.
@@ -843,9 +854,21 @@ func (s *StateDB) GetRefund() uint64 {
 // the journal as well as the refunds. Finalise, however, will not push any updates
 // into the tries just yet. Only IntermediateRoot or Commit will do that.
 func (s *StateDB) Finalise(deleteEmptyObjects bool) {
+	var txDiff map[common.Address][]common.Hash
+	if s.txDiff != nil {
+		txDiff = make(map[common.Address][]common.Hash, len(s.journal.dirties))
+		defer func() { s.txDiff = txDiff }()
+	}
 	addressesToPrefetch := make([][]byte, 0, len(s.journal.dirties))
 	for addr := range s.journal.dirties {
 		obj, exist := s.stateObjects[addr]
+		if exist && s.txDiff != nil {
+			slots := make([]common.Hash, 0, len(obj.dirtyStorage))
+			for key := range obj.dirtyStorage {
+				slots = append(slots, key)
+			}
+			txDiff[addr] = slots
+		}
 		if !exist {
 			// ripeMD is 'touched' at block 1714175, in tx 0x1237f737031e40bcde4a8b7e717b2d15e3ecadfe49bb1bbc71ee9deb09c6fcf2
 			// That tx goes out of gas, and although the notion of 'touched' does not exist there, the
@@ -885,6 +908,52 @@ func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 	s.clearJournalAndRefund()
 }
 
+// TrackTxDiffs makes Finalise record the accounts and storage slots modified by
+// each transaction, to be retrieved with TxDiff.
+func (s *StateDB) TrackTxDiffs() {
+	if s.txDiff == nil {
+		s.txDiff = make(map[common.Address][]common.Hash)
+	}
+}
+
+// AccountDiff is the state of an account after a transaction modified it.
+type AccountDiff struct {
+	Balance  *hexutil.Big                `json:"balance"`
+	Nonce    hexutil.Uint64              `json:"nonce"`
+	CodeHash common.Hash                 `json:"codeHash"`
+	Storage  map[common.Hash]common.Hash `json:"storage,omitempty"` // Modified storage slots only
+	Deleted  bool                        `json:"deleted,omitempty"`
+}
+
+// TxDiff returns the current state of the accounts and storage slots modified
+// by the last finalised transaction, or nil if tracking is not enabled.
+func (s *StateDB) TxDiff() map[common.Address]*AccountDiff {
+	if s.txDiff == nil {
+		return nil
+	}
+	diff := make(map[common.Address]*AccountDiff, len(s.txDiff))
+	for addr, slots := range s.txDiff {
+		obj := s.stateObjects[addr]
+		if obj == nil || obj.deleted {
+			diff[addr] = &AccountDiff{Balance: new(hexutil.Big), Deleted: true}
+			continue
+		}
+		account := &AccountDiff{
+			Balance:  (*hexutil.Big)(new(big.Int).Set(obj.Balance())),
+			Nonce:    hexutil.Uint64(obj.Nonce()),
+			CodeHash: common.BytesToHash(obj.KeccakCodeHash()),
+		}
+		if len(slots) > 0 {
+			account.Storage = make(map[common.Hash]common.Hash, len(slots))
+			for _, key := range slots {
+				account.Storage[key] = obj.GetState(s.db, key)
+			}
+		}
+		diff[addr] = account
+	}
+	return diff
+}
+
 // IntermediateRoot computes the current root hash of the state trie.
 // It is called in between transactions to get the root hash that
 // goes into transaction receipts.
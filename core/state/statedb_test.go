@@ -960,3 +960,44 @@ func TestStateDBTransientStorage(t *testing.T) {
 		t.Fatalf("transient storage mismatch: have %x, want %x", got, value)
 	}
 }
+
+// Tests that the diffs of consecutive transactions only cover the accounts and
+// storage slots modified by each of them.
+func TestTxDiff(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if diff := state.TxDiff(); diff != nil {
+		t.Fatalf("diff tracked without being enabled: %v", diff)
+	}
+	state.TrackTxDiffs()
+
+	var (
+		a, b = common.Address{1}, common.Address{2}
+		slot = common.Hash{3}
+	)
+	state.AddBalance(a, big.NewInt(10))
+	state.SetState(a, slot, common.Hash{4})
+	state.Finalise(true)
+
+	diff := state.TxDiff()
+	if len(diff) != 1 || diff[a] == nil {
+		t.Fatalf("first diff mismatch: %v", diff)
+	}
+	if diff[a].Balance.ToInt().Int64() != 10 || diff[a].Storage[slot] != (common.Hash{4}) {
+		t.Fatalf("first diff account mismatch: %+v", diff[a])
+	}
+	// Diffs survive copies, like the candidates of the block being built
+	state.SetNonce(b, 1)
+	state.Suicide(a)
+	state.Finalise(true)
+
+	diff = state.Copy().TxDiff()
+	if len(diff) != 2 || diff[a] == nil || diff[b] == nil {
+		t.Fatalf("second diff mismatch: %v", diff)
+	}
+	if !diff[a].Deleted {
+		t.Fatalf("destructed account not deleted: %+v", diff[a])
+	}
+	if diff[b].Nonce != 1 || diff[b].Storage != nil {
+		t.Fatalf("second diff account mismatch: %+v", diff[b])
+	}
+}
@@ -90,6 +90,10 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	if p.config.CurieBlock != nil && p.config.CurieBlock.Cmp(block.Number()) == 0 {
 		misc.ApplyCurieHardFork(statedb)
 	}
+	// Apply Feynman hard fork
+	if p.config.FeynmanBlock != nil && p.config.FeynmanBlock.Cmp(block.Number()) == 0 {
+		misc.ApplyFeynmanHardFork(statedb)
+	}
 	blockContext := NewEVMBlockContext(header, p.bc, p.config, nil)
 	vmenv := vm.NewEVM(blockContext, vm.TxContext{}, statedb, p.config, cfg)
 	processorBlockTransactionGauge.Update(int64(block.Transactions().Len()))
@@ -0,0 +1,270 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package stateless re-executes blocks against the partial state contained in
+// a block trace, without access to the full chain database.
+package stateless
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	zktrie "github.com/scroll-tech/zktrie/trie"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/consensus/misc"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/core/vm"
+	"github.com/scroll-tech/go-ethereum/crypto/codehash"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
+	"github.com/scroll-tech/go-ethereum/rollup/withdrawtrie"
+	"github.com/scroll-tech/go-ethereum/trie"
+)
+
+// Result is the outcome of re-executing a block trace.
+type Result struct {
+	Root             common.Hash    // State root after the block
+	WithdrawTrieRoot common.Hash    // Withdraw trie root after the block
+	Receipts         types.Receipts // Receipts of all transactions of the block
+	GasUsed          uint64         // Total gas used by the block
+}
+
+// NewStateDB builds a partial zktrie state from the account and storage proofs,
+// the deletion proofs and the bytecodes of the trace. Accessing any part of
+// the state not covered by the witness results in a database error, which is
+// reported by StateDB.Error.
+func NewStateDB(trace *types.BlockTrace) (*state.StateDB, error) {
+	return newStateDB(trace.StorageTrace, trace.Bytecodes)
+}
+
+// NewBatchStateDB builds a partial zktrie state from the merged witness of a
+// batch, on top of which all blocks of the batch can be executed in order.
+func NewBatchStateDB(witness *types.BatchWitness) (*state.StateDB, error) {
+	return newStateDB(witness.StorageTrace, witness.Bytecodes)
+}
+
+func newStateDB(storageTrace *types.StorageTrace, bytecodes []*types.BytecodeTrace) (*state.StateDB, error) {
+	if storageTrace == nil {
+		return nil, errors.New("trace has no storage trace")
+	}
+	var (
+		diskdb = rawdb.NewMemoryDatabase()
+		sdb    = state.NewDatabaseWithConfig(diskdb, &trie.Config{Zktrie: true})
+		zkdb   = trie.NewZktrieDatabaseFromTriedb(sdb.TrieDB())
+	)
+	for addr, proof := range storageTrace.Proofs {
+		if err := writeProof(zkdb, proof); err != nil {
+			return nil, fmt.Errorf("invalid proof of account %s: %w", addr, err)
+		}
+	}
+	for addr, slots := range storageTrace.StorageProofs {
+		for slot, proof := range slots {
+			if err := writeProof(zkdb, proof); err != nil {
+				return nil, fmt.Errorf("invalid proof of account %s slot %s: %w", addr, slot, err)
+			}
+		}
+	}
+	if err := writeProof(zkdb, storageTrace.DeletionProofs); err != nil {
+		return nil, fmt.Errorf("invalid deletion proof: %w", err)
+	}
+	for _, code := range bytecodes {
+		if hash := codehash.KeccakCodeHash(code.Code); hash != code.KeccakCodeHash {
+			return nil, fmt.Errorf("code hash mismatch: have %x, want %x", hash, code.KeccakCodeHash)
+		}
+		rawdb.WriteCode(diskdb, code.KeccakCodeHash, code.Code)
+	}
+	return state.New(storageTrace.RootBefore, sdb, nil)
+}
+
+// writeProof stores the nodes of a zktrie proof in the database, keyed by their
+// hash. The magic marker terminating every proof is skipped.
+func writeProof(zkdb *trie.ZktrieDatabase, proof []hexutil.Bytes) error {
+	for _, blob := range proof {
+		if bytes.Equal(blob, zktrie.ProofMagicBytes()) {
+			continue
+		}
+		node, err := zktrie.NewNodeFromBytes(blob)
+		if err != nil {
+			return err
+		}
+		hash, err := node.NodeHash()
+		if err != nil {
+			return err
+		}
+		if err := zkdb.Put(hash[:], node.CanonicalValue()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute re-executes the block of the trace on top of the partial state built
+// from its witness and returns the resulting state root, withdraw trie root and
+// receipts. It fails if the witness does not cover all state accessed by the
+// block.
+func Execute(config *params.ChainConfig, trace *types.BlockTrace) (*Result, error) {
+	if trace.Header == nil {
+		return nil, errors.New("trace has no header")
+	}
+	if config.ChainID != nil && config.ChainID.Uint64() != trace.ChainID {
+		return nil, fmt.Errorf("chain id mismatch: have %d, want %d", trace.ChainID, config.ChainID)
+	}
+	statedb, err := NewStateDB(trace)
+	if err != nil {
+		return nil, err
+	}
+	return execute(config, statedb, trace.Header, trace.Coinbase, trace.Transactions)
+}
+
+// ExecuteBatch re-executes all blocks of a batch witness in order on top of the
+// partial state built from the merged witness, and returns the result of each
+// block. It fails if the witness does not cover all state accessed by the
+// batch.
+func ExecuteBatch(config *params.ChainConfig, witness *types.BatchWitness) ([]*Result, error) {
+	if config.ChainID != nil && config.ChainID.Uint64() != witness.ChainID {
+		return nil, fmt.Errorf("chain id mismatch: have %d, want %d", witness.ChainID, config.ChainID)
+	}
+	statedb, err := NewBatchStateDB(witness)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*Result, 0, len(witness.Blocks))
+	for _, block := range witness.Blocks {
+		if block.Header == nil {
+			return nil, errors.New("batch block has no header")
+		}
+		result, err := execute(config, statedb, block.Header, block.Coinbase, block.Transactions)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", block.Header.Number, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// execute applies the transactions of a block on statedb, which is left at the
+// state after the block.
+func execute(config *params.ChainConfig, statedb *state.StateDB, header *types.Header, coinbase *types.AccountWrapper, txs []*types.TransactionData) (*Result, error) {
+	var (
+		author  *common.Address
+		usedGas = new(uint64)
+		gp      = new(core.GasPool).AddGas(header.GasLimit)
+	)
+	if coinbase != nil {
+		author = &coinbase.Address
+	}
+	if config.CurieBlock != nil && config.CurieBlock.Cmp(header.Number) == 0 {
+		misc.ApplyCurieHardFork(statedb)
+	}
+	if config.FeynmanBlock != nil && config.FeynmanBlock.Cmp(header.Number) == 0 {
+		misc.ApplyFeynmanHardFork(statedb)
+	}
+	result := &Result{Receipts: make(types.Receipts, 0, len(txs))}
+	for i, txData := range txs {
+		tx, err := txData.ToTransaction()
+		if err != nil {
+			return nil, fmt.Errorf("could not decode tx %d: %w", i, err)
+		}
+		statedb.SetTxContext(tx.Hash(), i)
+		// No chain context is needed: the coinbase is taken from the trace and
+		// BLOCKHASH does not consult the chain on Scroll.
+		receipt, err := core.ApplyTransaction(config, nil, author, gp, statedb, header, tx, usedGas, vm.Config{})
+		if err != nil {
+			return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+		result.Receipts = append(result.Receipts, receipt)
+	}
+	result.Root = statedb.IntermediateRoot(config.IsEIP158(header.Number))
+	if err := statedb.Error(); err != nil {
+		return nil, fmt.Errorf("incomplete witness: %w", err)
+	}
+	result.WithdrawTrieRoot = withdrawtrie.ReadWTRSlot(rcfg.L2MessageQueueAddress, statedb)
+	result.GasUsed = *usedGas
+	return result, nil
+}
+
+// Verify re-executes the block of the trace and checks the resulting state
+// root, withdraw trie root and receipts against the ones recorded in the trace
+// and its header.
+func Verify(config *params.ChainConfig, trace *types.BlockTrace) (*Result, error) {
+	result, err := Execute(config, trace)
+	if err != nil {
+		return nil, err
+	}
+	return result, verify(result, trace.Header, trace.ExecutionResults, trace.StorageTrace.RootAfter, trace.WithdrawTrieRoot)
+}
+
+// VerifyBatch re-executes all blocks of a batch witness and checks the result
+// of each block against its execution results and header, and the final state
+// root against the one recorded in the witness.
+func VerifyBatch(config *params.ChainConfig, witness *types.BatchWitness) ([]*Result, error) {
+	results, err := ExecuteBatch(config, witness)
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range results {
+		block := witness.Blocks[i]
+		if err := verify(result, block.Header, block.ExecutionResults, block.Header.Root, block.WithdrawTrieRoot); err != nil {
+			return results, fmt.Errorf("block %d: %w", block.Header.Number, err)
+		}
+	}
+	if n := len(results); n > 0 && results[n-1].Root != witness.StorageTrace.RootAfter {
+		return results, fmt.Errorf("invalid merkle root (remote: %x local: %x)", witness.StorageTrace.RootAfter, results[n-1].Root)
+	}
+	return results, nil
+}
+
+// verify checks the result of re-executing a block against its header and the
+// expected execution results, state root and withdraw trie root.
+func verify(result *Result, header *types.Header, executionResults []*types.ExecutionResult, root, withdrawTrieRoot common.Hash) error {
+	if len(executionResults) != len(result.Receipts) {
+		return fmt.Errorf("execution result count mismatch: have %d, want %d", len(result.Receipts), len(executionResults))
+	}
+	for i, receipt := range result.Receipts {
+		want := executionResults[i]
+		if receipt.GasUsed != want.Gas {
+			return fmt.Errorf("tx %d gas used mismatch: have %d, want %d", i, receipt.GasUsed, want.Gas)
+		}
+		if failed := receipt.Status == types.ReceiptStatusFailed; failed != want.Failed {
+			return fmt.Errorf("tx %d status mismatch: have failed=%v, want failed=%v", i, failed, want.Failed)
+		}
+		if want.L1DataFee != nil && (receipt.L1Fee == nil || receipt.L1Fee.Cmp(want.L1DataFee.ToInt()) != 0) {
+			return fmt.Errorf("tx %d l1 data fee mismatch: have %v, want %v", i, receipt.L1Fee, want.L1DataFee.ToInt())
+		}
+	}
+	if result.GasUsed != header.GasUsed {
+		return fmt.Errorf("invalid gas used (remote: %d local: %d)", header.GasUsed, result.GasUsed)
+	}
+	if bloom := types.CreateBloom(result.Receipts); bloom != header.Bloom {
+		return fmt.Errorf("invalid bloom (remote: %x  local: %x)", header.Bloom, bloom)
+	}
+	if receiptSha := types.DeriveSha(result.Receipts, trie.NewStackTrie(nil)); receiptSha != header.ReceiptHash {
+		return fmt.Errorf("invalid receipt root hash (remote: %x local: %x)", header.ReceiptHash, receiptSha)
+	}
+	if result.Root != root {
+		return fmt.Errorf("invalid merkle root (remote: %x local: %x)", root, result.Root)
+	}
+	if result.WithdrawTrieRoot != withdrawTrieRoot {
+		return fmt.Errorf("invalid withdraw trie root (remote: %x local: %x)", withdrawTrieRoot, result.WithdrawTrieRoot)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package stateless
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/consensus/clique"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/core/vm"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/tracing"
+)
+
+func TestVerifyBlockTrace(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0xc0de")
+		config   = *params.TestChainConfig
+	)
+	config.Ethash = nil
+	config.Clique = &params.CliqueConfig{Period: 1, Epoch: 30000}
+	config.Scroll.UseZktrie = true
+
+	db := rawdb.NewMemoryDatabase()
+	engine := clique.New(config.Clique, db)
+	gspec := &core.Genesis{
+		Config:    &config,
+		ExtraData: make([]byte, 32+common.AddressLength+crypto.SignatureLength),
+		Alloc: core.GenesisAlloc{
+			addr: {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))},
+			// PUSH1 0x2a PUSH1 0x01 SSTORE
+			contract: {Balance: common.Big0, Code: common.FromHex("0x602a600155"), Storage: map[common.Hash]common.Hash{
+				common.BytesToHash([]byte{2}): common.BytesToHash([]byte{2}),
+			}},
+		},
+	}
+	copy(gspec.ExtraData[32:], addr[:])
+	genesis := gspec.MustCommit(db)
+	chain, err := core.NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	signer := types.LatestSigner(&config)
+	blocks, _ := core.GenerateChain(&config, genesis, engine, db, 1, func(i int, block *core.BlockGen) {
+		block.SetDifficulty(big.NewInt(2))
+		tx, _ := types.SignTx(types.NewTransaction(block.TxNonce(addr), common.Address{1}, big.NewInt(1000), params.TxGas, block.BaseFee(), nil), signer, key)
+		block.AddTx(tx)
+		tx, _ = types.SignTx(types.NewTransaction(block.TxNonce(addr), contract, common.Big0, 100000, block.BaseFee(), nil), signer, key)
+		block.AddTx(tx)
+	})
+	header := blocks[0].Header()
+	header.Extra = make([]byte, 32+crypto.SignatureLength)
+	sig, _ := crypto.Sign(clique.SealHash(header).Bytes(), key)
+	copy(header.Extra[32:], sig)
+	blocks[0] = blocks[0].WithSeal(header)
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	statedb, err := chain.StateAt(genesis.Root())
+	if err != nil {
+		t.Fatalf("failed to open state: %v", err)
+	}
+	trace, err := tracing.NewTracerWrapper().CreateTraceEnvAndGetBlockTrace(&config, chain, chain.Engine(), db, statedb, genesis, blocks[0], true)
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+
+	result, err := Verify(&config, trace)
+	if err != nil {
+		t.Fatalf("failed to verify trace: %v", err)
+	}
+	if result.Root != blocks[0].Root() {
+		t.Fatalf("state root mismatch: have %x, want %x", result.Root, blocks[0].Root())
+	}
+	if len(result.Receipts) != 2 {
+		t.Fatalf("receipt count mismatch: have %d, want 2", len(result.Receipts))
+	}
+
+	// Dropping the account proofs or a touched contract code must be detected.
+	incomplete := *trace
+	incomplete.StorageTrace = new(types.StorageTrace)
+	*incomplete.StorageTrace = *trace.StorageTrace
+	incomplete.StorageTrace.Proofs = make(map[string][]hexutil.Bytes)
+	if _, err := Verify(&config, &incomplete); err == nil {
+		t.Fatal("trace without account proofs passed verification")
+	}
+	incomplete = *trace
+	incomplete.Bytecodes = nil
+	for _, code := range trace.Bytecodes {
+		if code.KeccakCodeHash != crypto.Keccak256Hash(common.FromHex("0x602a600155")) {
+			incomplete.Bytecodes = append(incomplete.Bytecodes, code)
+		}
+	}
+	if _, err := Verify(&config, &incomplete); err == nil {
+		t.Fatal("trace without contract code passed verification")
+	}
+
+	// A wrong post state root must be detected.
+	tampered := *trace
+	tampered.StorageTrace = new(types.StorageTrace)
+	*tampered.StorageTrace = *trace.StorageTrace
+	tampered.StorageTrace.RootAfter = common.Hash{1}
+	if _, err := Verify(&config, &tampered); err == nil {
+		t.Fatal("trace with wrong root passed verification")
+	}
+}
+
+func TestVerifyBatchWitness(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0xc0de")
+		config   = *params.TestChainConfig
+	)
+	config.Ethash = nil
+	config.Clique = &params.CliqueConfig{Period: 1, Epoch: 30000}
+	config.Scroll.UseZktrie = true
+	config.CurieBlock = big.NewInt(2) // The batch spans the Curie fork
+
+	db := rawdb.NewMemoryDatabase()
+	engine := clique.New(config.Clique, db)
+	gspec := &core.Genesis{
+		Config:    &config,
+		ExtraData: make([]byte, 32+common.AddressLength+crypto.SignatureLength),
+		Alloc: core.GenesisAlloc{
+			addr: {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))},
+			// PUSH1 0x01 SLOAD PUSH1 0x01 ADD PUSH1 0x01 SSTORE PUSH1 0x00 PUSH1 0x02 SSTORE
+			contract: {Balance: common.Big0, Code: common.FromHex("0x6001546001016001556000600255"), Storage: map[common.Hash]common.Hash{
+				common.BytesToHash([]byte{2}): common.BytesToHash([]byte{2}),
+				common.BytesToHash([]byte{3}): common.BytesToHash([]byte{3}),
+			}},
+		},
+	}
+	copy(gspec.ExtraData[32:], addr[:])
+	genesis := gspec.MustCommit(db)
+	chain, err := core.NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	// Seal and insert the blocks one by one, the hash of every block changes
+	// when it is sealed.
+	signer := types.LatestSigner(&config)
+	blocks := make([]*types.Block, 0, 3)
+	for parent := genesis; len(blocks) < 3; parent = blocks[len(blocks)-1] {
+		recipient := common.Address{byte(len(blocks) + 1)}
+		generated, _ := core.GenerateChain(&config, parent, engine, db, 1, func(i int, block *core.BlockGen) {
+			block.SetDifficulty(big.NewInt(2))
+			tx, _ := types.SignTx(types.NewTransaction(block.TxNonce(addr), recipient, big.NewInt(1000), params.TxGas, block.BaseFee(), nil), signer, key)
+			block.AddTx(tx)
+			tx, _ = types.SignTx(types.NewTransaction(block.TxNonce(addr), contract, common.Big0, 100000, block.BaseFee(), nil), signer, key)
+			block.AddTx(tx)
+		})
+		header := generated[0].Header()
+		header.Extra = make([]byte, 32+crypto.SignatureLength)
+		sig, _ := crypto.Sign(clique.SealHash(header).Bytes(), key)
+		copy(header.Extra[32:], sig)
+		block := generated[0].WithSeal(header)
+		if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+			t.Fatalf("failed to insert block %d: %v", block.NumberU64(), err)
+		}
+		blocks = append(blocks, block)
+	}
+	statedb, err := chain.StateAt(genesis.Root())
+	if err != nil {
+		t.Fatalf("failed to open state: %v", err)
+	}
+	witness, err := tracing.NewTracerWrapper().CreateTraceEnvAndGetBatchWitness(&config, chain, chain.Engine(), db, statedb, genesis, blocks)
+	if err != nil {
+		t.Fatalf("failed to trace batch: %v", err)
+	}
+	if len(witness.Blocks) != len(blocks) {
+		t.Fatalf("block count mismatch: have %d, want %d", len(witness.Blocks), len(blocks))
+	}
+	if witness.StorageTrace.RootBefore != genesis.Root() || witness.StorageTrace.RootAfter != blocks[2].Root() {
+		t.Fatalf("witness roots mismatch: have %x..%x, want %x..%x", witness.StorageTrace.RootBefore, witness.StorageTrace.RootAfter, genesis.Root(), blocks[2].Root())
+	}
+
+	results, err := VerifyBatch(&config, witness)
+	if err != nil {
+		t.Fatalf("failed to verify batch witness: %v", err)
+	}
+	for i, result := range results {
+		if result.Root != blocks[i].Root() {
+			t.Fatalf("block %d state root mismatch: have %x, want %x", i+1, result.Root, blocks[i].Root())
+		}
+	}
+
+	// Dropping a touched contract code or the storage proofs must be detected.
+	incomplete := *witness
+	incomplete.Bytecodes = nil
+	if _, err := VerifyBatch(&config, &incomplete); err == nil {
+		t.Fatal("batch witness without contract code passed verification")
+	}
+	incomplete = *witness
+	incomplete.StorageTrace = new(types.StorageTrace)
+	*incomplete.StorageTrace = *witness.StorageTrace
+	incomplete.StorageTrace.StorageProofs = make(map[string]map[string][]hexutil.Bytes)
+	if _, err := VerifyBatch(&config, &incomplete); err == nil {
+		t.Fatal("batch witness without storage proofs passed verification")
+	}
+}
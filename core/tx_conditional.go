@@ -0,0 +1,55 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
+)
+
+// CheckConditional checks the preconditions of a conditional transaction for
+// its inclusion in the block with the given number and timestamp, whose state
+// before the transaction is statedb.
+func CheckConditional(cond *types.TransactionConditional, number *big.Int, time uint64, statedb *state.StateDB) error {
+	if min := cond.BlockNumberMin; min != nil && number.Cmp(min.ToInt()) < 0 {
+		return fmt.Errorf("%w: block number %v before minimum %v", ErrConditionNotMet, number, min.ToInt())
+	}
+	if max := cond.BlockNumberMax; max != nil && number.Cmp(max.ToInt()) > 0 {
+		return fmt.Errorf("%w: block number %v after maximum %v", ErrConditionNotMet, number, max.ToInt())
+	}
+	if min := cond.TimestampMin; min != nil && time < uint64(*min) {
+		return fmt.Errorf("%w: timestamp %d before minimum %d", ErrConditionNotMet, time, *min)
+	}
+	if max := cond.TimestampMax; max != nil && time > uint64(*max) {
+		return fmt.Errorf("%w: timestamp %d after maximum %d", ErrConditionNotMet, time, *max)
+	}
+	for addr, account := range cond.KnownAccounts {
+		if account.StorageRoot != nil {
+			if root := storageRoot(statedb, addr); root != *account.StorageRoot {
+				return fmt.Errorf("%w: storage root of %v is %v, not %v", ErrConditionNotMet, addr, root, *account.StorageRoot)
+			}
+			continue
+		}
+		for slot, value := range account.StorageSlots {
+			if have := statedb.GetState(addr, slot); have != value {
+				return fmt.Errorf("%w: storage slot %v of %v is %v, not %v", ErrConditionNotMet, slot, addr, have, value)
+			}
+		}
+	}
+	return nil
+}
+
+// storageRoot returns the current storage root of an account, including the
+// changes not committed yet.
+func storageRoot(statedb *state.StateDB, addr common.Address) common.Hash {
+	trie := statedb.StorageTrie(addr)
+	if trie == nil {
+		if statedb.IsZktrie() {
+			return common.Hash{}
+		}
+		return types.EmptyRootHash
+	}
+	return trie.Hash()
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+)
+
+// Reasons of the eviction of transactions from the pool.
+const (
+	evictLifetime        = "queued lifetime exceeded"
+	evictPrivateLifetime = "private lifetime exceeded"
+	evictGasPrice        = "below minimum gas price"
+	evictUnderpriced     = "underpriced in a full pool"
+	evictReplaced        = "replaced"
+	evictReplacement     = "replacement underpriced"
+	evictPolicy          = "sender excluded by policy"
+	evictMiner           = "removed by the miner"
+	evictNoFunds         = "insufficient funds"
+	evictGasLimit        = "exceeds block gas limit"
+	evictAccountQueue    = "account queue limit exceeded"
+	evictPendingOverflow = "pending pool overflow"
+	evictQueueOverflow   = "queued pool overflow"
+)
+
+// TxEviction is the record of a transaction dropped from the pool without
+// being included in a block.
+type TxEviction struct {
+	Reason  string    // Reason of the eviction
+	Added   time.Time // Time the transaction was first seen
+	Evicted time.Time // Time the transaction was dropped
+}
+
+// txEvictions remembers the evictions from the pool for a retention window, in
+// a database so that they survive restarts. It is safe for concurrent use, so
+// that it can be queried without the pool lock.
+type txEvictions struct {
+	db        ethdb.KeyValueStore
+	retention time.Duration
+	lock      sync.RWMutex // Protects db from being replaced while in use
+}
+
+// newTxEvictions creates an eviction record kept in memory until a database is
+// set, remembering evictions for the given retention window.
+func newTxEvictions(retention time.Duration) *txEvictions {
+	return &txEvictions{db: rawdb.NewMemoryDatabase(), retention: retention}
+}
+
+// setDatabase moves the eviction record to the given database.
+func (e *txEvictions) setDatabase(db ethdb.KeyValueStore) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.db = db
+}
+
+// record remembers the eviction of the transactions for the given reason.
+func (e *txEvictions) record(txs types.Transactions, reason string) {
+	if len(txs) == 0 {
+		return
+	}
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	now := uint64(time.Now().UnixMilli())
+	for _, tx := range txs {
+		rawdb.WriteTxEviction(e.db, tx.Hash(), &rawdb.TxEvictionRecord{
+			Reason:  reason,
+			Added:   uint64(tx.Time().UnixMilli()),
+			Evicted: now,
+		})
+	}
+}
+
+// forget drops the eviction record of a transaction that entered the pool again.
+func (e *txEvictions) forget(hash common.Hash) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	rawdb.DeleteTxEviction(e.db, hash)
+}
+
+// get returns the eviction record of a transaction.
+func (e *txEvictions) get(hash common.Hash) (TxEviction, bool) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	record := rawdb.ReadTxEviction(e.db, hash)
+	if record == nil || time.Since(time.UnixMilli(int64(record.Evicted))) > e.retention {
+		return TxEviction{}, false
+	}
+	return TxEviction{
+		Reason:  record.Reason,
+		Added:   time.UnixMilli(int64(record.Added)),
+		Evicted: time.UnixMilli(int64(record.Evicted)),
+	}, true
+}
+
+// prune drops the evictions older than the retention window, and returns the
+// number of records dropped.
+func (e *txEvictions) prune(now time.Time) int {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	return rawdb.PruneTxEvictions(e.db, uint64(now.Add(-e.retention).UnixMilli()))
+}
@@ -277,21 +277,41 @@ func (l *txList) Overlaps(tx *types.Transaction) bool {
 	return l.txs.Get(tx.Nonce()) != nil
 }
 
-// Add tries to insert a new transaction into the list, returning whether the
-// transaction was accepted, and if yes, any previous transaction it replaced.
-//
-// If the new transaction is accepted into the list, the lists' cost and gas
-// thresholds are also potentially updated.
-func (l *txList) Add(tx *types.Transaction, state *state.StateDB, priceBump uint64, chainconfig *params.ChainConfig, blockNumber *big.Int) (bool, *types.Transaction) {
+// Accepts returns whether the list would accept the transaction, that is if it
+// doesn't overlap a transaction already in the list or sufficiently bumps its
+// price, along with the L1 data fee of the transaction.
+func (l *txList) Accepts(tx *types.Transaction, state *state.StateDB, priceBump uint64, chainconfig *params.ChainConfig, blockNumber *big.Int) (bool, *big.Int) {
+	l1DataFee := big.NewInt(0)
+	if state != nil && chainconfig != nil {
+		var err error
+		l1DataFee, err = fees.CalculateL1DataFee(tx, state, chainconfig, blockNumber)
+		if err != nil {
+			log.Error("Failed to calculate L1 data fee", "err", err, "tx", tx)
+			return false, nil
+		}
+	}
 	// If there's an older better transaction, abort
 	old := l.txs.Get(tx.Nonce())
 	if old != nil {
-		if old.GasFeeCapCmp(tx) >= 0 || old.GasTipCapCmp(tx) >= 0 {
+		// Fee caps are compared including the L1 data fee per gas, so that the
+		// bump applies to the total fee paid by the transactions.
+		oldL1DataFee := big.NewInt(0)
+		if state != nil && chainconfig != nil {
+			var err error
+			oldL1DataFee, err = fees.CalculateL1DataFee(old, state, chainconfig, blockNumber)
+			if err != nil {
+				log.Error("Failed to calculate L1 data fee", "err", err, "tx", old)
+				return false, nil
+			}
+		}
+		oldFeeCap := totalFeePerGas(old.GasFeeCap(), old, oldL1DataFee)
+		feeCap := totalFeePerGas(tx.GasFeeCap(), tx, l1DataFee)
+		if oldFeeCap.Cmp(feeCap) >= 0 || old.GasTipCapCmp(tx) >= 0 {
 			return false, nil
 		}
 		// thresholdFeeCap = oldFC  * (100 + priceBump) / 100
 		a := big.NewInt(100 + int64(priceBump))
-		aFeeCap := new(big.Int).Mul(a, old.GasFeeCap())
+		aFeeCap := new(big.Int).Mul(a, oldFeeCap)
 		aTip := a.Mul(a, old.GasTipCap())
 
 		// thresholdTip    = oldTip * (100 + priceBump) / 100
@@ -302,20 +322,25 @@ func (l *txList) Add(tx *types.Transaction, state *state.StateDB, priceBump uint
 		// We have to ensure that both the new fee cap and tip are higher than the
 		// old ones as well as checking the percentage threshold to ensure that
 		// this is accurate for low (Wei-level) gas price replacements.
-		if tx.GasFeeCapIntCmp(thresholdFeeCap) < 0 || tx.GasTipCapIntCmp(thresholdTip) < 0 {
+		if feeCap.Cmp(thresholdFeeCap) < 0 || tx.GasTipCapIntCmp(thresholdTip) < 0 {
 			return false, nil
 		}
 	}
-	// Otherwise overwrite the old transaction with the current one
-	l1DataFee := big.NewInt(0)
-	if state != nil && chainconfig != nil {
-		var err error
-		l1DataFee, err = fees.CalculateL1DataFee(tx, state, chainconfig, blockNumber)
-		if err != nil {
-			log.Error("Failed to calculate L1 data fee", "err", err, "tx", tx)
-			return false, nil
-		}
+	return true, l1DataFee
+}
+
+// Add tries to insert a new transaction into the list, returning whether the
+// transaction was accepted, and if yes, any previous transaction it replaced.
+//
+// If the new transaction is accepted into the list, the lists' cost and gas
+// thresholds are also potentially updated.
+func (l *txList) Add(tx *types.Transaction, state *state.StateDB, priceBump uint64, chainconfig *params.ChainConfig, blockNumber *big.Int) (bool, *types.Transaction) {
+	accepted, l1DataFee := l.Accepts(tx, state, priceBump, chainconfig, blockNumber)
+	if !accepted {
+		return false, nil
 	}
+	// Overwrite the old transaction, if any, with the current one
+	old := l.txs.Get(tx.Nonce())
 	l.txs.Put(tx)
 	if cost := new(big.Int).Add(tx.Cost(), l1DataFee); l.costcap.Cmp(cost) < 0 {
 		l.costcap = cost
@@ -326,6 +351,16 @@ func (l *txList) Add(tx *types.Transaction, state *state.StateDB, priceBump uint
 	return true, old
 }
 
+// totalFeePerGas returns the given per gas price of a transaction increased by
+// its L1 data fee spread over its gas limit.
+func totalFeePerGas(price *big.Int, tx *types.Transaction, l1DataFee *big.Int) *big.Int {
+	if l1DataFee.Sign() == 0 || tx.Gas() == 0 {
+		return price
+	}
+	perGas := new(big.Int).Div(l1DataFee, new(big.Int).SetUint64(tx.Gas()))
+	return perGas.Add(perGas, price)
+}
+
 // Forward removes all transactions from the list with a nonce lower than the
 // provided threshold. Every removed transaction is returned for any post-removal
 // maintenance.
@@ -463,9 +498,11 @@ func (l *txList) LastElement() *types.Transaction {
 // priceHeap is a heap.Interface implementation over transactions for retrieving
 // price-sorted transactions to discard when the pool fills up. If baseFee is set
 // then the heap is sorted based on the effective tip based on the given base fee.
-// If baseFee is nil then the sorting is based on gasFeeCap.
+// If baseFee is nil then the sorting is based on gasFeeCap. In both cases the
+// L1 data fee of the transaction per gas is added to its price.
 type priceHeap struct {
-	baseFee *big.Int // heap should always be re-sorted after baseFee is changed
+	baseFee *big.Int                 // heap should always be re-sorted after baseFee is changed
+	l1Fees  map[common.Hash]*big.Int // L1 data fees of the transactions, missing ones count as zero
 	list    []*types.Transaction
 }
 
@@ -484,20 +521,35 @@ func (h *priceHeap) Less(i, j int) bool {
 }
 
 func (h *priceHeap) cmp(a, b *types.Transaction) int {
+	return h.cmpWithL1DataFee(a, h.l1DataFee(a), b, h.l1DataFee(b))
+}
+
+// cmpWithL1DataFee compares two transactions with the given L1 data fees.
+func (h *priceHeap) cmpWithL1DataFee(a *types.Transaction, aL1DataFee *big.Int, b *types.Transaction, bL1DataFee *big.Int) int {
 	if h.baseFee != nil {
 		// Compare effective tips if baseFee is specified
-		if c := a.EffectiveGasTipCmp(b, h.baseFee); c != 0 {
+		aTip := totalFeePerGas(a.EffectiveGasTipValue(h.baseFee), a, aL1DataFee)
+		bTip := totalFeePerGas(b.EffectiveGasTipValue(h.baseFee), b, bL1DataFee)
+		if c := aTip.Cmp(bTip); c != 0 {
 			return c
 		}
 	}
 	// Compare fee caps if baseFee is not specified or effective tips are equal
-	if c := a.GasFeeCapCmp(b); c != 0 {
+	if c := totalFeePerGas(a.GasFeeCap(), a, aL1DataFee).Cmp(totalFeePerGas(b.GasFeeCap(), b, bL1DataFee)); c != 0 {
 		return c
 	}
 	// Compare tips if effective tips and fee caps are equal
 	return a.GasTipCapCmp(b)
 }
 
+// l1DataFee returns the tracked L1 data fee of the transaction.
+func (h *priceHeap) l1DataFee(tx *types.Transaction) *big.Int {
+	if fee := h.l1Fees[tx.Hash()]; fee != nil {
+		return fee
+	}
+	return common.Big0
+}
+
 func (h *priceHeap) Push(x interface{}) {
 	tx := x.(*types.Transaction)
 	h.list = append(h.list, tx)
@@ -523,6 +575,11 @@ func (h *priceHeap) Pop() interface{} {
 // In some cases (during a congestion, when blocks are full) the urgent heap can provide
 // better candidates for inclusion while in other cases (at the top of the baseFee peak)
 // the floating heap is better. When baseFee is decreasing they behave similarly.
+//
+// Prices include the L1 data fee of the transactions per gas, so that transactions
+// with large payloads are ranked by what they pay relative to their real cost. The
+// L1 data fees are computed when a transaction is added and refreshed on every
+// re-heap, since the L1 fee parameters may change with each block.
 type txPricedList struct {
 	// Number of stale price points to (re-heap trigger).
 	// This field is accessed atomically, and must be the first field
@@ -530,9 +587,10 @@ type txPricedList struct {
 	// See https://golang.org/pkg/sync/atomic/#pkg-note-BUG.
 	stales int64
 
-	all              *txLookup  // Pointer to the map of all transactions
-	urgent, floating priceHeap  // Heaps of prices of all the stored **remote** transactions
-	reheapMu         sync.Mutex // Mutex asserts that only one routine is reheaping the list
+	all              *txLookup                            // Pointer to the map of all transactions
+	urgent, floating priceHeap                            // Heaps of prices of all the stored **remote** transactions
+	reheapMu         sync.Mutex                           // Mutex asserts that only one routine is reheaping the list
+	l1DataFee        func(tx *types.Transaction) *big.Int // Computes the current L1 data fee of a transaction
 }
 
 const (
@@ -541,11 +599,33 @@ const (
 	floatingRatio = 1
 )
 
-// newTxPricedList creates a new price-sorted transaction heap.
-func newTxPricedList(all *txLookup) *txPricedList {
-	return &txPricedList{
-		all: all,
+// newTxPricedList creates a new price-sorted transaction heap. The l1DataFee
+// callback may be nil, in which case L1 data fees are not taken into account.
+func newTxPricedList(all *txLookup, l1DataFee func(tx *types.Transaction) *big.Int) *txPricedList {
+	l := &txPricedList{
+		all:       all,
+		l1DataFee: l1DataFee,
 	}
+	l.setL1Fees(make(map[common.Hash]*big.Int))
+	return l
+}
+
+// setL1Fees replaces the L1 data fees tracked by both heaps.
+func (l *txPricedList) setL1Fees(l1Fees map[common.Hash]*big.Int) {
+	l.urgent.l1Fees = l1Fees
+	l.floating.l1Fees = l1Fees
+}
+
+// trackL1DataFee computes and records the L1 data fee of the transaction.
+func (l *txPricedList) trackL1DataFee(tx *types.Transaction) {
+	if l.l1DataFee != nil {
+		l.urgent.l1Fees[tx.Hash()] = l.l1DataFee(tx)
+	}
+}
+
+// untrackL1DataFee forgets the L1 data fee of a transaction leaving the heaps.
+func (l *txPricedList) untrackL1DataFee(tx *types.Transaction) {
+	delete(l.urgent.l1Fees, tx.Hash())
 }
 
 // Put inserts a new transaction into the heap.
@@ -554,6 +634,7 @@ func (l *txPricedList) Put(tx *types.Transaction, local bool) {
 		return
 	}
 	// Insert every new transaction to the urgent heap first; Discard will balance the heaps
+	l.trackL1DataFee(tx)
 	heap.Push(&l.urgent, tx)
 }
 
@@ -588,7 +669,7 @@ func (l *txPricedList) underpricedFor(h *priceHeap, tx *types.Transaction) bool
 		head := h.list[0]
 		if l.all.GetRemote(head.Hash()) == nil { // Removed or migrated
 			atomic.AddInt64(&l.stales, -1)
-			heap.Pop(h)
+			l.untrackL1DataFee(heap.Pop(h).(*types.Transaction))
 			continue
 		}
 		break
@@ -599,7 +680,11 @@ func (l *txPricedList) underpricedFor(h *priceHeap, tx *types.Transaction) bool
 	}
 	// If the remote transaction is even cheaper than the
 	// cheapest one tracked locally, reject it.
-	return h.cmp(h.list[0], tx) >= 0
+	l1DataFee := common.Big0
+	if l.l1DataFee != nil {
+		l1DataFee = l.l1DataFee(tx)
+	}
+	return h.cmpWithL1DataFee(h.list[0], h.l1DataFee(h.list[0]), tx, l1DataFee) >= 0
 }
 
 // Discard finds a number of most underpriced transactions, removes them from the
@@ -614,6 +699,7 @@ func (l *txPricedList) Discard(slots int, force bool) (types.Transactions, bool)
 			tx := heap.Pop(&l.urgent).(*types.Transaction)
 			if l.all.GetRemote(tx.Hash()) == nil { // Removed or migrated
 				atomic.AddInt64(&l.stales, -1)
+				l.untrackL1DataFee(tx)
 				continue
 			}
 			// Non stale transaction found, move to floating heap
@@ -627,6 +713,7 @@ func (l *txPricedList) Discard(slots int, force bool) (types.Transactions, bool)
 			tx := heap.Pop(&l.floating).(*types.Transaction)
 			if l.all.GetRemote(tx.Hash()) == nil { // Removed or migrated
 				atomic.AddInt64(&l.stales, -1)
+				l.untrackL1DataFee(tx)
 				continue
 			}
 			// Non stale transaction found, discard it
@@ -641,6 +728,9 @@ func (l *txPricedList) Discard(slots int, force bool) (types.Transactions, bool)
 		}
 		return nil, false
 	}
+	for _, tx := range drop {
+		l.untrackL1DataFee(tx)
+	}
 	return drop, true
 }
 
@@ -651,7 +741,9 @@ func (l *txPricedList) Reheap() {
 	start := time.Now()
 	atomic.StoreInt64(&l.stales, 0)
 	l.urgent.list = make([]*types.Transaction, 0, l.all.RemoteCount())
+	l.setL1Fees(make(map[common.Hash]*big.Int, l.all.RemoteCount()))
 	l.all.Range(func(hash common.Hash, tx *types.Transaction, local bool) bool {
+		l.trackL1DataFee(tx)
 		l.urgent.list = append(l.urgent.list, tx)
 		return true
 	}, false, true) // Only iterate remotes
@@ -70,3 +70,32 @@ func BenchmarkTxListAdd(b *testing.B) {
 		}
 	}
 }
+
+// Tests that the L1 data fees tracked by the priced list are forgotten once the
+// transactions leave it.
+func TestPricedListL1DataFees(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+
+	all := newTxLookup()
+	list := newTxPricedList(all, func(tx *types.Transaction) *big.Int { return big.NewInt(1) })
+	for i := 0; i < 8; i++ {
+		tx := pricedTransaction(uint64(i), 100000, big.NewInt(int64(i+1)), key)
+		all.Add(tx, false)
+		list.Put(tx, false)
+	}
+	// Stale transactions are forgotten when popped
+	for i := 0; i < 4; i++ {
+		all.Remove(list.urgent.list[0].Hash())
+		list.Underpriced(pricedTransaction(0, 100000, big.NewInt(1), key))
+	}
+	if have := len(list.urgent.l1Fees); have != 4 {
+		t.Fatalf("tracked fees after removals mismatch: have %d, want 4", have)
+	}
+	// Discarded transactions are forgotten
+	if drop, _ := list.Discard(4, true); len(drop) != 4 {
+		t.Fatalf("discarded transactions mismatch: have %d, want 4", len(drop))
+	}
+	if have := len(list.urgent.l1Fees); have != 0 {
+		t.Fatalf("tracked fees after discard mismatch: have %d, want 0", have)
+	}
+}
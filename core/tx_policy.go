@@ -0,0 +1,161 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+)
+
+// TxPolicy is the admission policy of the transaction pool for senders. Denied
+// senders are rejected, as are senders not allowed in allowlist-only mode.
+// Allowed senders are exempt from rate limiting.
+type TxPolicy struct {
+	RateLimit     float64          `json:"rateLimit"`     // Transactions accepted per second from each sender (0 = unlimited)
+	RateBurst     uint64           `json:"rateBurst"`     // Transactions accepted at once from each sender
+	Deny          []common.Address `json:"deny"`          // Senders whose transactions are rejected
+	Allow         []common.Address `json:"allow"`         // Senders exempt from rate limiting
+	AllowlistOnly bool             `json:"allowlistOnly"` // Whether to reject senders not in the allow list
+}
+
+// validate checks the policy for inconsistencies.
+func (p *TxPolicy) validate() error {
+	if p.RateLimit < 0 || math.IsNaN(p.RateLimit) || math.IsInf(p.RateLimit, 0) {
+		return errors.New("invalid rate limit")
+	}
+	if p.RateLimit > 0 && p.RateBurst == 0 {
+		return errors.New("rate limit without burst")
+	}
+	return nil
+}
+
+// senderBucket is the token bucket rate limiting a sender.
+type senderBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// txPolicy is a TxPolicy prepared for lookups, along with the rate limiting
+// state of senders. It is not safe for concurrent use, the pool lock protects it.
+type txPolicy struct {
+	policy  TxPolicy
+	deny    map[common.Address]struct{}
+	allow   map[common.Address]struct{}
+	buckets map[common.Address]*senderBucket
+}
+
+// newTxPolicy prepares the policy for admission checks.
+func newTxPolicy(policy TxPolicy) *txPolicy {
+	p := &txPolicy{
+		policy:  policy,
+		deny:    make(map[common.Address]struct{}, len(policy.Deny)),
+		allow:   make(map[common.Address]struct{}, len(policy.Allow)),
+		buckets: make(map[common.Address]*senderBucket),
+	}
+	for _, addr := range policy.Deny {
+		p.deny[addr] = struct{}{}
+	}
+	for _, addr := range policy.Allow {
+		p.allow[addr] = struct{}{}
+	}
+	return p
+}
+
+// excluded returns the error rejecting all transactions of the sender, if any.
+func (p *txPolicy) excluded(addr common.Address) error {
+	if _, ok := p.deny[addr]; ok {
+		return ErrSenderDenied
+	}
+	if _, ok := p.allow[addr]; !ok && p.policy.AllowlistOnly {
+		return ErrSenderNotAllowed
+	}
+	return nil
+}
+
+// limited returns whether the transactions of the sender are rate limited.
+func (p *txPolicy) limited(addr common.Address) bool {
+	_, ok := p.allow[addr]
+	return !ok && p.policy.RateLimit > 0
+}
+
+// admit checks whether a transaction of the sender may enter the pool. If the
+// transaction is rate limited, the sender must have a token left, which charge
+// takes once the transaction is in the pool.
+func (p *txPolicy) admit(addr common.Address, limited bool, now time.Time) error {
+	if err := p.excluded(addr); err != nil {
+		return err
+	}
+	if !limited || !p.limited(addr) {
+		return nil
+	}
+	if bucket := p.buckets[addr]; bucket != nil && p.refill(bucket, now) < 1 {
+		return ErrSenderRateLimited
+	}
+	return nil
+}
+
+// charge takes a token from the sender for a transaction admitted in the pool.
+func (p *txPolicy) charge(addr common.Address, now time.Time) {
+	if !p.limited(addr) {
+		return
+	}
+	bucket := p.buckets[addr]
+	if bucket == nil {
+		bucket = &senderBucket{tokens: float64(p.policy.RateBurst), last: now}
+		p.buckets[addr] = bucket
+	}
+	bucket.tokens = p.refill(bucket, now) - 1
+	bucket.last = now
+}
+
+// refill returns the tokens of the bucket at the given time.
+func (p *txPolicy) refill(bucket *senderBucket, now time.Time) float64 {
+	tokens := bucket.tokens + now.Sub(bucket.last).Seconds()*p.policy.RateLimit
+	return math.Min(tokens, float64(p.policy.RateBurst))
+}
+
+// prune forgets the senders whose bucket is full again, as they are not rate
+// limited anymore.
+func (p *txPolicy) prune(now time.Time) {
+	for addr, bucket := range p.buckets {
+		if p.refill(bucket, now) >= float64(p.policy.RateBurst) {
+			delete(p.buckets, addr)
+		}
+	}
+}
+
+// loadTxPolicy reads a policy from the given file. A missing file is an empty
+// policy.
+func loadTxPolicy(path string) (TxPolicy, error) {
+	var policy TxPolicy
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return policy, nil
+	}
+	if err != nil {
+		return policy, err
+	}
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return TxPolicy{}, err
+	}
+	if err := policy.validate(); err != nil {
+		return TxPolicy{}, err
+	}
+	return policy, nil
+}
+
+// saveTxPolicy writes a policy to the given file, replacing it atomically.
+func saveTxPolicy(path string, policy TxPolicy) error {
+	data, err := json.MarshalIndent(policy, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".new"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
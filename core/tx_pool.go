@@ -26,11 +26,14 @@ import (
 	"sync/atomic"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/prque"
 	"github.com/scroll-tech/go-ethereum/consensus/misc"
 	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/event"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/metrics"
@@ -87,11 +90,29 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	// ErrCircuitCapacityOverflow is returned if a transaction would overflow the
+	// circuit capacity even of an otherwise empty block, so it could never be
+	// included in a block.
+	ErrCircuitCapacityOverflow = errors.New("transaction exceeds circuit capacity")
+
+	// ErrSenderDenied is returned if the sender of a transaction is in the deny
+	// list of the pool policy.
+	ErrSenderDenied = errors.New("sender denied by txpool policy")
+
+	// ErrSenderNotAllowed is returned if the pool policy only admits the senders
+	// of its allow list and the sender of a transaction is not in it.
+	ErrSenderNotAllowed = errors.New("sender not allowed by txpool policy")
+
+	// ErrSenderRateLimited is returned if the sender of a transaction exceeded
+	// the rate limit of the pool policy.
+	ErrSenderRateLimited = errors.New("sender rate limited by txpool policy")
 )
 
 var (
-	evictionInterval    = time.Minute     // Time interval to check for evictable transactions
-	statsReportInterval = 8 * time.Second // Time interval to report transaction pool stats
+	evictionInterval        = time.Minute     // Time interval to check for evictable transactions
+	privateEvictionInterval = 5 * time.Second // Time interval to check for expired private transactions
+	statsReportInterval     = 8 * time.Second // Time interval to report transaction pool stats
 )
 
 var (
@@ -117,6 +138,21 @@ var (
 	// throttleTxMeter counts how many transactions are rejected due to too-many-changes between
 	// txpool reorgs.
 	throttleTxMeter = metrics.NewRegisteredMeter("txpool/throttle", nil)
+	// circuitOverflowTxMeter counts how many transactions are rejected for overflowing the
+	// circuit capacity, circuitEstimatedTxMeter how many are admitted on the estimate alone
+	// and circuitUncheckedTxMeter how many are admitted unchecked over the check budget.
+	circuitOverflowTxMeter  = metrics.NewRegisteredMeter("txpool/ccc/overflow", nil)
+	circuitEstimatedTxMeter = metrics.NewRegisteredMeter("txpool/ccc/estimated", nil)
+	circuitUncheckedTxMeter = metrics.NewRegisteredMeter("txpool/ccc/unchecked", nil)
+
+	// policyRejectMeter counts transactions rejected by the sender policy
+	policyRejectMeter = metrics.NewRegisteredMeter("txpool/policy/rejected", nil)
+
+	// privateExpiredMeter counts private transactions dropped at their deadline
+	privateExpiredMeter = metrics.NewRegisteredMeter("txpool/private/expired", nil)
+
+	// maintenanceTxMeter counts transactions rejected while the sequencer is in maintenance
+	maintenanceTxMeter = metrics.NewRegisteredMeter("txpool/maintenance", nil)
 	// reorgDurationTimer measures how long time a txpool reorg takes.
 	reorgDurationTimer = metrics.NewRegisteredTimer("txpool/reorgtime", nil)
 	// dropBetweenReorgHistogram counts how many drops we experience between two reorg runs. It is expected
@@ -128,6 +164,7 @@ var (
 	queuedGauge      = metrics.NewRegisteredGauge("txpool/queued", nil)
 	realQueuedGauge  = metrics.NewRegisteredGauge("txpool/real_queued", nil)
 	localGauge       = metrics.NewRegisteredGauge("txpool/local", nil)
+	privateGauge     = metrics.NewRegisteredGauge("txpool/private", nil)
 	slotsGauge       = metrics.NewRegisteredGauge("txpool/slots", nil)
 
 	reheapTimer = metrics.NewRegisteredTimer("txpool/reheap", nil)
@@ -172,6 +209,29 @@ type TxPoolConfig struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	CircuitCheck       bool   // Whether to reject transactions overflowing the circuit capacity of an empty block
+	CircuitCheckBudget uint64 // Maximum number of transactions checked against the circuit capacity per second, over which they are admitted unchecked (0 = unlimited)
+
+	PrivateLifetime time.Duration // Maximum amount of time private transactions are kept before being dropped
+
+	PolicyFile string // File the sender admission policy is persisted to
+
+	EvictionRetention time.Duration // Amount of time evictions are remembered for status queries
+}
+
+// CircuitChecker checks whether a transaction fits in the circuits of an
+// otherwise empty block on top of the current chain head.
+type CircuitChecker interface {
+	// EstimateTx cheaply bounds the circuit usage of the transaction without
+	// tracing it. It returns an error wrapping ErrCircuitCapacityOverflow if the
+	// transaction surely overflows the circuits, and whether it surely fits.
+	EstimateTx(tx *types.Transaction) (bool, error)
+
+	// CheckTx returns an error wrapping ErrCircuitCapacityOverflow if the
+	// transaction would overflow the circuits. Other failures should not reject
+	// the transaction, since the pool validates it separately.
+	CheckTx(tx *types.Transaction) error
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -180,6 +240,8 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	Journal:   "transactions.rlp",
 	Rejournal: time.Hour,
 
+	PolicyFile: "txpool_policy.json",
+
 	PriceLimit: 1,
 	PriceBump:  10,
 
@@ -189,6 +251,12 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	GlobalQueue:  1024,
 
 	Lifetime: 3 * time.Hour,
+
+	CircuitCheckBudget: 100,
+
+	PrivateLifetime: 10 * time.Minute,
+
+	EvictionRetention: 24 * time.Hour,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -227,6 +295,14 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultTxPoolConfig.Lifetime)
 		conf.Lifetime = DefaultTxPoolConfig.Lifetime
 	}
+	if conf.PrivateLifetime < 1 {
+		log.Warn("Sanitizing invalid txpool private lifetime", "provided", conf.PrivateLifetime, "updated", DefaultTxPoolConfig.PrivateLifetime)
+		conf.PrivateLifetime = DefaultTxPoolConfig.PrivateLifetime
+	}
+	if conf.EvictionRetention < 1 {
+		log.Warn("Sanitizing invalid txpool eviction retention", "provided", conf.EvictionRetention, "updated", DefaultTxPoolConfig.EvictionRetention)
+		conf.EvictionRetention = DefaultTxPoolConfig.EvictionRetention
+	}
 	return conf
 }
 
@@ -243,6 +319,7 @@ type TxPool struct {
 	chain       blockChain
 	gasPrice    *big.Int
 	txFeed      event.Feed
+	publicFeed  event.Feed // Same as txFeed, without the private transactions
 	scope       event.SubscriptionScope
 	signer      types.Signer
 	mu          sync.RWMutex
@@ -253,7 +330,7 @@ type TxPool struct {
 	shanghai bool // Fork indicator whether we are in the Shanghai stage.
 
 	currentState  *state.StateDB // Current state in the blockchain head
-	currentHead   *big.Int       // Current blockchain head
+	currentHead   *big.Int       // Number of the block following the current blockchain head
 	pendingNonces *txNoncer      // Pending state tracking virtual nonces
 	currentMaxGas uint64         // Current gas limit for transaction caps
 
@@ -265,6 +342,13 @@ type TxPool struct {
 	beats   map[common.Address]time.Time // Last heartbeat from each known account
 	all     *txLookup                    // All transactions to allow lookups
 	priced  *txPricedList                // All transactions sorted by price
+	private *privateTxSet                // Transactions kept from the network until included or expired
+	policy  *txPolicy                    // Admission policy for senders
+	evicted *txEvictions                 // Recently evicted transactions, for status queries
+
+	circuitChecker CircuitChecker // Optional circuit capacity admission check
+	circuitBudget  *rate.Limiter  // Rate limit of the circuit capacity checks, over which txs are admitted unchecked
+	maintenance    bool           // Whether new transactions are rejected as the sequencer is in maintenance
 
 	chainHeadCh              chan ChainHeadEvent
 	chainHeadSub             event.Subscription
@@ -301,6 +385,8 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		queue:                    make(map[common.Address]*txList),
 		beats:                    make(map[common.Address]time.Time),
 		all:                      newTxLookup(),
+		private:                  newPrivateTxSet(),
+		evicted:                  newTxEvictions(config.EvictionRetention),
 		chainHeadCh:              make(chan ChainHeadEvent, chainHeadChanSize),
 		reqResetCh:               make(chan *txpoolResetRequest),
 		reqPromoteCh:             make(chan *accountSet),
@@ -311,13 +397,25 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		reorgPauseCh:             make(chan bool),
 		initDoneCh:               make(chan struct{}),
 		gasPrice:                 new(big.Int).SetUint64(config.PriceLimit),
+		circuitBudget:            rate.NewLimiter(rate.Inf, 0),
+	}
+	if config.CircuitCheckBudget > 0 {
+		pool.circuitBudget = rate.NewLimiter(rate.Limit(config.CircuitCheckBudget), int(config.CircuitCheckBudget))
+	}
+	pool.policy = newTxPolicy(TxPolicy{})
+	if config.PolicyFile != "" {
+		if policy, err := loadTxPolicy(config.PolicyFile); err != nil {
+			log.Warn("Failed to load txpool policy", "file", config.PolicyFile, "err", err)
+		} else {
+			pool.policy = newTxPolicy(policy)
+		}
 	}
 	pool.locals = newAccountSet(pool.signer)
 	for _, addr := range config.Locals {
 		log.Info("Setting new local account", "address", addr)
 		pool.locals.add(addr)
 	}
-	pool.priced = newTxPricedList(pool.all)
+	pool.priced = newTxPricedList(pool.all, pool.l1DataFee)
 	pool.reset(nil, chain.CurrentBlock().Header())
 
 	// Start the reorg loop early so it can handle requests generated during journal loading.
@@ -331,7 +429,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		if err := pool.journal.load(pool.AddLocals); err != nil {
 			log.Warn("Failed to load transaction journal", "err", err)
 		}
-		if err := pool.journal.rotate(pool.local()); err != nil {
+		if err := pool.journal.rotate(pool.journaled()); err != nil {
 			log.Warn("Failed to rotate transaction journal", "err", err)
 		}
 	}
@@ -373,12 +471,14 @@ func (pool *TxPool) loop() {
 		// Start the stats reporting and transaction eviction tickers
 		report  = time.NewTicker(statsReportInterval)
 		evict   = time.NewTicker(evictionInterval)
+		private = time.NewTicker(privateEvictionInterval)
 		journal = time.NewTicker(pool.config.Rejournal)
 		// Track the previous head headers for transaction reorgs
 		head = pool.chain.CurrentBlock()
 	)
 	defer report.Stop()
 	defer evict.Stop()
+	defer private.Stop()
 	defer journal.Stop()
 
 	// Notify tests that the init phase is done
@@ -422,18 +522,27 @@ func (pool *TxPool) loop() {
 				if time.Since(pool.beats[addr]) > pool.config.Lifetime {
 					list := pool.queue[addr].Flatten()
 					for _, tx := range list {
-						pool.removeTx(tx.Hash(), true)
+						pool.evictTx(tx.Hash(), true, evictLifetime)
 					}
 					queuedEvictionMeter.Mark(int64(len(list)))
 				}
 			}
+			pool.policy.prune(time.Now())
+			pool.mu.Unlock()
+
+			pool.evicted.prune(time.Now())
+
+		// Handle expiry of private transactions
+		case <-private.C:
+			pool.mu.Lock()
+			pool.expirePrivate(time.Now())
 			pool.mu.Unlock()
 
 		// Handle local transaction journal rotation
 		case <-journal.C:
 			if pool.journal != nil {
 				pool.mu.Lock()
-				if err := pool.journal.rotate(pool.local()); err != nil {
+				if err := pool.journal.rotate(pool.journaled()); err != nil {
 					log.Warn("Failed to rotate local tx journal", "err", err)
 				}
 				pool.mu.Unlock()
@@ -463,6 +572,12 @@ func (pool *TxPool) SubscribeNewTxsEvent(ch chan<- NewTxsEvent) event.Subscripti
 	return pool.scope.Track(pool.txFeed.Subscribe(ch))
 }
 
+// SubscribePublicTxsEvent registers a subscription of NewTxsEvent leaving out
+// the private transactions, and starts sending event to the given channel.
+func (pool *TxPool) SubscribePublicTxsEvent(ch chan<- NewTxsEvent) event.Subscription {
+	return pool.scope.Track(pool.publicFeed.Subscribe(ch))
+}
+
 // GasPrice returns the current gas price enforced by the transaction pool.
 func (pool *TxPool) GasPrice() *big.Int {
 	pool.mu.RLock()
@@ -484,7 +599,7 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 		// pool.priced is sorted by GasFeeCap, so we have to iterate through pool.all instead
 		drop := pool.all.RemotesBelowTip(price)
 		for _, tx := range drop {
-			pool.removeTx(tx.Hash(), false)
+			pool.evictTx(tx.Hash(), false, evictGasPrice)
 		}
 		pool.priced.Removed(len(drop))
 	}
@@ -597,6 +712,27 @@ func (pool *TxPool) ContentFrom(addr common.Address) (types.Transactions, types.
 	return pending, queued
 }
 
+// PublicContent is like Content, but leaves out the private transactions.
+func (pool *TxPool) PublicContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
+	pending, queued := pool.Content()
+	for _, txs := range []map[common.Address]types.Transactions{pending, queued} {
+		for addr, list := range txs {
+			if list = pool.private.filter(list); len(list) == 0 {
+				delete(txs, addr)
+			} else {
+				txs[addr] = list
+			}
+		}
+	}
+	return pending, queued
+}
+
+// PublicContentFrom is like ContentFrom, but leaves out the private transactions.
+func (pool *TxPool) PublicContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	pending, queued := pool.ContentFrom(addr)
+	return pool.private.filter(pending), pool.private.filter(queued)
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce. The returned transaction set is a copy and can be
 // freely modified by calling code.
@@ -665,6 +801,74 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 	return txs
 }
 
+// Stale retrieves the local executable transactions first seen longer than the
+// given age ago, leaving out the private and conditional ones which are never
+// propagated.
+func (pool *TxPool) Stale(age time.Duration) types.Transactions {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	var stale types.Transactions
+	for addr := range pool.locals.accounts {
+		if pending := pool.pending[addr]; pending != nil {
+			for _, tx := range pending.Flatten() {
+				if time.Since(tx.Time()) > age && !pool.private.contains(tx.Hash()) && tx.Conditional() == nil {
+					stale = append(stale, tx)
+				}
+			}
+		}
+	}
+	return stale
+}
+
+// expirePrivate drops the private transactions not included before their
+// deadline. The caller must hold pool.mu.
+func (pool *TxPool) expirePrivate(now time.Time) {
+	expired := pool.private.expire(now, func(hash common.Hash) bool {
+		return pool.all.Get(hash) != nil
+	})
+	for _, hash := range expired {
+		log.Debug("Dropping expired private transaction", "hash", hash)
+		pool.evictTx(hash, true, evictPrivateLifetime)
+	}
+	privateExpiredMeter.Mark(int64(len(expired)))
+	privateGauge.Update(int64(pool.private.count()))
+}
+
+// journaled retrieves the local transactions to be kept in the journal, which
+// are all of them except the private ones, to avoid broadcasting them after a
+// restart, and the conditional ones, whose conditions are not journaled.
+func (pool *TxPool) journaled() map[common.Address]types.Transactions {
+	txs := pool.local()
+	for addr, list := range txs {
+		kept := list[:0]
+		for _, tx := range list {
+			if !pool.private.contains(tx.Hash()) && tx.Conditional() == nil {
+				kept = append(kept, tx)
+			}
+		}
+		if len(kept) == 0 {
+			delete(txs, addr)
+		} else {
+			txs[addr] = kept
+		}
+	}
+	return txs
+}
+
+// Validate checks whether a transaction would be accepted by the pool as a local
+// one, without adding it. Checks depending on the other transactions of the
+// pool, such as replacements and capacity limits, are not performed.
+func (pool *TxPool) Validate(tx *types.Transaction) error {
+	if pool.all.Get(tx.Hash()) != nil {
+		return ErrAlreadyKnown
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.validateTx(tx, !pool.config.NoLocals)
+}
+
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
@@ -749,9 +953,33 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if tx.Gas() < intrGas {
 		return ErrIntrinsicGas
 	}
+	// Conditional transactions must be includable in the next block, whose number
+	// is currentHead
+	if cond := tx.Conditional(); cond != nil && pool.currentHead != nil {
+		if err := CheckConditional(cond, pool.currentHead, uint64(time.Now().Unix()), pool.currentState); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// l1DataFee returns the L1 data fee of the transaction in the current state of
+// the pool, or zero if it cannot be computed. It is used to rank transactions
+// by the total fee they pay.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) l1DataFee(tx *types.Transaction) *big.Int {
+	if pool.currentState == nil {
+		return new(big.Int)
+	}
+	l1DataFee, err := fees.CalculateL1DataFee(tx, pool.currentState, pool.chainconfig, pool.currentHead)
+	if err != nil {
+		log.Debug("Failed to calculate L1 data fee", "hash", tx.Hash(), "err", err)
+		return new(big.Int)
+	}
+	return l1DataFee
+}
+
 // add validates a transaction and inserts it into the non-executable queue for later
 // pending promotion and execution. If the transaction is a replacement for an already
 // pending or queued one, it overwrites the previous transaction if its price is higher.
@@ -759,7 +987,10 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 // If a newly added transaction is marked as local, its sending account will be
 // be added to the allowlist, preventing any associated transaction from being dropped
 // out of the pool due to pricing constraints.
-func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err error) {
+//
+// Transactions reinjected after a reorg bypass the sender policy, and only new
+// remote transactions count towards the rate limit of their sender.
+func (pool *TxPool) add(tx *types.Transaction, local, reinject bool) (replaced bool, err error) {
 	// If the transaction is already known, discard it
 	hash := tx.Hash()
 	if pool.all.Get(hash) != nil {
@@ -777,6 +1008,23 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		invalidTxMeter.Mark(1)
 		return false, err
 	}
+	// If the sender is rejected or rate limited by the policy, discard it
+	from, _ := types.Sender(pool.signer, tx) // already validated
+	if !reinject {
+		limited := !isLocal
+		if err := pool.policy.admit(from, limited, time.Now()); err != nil {
+			log.Trace("Discarding transaction rejected by policy", "hash", hash, "from", from, "err", err)
+			policyRejectMeter.Mark(1)
+			return false, err
+		}
+		if limited {
+			defer func() {
+				if err == nil {
+					pool.policy.charge(from, time.Now())
+				}
+			}()
+		}
+	}
 	// If the transaction pool is full, discard underpriced transactions
 	if uint64(pool.all.Slots()+numSlots(tx)) > pool.config.GlobalSlots+pool.config.GlobalQueue {
 		// If the new transaction is underpriced, don't accept it
@@ -811,11 +1059,10 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		for _, tx := range drop {
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "gasTipCap", tx.GasTipCap(), "gasFeeCap", tx.GasFeeCap())
 			underpricedTxMeter.Mark(1)
-			pool.removeTx(tx.Hash(), false)
+			pool.evictTx(tx.Hash(), false, evictUnderpriced)
 		}
 	}
 	// Try to replace an existing transaction in the pending pool
-	from, _ := types.Sender(pool.signer, tx) // already validated
 	if list := pool.pending[from]; list != nil && list.Overlaps(tx) {
 		// Nonce already pending, check if required price bump is met
 		inserted, old := list.Add(tx, pool.currentState, pool.config.PriceBump, pool.chainconfig, pool.currentHead)
@@ -826,12 +1073,14 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		// New transaction is better, replace old one
 		if old != nil {
 			pool.all.Remove(old.Hash())
+			pool.evicted.record(types.Transactions{old}, evictReplaced)
 			pool.calculateTxsLifecycle(types.Transactions{old}, time.Now())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
 		}
 		pool.all.Add(tx, isLocal)
 		pool.priced.Put(tx, isLocal)
+		pool.evicted.forget(hash)
 		pool.journalTx(from, tx)
 		pool.queueTxEvent(tx)
 		log.Trace("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.To())
@@ -879,6 +1128,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 	// Discard any previous transaction and mark this
 	if old != nil {
 		pool.all.Remove(old.Hash())
+		pool.evicted.record(types.Transactions{old}, evictReplaced)
 		pool.priced.Removed(1)
 		pool.calculateTxsLifecycle(types.Transactions{old}, time.Now())
 		queuedReplaceMeter.Mark(1)
@@ -894,6 +1144,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 	if addAll {
 		pool.all.Add(tx, local)
 		pool.priced.Put(tx, local)
+		pool.evicted.forget(hash)
 	}
 	// If we never record the heartbeat, do it right now.
 	if _, exist := pool.beats[from]; !exist {
@@ -909,6 +1160,10 @@ func (pool *TxPool) journalTx(from common.Address, tx *types.Transaction) {
 	if pool.journal == nil || !pool.locals.contains(from) {
 		return
 	}
+	// Private and conditional transactions are never journaled, see journaled
+	if pool.private.contains(tx.Hash()) || tx.Conditional() != nil {
+		return
+	}
 	if err := pool.journal.insert(tx); err != nil {
 		log.Warn("Failed to journal local transaction", "err", err)
 	}
@@ -929,6 +1184,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	if !inserted {
 		// An older transaction was better, discard this
 		pool.all.Remove(hash)
+		pool.evicted.record(types.Transactions{tx}, evictReplacement)
 		pool.calculateTxsLifecycle(types.Transactions{tx}, time.Now())
 		pool.priced.Removed(1)
 		pendingDiscardMeter.Mark(1)
@@ -937,6 +1193,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	// Otherwise discard any previous transaction and mark this
 	if old != nil {
 		pool.all.Remove(old.Hash())
+		pool.evicted.record(types.Transactions{old}, evictReplaced)
 		pool.calculateTxsLifecycle(types.Transactions{old}, time.Now())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
@@ -968,6 +1225,70 @@ func (pool *TxPool) AddLocal(tx *types.Transaction) error {
 	return errs[0]
 }
 
+// AddPrivate enqueues a single local transaction into the pool like AddLocal, but
+// keeps it from the network: it is neither announced nor served to peers, and
+// it is dropped if not included within the given lifetime. A zero lifetime
+// uses the configured default, and longer ones are capped to it.
+func (pool *TxPool) AddPrivate(tx *types.Transaction, lifetime time.Duration) error {
+	hash := tx.Hash()
+	if pool.all.Get(hash) != nil {
+		log.Trace("Discarding already known private transaction", "hash", hash)
+		knownTxMeter.Mark(1)
+		return ErrAlreadyKnown
+	}
+	// Mark the transaction before adding it, so that the handler never sees it
+	// as a public one.
+	pool.private.add(hash, pool.privateDeadline(lifetime), false)
+	if err := pool.AddLocal(tx); err != nil {
+		pool.private.remove(hash)
+		return err
+	}
+	return nil
+}
+
+// TrackPrivate tracks the status of a private transaction forwarded to another
+// node instead of being added to the pool. The same lifetime rules as in
+// AddPrivate apply.
+func (pool *TxPool) TrackPrivate(hash common.Hash, lifetime time.Duration) {
+	pool.private.add(hash, pool.privateDeadline(lifetime), true)
+}
+
+// privateDeadline returns the deadline of a private transaction submitted now
+// with the requested lifetime.
+func (pool *TxPool) privateDeadline(lifetime time.Duration) time.Time {
+	if lifetime <= 0 || lifetime > pool.config.PrivateLifetime {
+		lifetime = pool.config.PrivateLifetime
+	}
+	return time.Now().Add(lifetime)
+}
+
+// IsPrivate returns whether the transaction with the given hash was submitted
+// privately and has not expired yet.
+func (pool *TxPool) IsPrivate(hash common.Hash) bool {
+	return pool.private.contains(hash)
+}
+
+// GetPublic returns a transaction if it is contained in the pool and is not
+// private, and nil otherwise.
+func (pool *TxPool) GetPublic(hash common.Hash) *types.Transaction {
+	if pool.private.contains(hash) {
+		return nil
+	}
+	return pool.all.Get(hash)
+}
+
+// PrivateStatus returns the tracking information of a private transaction. The
+// information is available until a while after the deadline of the transaction.
+func (pool *TxPool) PrivateStatus(hash common.Hash) (PrivateTxInfo, bool) {
+	return pool.private.get(hash)
+}
+
+// Eviction returns the record of a transaction dropped from the pool without
+// being included within the eviction retention window, if any.
+func (pool *TxPool) Eviction(hash common.Hash) (TxEviction, bool) {
+	return pool.evicted.get(hash)
+}
+
 // AddRemotes enqueues a batch of transactions into the pool if they are valid. If the
 // senders are not among the locally tracked ones, full pricing constraints will apply.
 //
@@ -997,12 +1318,135 @@ func (pool *TxPool) AddRemote(tx *types.Transaction) error {
 	return errs[0]
 }
 
+// SetEvictionDatabase sets the database the evictions are recorded in, so that
+// they are kept across restarts. Evictions recorded before are forgotten.
+func (pool *TxPool) SetEvictionDatabase(db ethdb.KeyValueStore) {
+	pool.evicted.setDatabase(db)
+}
+
+// SetCircuitChecker sets the check run on new transactions to reject the ones
+// that would overflow the circuit capacity of an empty block.
+func (pool *TxPool) SetCircuitChecker(checker CircuitChecker) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.circuitChecker = checker
+}
+
+// SetMaintenance sets whether new transactions are rejected with
+// ErrSequencerMaintenance, as the sequencer is in maintenance and builds no
+// block to include them in.
+func (pool *TxPool) SetMaintenance(enabled bool) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.maintenance = enabled
+}
+
+// Policy returns the sender admission policy of the pool.
+func (pool *TxPool) Policy() TxPolicy {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.policy.policy
+}
+
+// SetPolicy replaces the sender admission policy of the pool, persisting it if
+// a policy file is configured. The transactions of the senders the new policy
+// rejects are dropped, while rate limits only apply to new transactions.
+func (pool *TxPool) SetPolicy(policy TxPolicy) error {
+	if err := policy.validate(); err != nil {
+		return err
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if pool.config.PolicyFile != "" {
+		if err := saveTxPolicy(pool.config.PolicyFile, policy); err != nil {
+			return err
+		}
+	}
+	pool.policy = newTxPolicy(policy)
+
+	var dropped int
+	for _, txs := range []map[common.Address]*txList{pool.pending, pool.queue} {
+		for addr, list := range txs {
+			if pool.policy.excluded(addr) == nil {
+				continue
+			}
+			for _, tx := range list.Flatten() {
+				pool.evictTx(tx.Hash(), true, evictPolicy)
+				dropped++
+			}
+		}
+	}
+	log.Info("Updated txpool policy", "deny", len(policy.Deny), "allow", len(policy.Allow), "allowlistOnly", policy.AllowlistOnly, "rateLimit", policy.RateLimit, "dropped", dropped)
+	return nil
+}
+
+// precheck runs the checks of add that don't modify the pool on a new
+// transaction: validation, the sender policy, and whether it is underpriced or
+// fails to replace the transaction with the same nonce, so that no circuit
+// capacity check is spent on transactions rejected anyway.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) precheck(tx *types.Transaction, local bool) error {
+	isLocal := local || pool.locals.containsTx(tx)
+	if err := pool.validateTx(tx, isLocal); err != nil {
+		return err
+	}
+	from, _ := types.Sender(pool.signer, tx) // already validated
+	if err := pool.policy.admit(from, !isLocal, time.Now()); err != nil {
+		return err
+	}
+	if uint64(pool.all.Slots()+numSlots(tx)) > pool.config.GlobalSlots+pool.config.GlobalQueue {
+		if !isLocal && pool.priced.Underpriced(tx) {
+			return ErrUnderpriced
+		}
+	}
+	for _, list := range []*txList{pool.pending[from], pool.queue[from]} {
+		if list != nil && list.Overlaps(tx) {
+			if accepted, _ := list.Accepts(tx, pool.currentState, pool.config.PriceBump, pool.chainconfig, pool.currentHead); !accepted {
+				return ErrReplaceUnderpriced
+			}
+		}
+	}
+	return nil
+}
+
+// checkCircuitCapacity runs the circuit capacity check on a new transaction, if
+// a checker is set. Transactions the estimate can't tell apart are traced within
+// the check budget, and admitted unchecked over it: the sequencer still checks
+// them when building blocks, so a burst of transactions never gets valid ones
+// rejected.
+func (pool *TxPool) checkCircuitCapacity(checker CircuitChecker, tx *types.Transaction) error {
+	fits, err := checker.EstimateTx(tx)
+	if err == nil && fits {
+		circuitEstimatedTxMeter.Mark(1)
+		return nil
+	}
+	if err == nil {
+		if !pool.circuitBudget.Allow() {
+			log.Trace("Admitting transaction over circuit capacity check budget", "hash", tx.Hash())
+			circuitUncheckedTxMeter.Mark(1)
+			return nil
+		}
+		err = checker.CheckTx(tx)
+	}
+	if err != nil {
+		log.Trace("Discarding transaction overflowing circuit capacity", "hash", tx.Hash(), "err", err)
+		circuitOverflowTxMeter.Mark(1)
+	}
+	return err
+}
+
 // addTxs attempts to queue a batch of transactions if they are valid.
 func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 	// Filter out known ones without obtaining the pool lock or recovering signatures
 	var (
-		errs = make([]error, len(txs))
-		news = make([]*types.Transaction, 0, len(txs))
+		errs  = make([]error, len(txs))
+		news  = make([]*types.Transaction, 0, len(txs))
+		slots = make([]int, 0, len(txs)) // Index of each new transaction in errs
 	)
 	for i, tx := range txs {
 		// If the transaction is known, pre-set the error slot
@@ -1022,23 +1466,59 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 		}
 		// Accumulate all unknown transactions for deeper processing
 		news = append(news, tx)
+		slots = append(slots, i)
 	}
 	if len(news) == 0 {
 		return errs
 	}
+	// Reject transactions that could never fit in a block. The cheap checks run
+	// first under the lock, then the circuit capacity checks without it.
+	pool.mu.RLock()
+	checker, maintenance := pool.circuitChecker, pool.maintenance
+	pool.mu.RUnlock()
+
+	// Nothing is queued for inclusion while the sequencer is in maintenance,
+	// whether submitted locally or received from the network
+	if maintenance {
+		for _, slot := range slots {
+			errs[slot] = ErrSequencerMaintenance
+		}
+		maintenanceTxMeter.Mark(int64(len(news)))
+		return errs
+	}
+	if checker != nil {
+		pool.mu.Lock()
+		for i, tx := range news {
+			errs[slots[i]] = pool.precheck(tx, local)
+		}
+		pool.mu.Unlock()
+
+		var (
+			checked      = make([]*types.Transaction, 0, len(news))
+			checkedSlots = make([]int, 0, len(news))
+		)
+		for i, tx := range news {
+			if errs[slots[i]] == nil {
+				errs[slots[i]] = pool.checkCircuitCapacity(checker, tx)
+			}
+			if errs[slots[i]] == nil {
+				checked = append(checked, tx)
+				checkedSlots = append(checkedSlots, slots[i])
+			}
+		}
+		news, slots = checked, checkedSlots
+		if len(news) == 0 {
+			return errs
+		}
+	}
 
 	// Process all the new transaction and merge any errors into the original slice
 	pool.mu.Lock()
-	newErrs, dirtyAddrs := pool.addTxsLocked(news, local)
+	newErrs, dirtyAddrs := pool.addTxsLocked(news, local, false)
 	pool.mu.Unlock()
 
-	var nilSlot = 0
-	for _, err := range newErrs {
-		for errs[nilSlot] != nil {
-			nilSlot++
-		}
-		errs[nilSlot] = err
-		nilSlot++
+	for i, err := range newErrs {
+		errs[slots[i]] = err
 	}
 	// Reorg the pool internals if needed and return
 	done := pool.requestPromoteExecutables(dirtyAddrs)
@@ -1048,13 +1528,14 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 	return errs
 }
 
-// addTxsLocked attempts to queue a batch of transactions if they are valid.
+// addTxsLocked attempts to queue a batch of transactions if they are valid,
+// reinject being set for the transactions of reorged blocks.
 // The transaction pool lock must be held.
-func (pool *TxPool) addTxsLocked(txs []*types.Transaction, local bool) ([]error, *accountSet) {
+func (pool *TxPool) addTxsLocked(txs []*types.Transaction, local, reinject bool) ([]error, *accountSet) {
 	dirty := newAccountSet(pool.signer)
 	errs := make([]error, len(txs))
 	for i, tx := range txs {
-		replaced, err := pool.add(tx, local)
+		replaced, err := pool.add(tx, local, reinject)
 		errs[i] = err
 		if err == nil && !replaced {
 			dirty.addTx(tx)
@@ -1104,9 +1585,35 @@ func (pool *TxPool) RemoveTx(hash common.Hash, outofbound bool) {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
+	pool.evictTx(hash, outofbound, evictMiner)
+}
+
+// evictTx removes a transaction dropped without being included, recording the
+// reason of its eviction.
+func (pool *TxPool) evictTx(hash common.Hash, outofbound bool, reason string) {
+	if tx := pool.all.Get(hash); tx != nil {
+		pool.evicted.record(types.Transactions{tx}, reason)
+	}
 	pool.removeTx(hash, outofbound)
 }
 
+// recordUnpayable records the eviction of transactions dropped for being too
+// costly, telling apart the ones exceeding the block gas limit.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) recordUnpayable(drops types.Transactions) {
+	var noFunds, overGas types.Transactions
+	for _, tx := range drops {
+		if tx.Gas() > pool.currentMaxGas {
+			overGas = append(overGas, tx)
+		} else {
+			noFunds = append(noFunds, tx)
+		}
+	}
+	pool.evicted.record(noFunds, evictNoFunds)
+	pool.evicted.record(overGas, evictGasLimit)
+}
+
 // removeTx removes a single transaction from the queue, moving all subsequent
 // transactions back to the future queue.
 func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
@@ -1337,6 +1844,9 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 			txs = append(txs, set.Flatten()...)
 		}
 		pool.txFeed.Send(NewTxsEvent{txs})
+		if public := pool.private.filter(txs); len(public) > 0 {
+			pool.publicFeed.Send(NewTxsEvent{public})
+		}
 	}
 }
 
@@ -1422,7 +1932,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	senderCacher.recover(pool.signer, reinject)
-	pool.addTxsLocked(reinject, false)
+	pool.addTxsLocked(reinject, false, true)
 
 	// Update all fork indicator by next pending block number.
 	next := new(big.Int).Add(newHead.Number, big.NewInt(1))
@@ -1466,6 +1976,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 			pool.all.Remove(hash)
 			pool.calculateTxsLifecycle(types.Transactions{tx}, time.Now())
 		}
+		pool.recordUnpayable(drops)
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
 
@@ -1490,6 +2001,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 				pool.calculateTxsLifecycle(types.Transactions{tx}, time.Now())
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
+			pool.evicted.record(caps, evictAccountQueue)
 			queuedRateLimitMeter.Mark(int64(len(caps)))
 		}
 		// Mark all the items dropped as removed
@@ -1576,6 +2088,7 @@ func (pool *TxPool) truncatePending() {
 						pool.pendingNonces.setIfLower(offenders[i], tx.Nonce())
 						log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 					}
+					pool.evicted.record(caps, evictPendingOverflow)
 					pool.priced.Removed(len(caps))
 					pendingGauge.Dec(int64(len(caps)))
 					if pool.locals.contains(offenders[i]) {
@@ -1604,6 +2117,7 @@ func (pool *TxPool) truncatePending() {
 					pool.pendingNonces.setIfLower(addr, tx.Nonce())
 					log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 				}
+				pool.evicted.record(caps, evictPendingOverflow)
 				pool.priced.Removed(len(caps))
 				pendingGauge.Dec(int64(len(caps)))
 				if pool.locals.contains(addr) {
@@ -1645,7 +2159,7 @@ func (pool *TxPool) truncateQueue() {
 		// Drop all transactions if they are less than the overflow
 		if size := uint64(list.Len()); size <= drop {
 			for _, tx := range list.Flatten() {
-				pool.removeTx(tx.Hash(), true)
+				pool.evictTx(tx.Hash(), true, evictQueueOverflow)
 			}
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
@@ -1654,7 +2168,7 @@ func (pool *TxPool) truncateQueue() {
 		// Otherwise drop only last few transactions
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
-			pool.removeTx(txs[i].Hash(), true)
+			pool.evictTx(txs[i].Hash(), true, evictQueueOverflow)
 			drop--
 			queuedRateLimitMeter.Mark(1)
 		}
@@ -1690,6 +2204,7 @@ func (pool *TxPool) demoteUnexecutables() {
 			pool.all.Remove(hash)
 			pool.calculateTxsLifecycle(types.Transactions{tx}, time.Now())
 		}
+		pool.recordUnpayable(drops)
 		pendingNofundsMeter.Mark(int64(len(drops)))
 
 		for _, tx := range invalids {
@@ -24,6 +24,7 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -31,12 +32,14 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/event"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
 	"github.com/scroll-tech/go-ethereum/trie"
 )
 
@@ -58,6 +61,7 @@ var (
 func init() {
 	testTxPoolConfig = DefaultTxPoolConfig
 	testTxPoolConfig.Journal = ""
+	testTxPoolConfig.PolicyFile = ""
 
 	cpy0 := *params.TestNoL1DataFeeChainConfig
 	noL1DataFeeConfig = &cpy0
@@ -447,14 +451,14 @@ func TestTransactionChainFork(t *testing.T) {
 	resetState()
 
 	tx := transaction(0, 100000, key)
-	if _, err := pool.add(tx, false); err != nil {
+	if _, err := pool.add(tx, false, false); err != nil {
 		t.Error("didn't expect error", err)
 	}
 	pool.removeTx(tx.Hash(), true)
 
 	// reset the pool's internal state
 	resetState()
-	if _, err := pool.add(tx, false); err != nil {
+	if _, err := pool.add(tx, false, false); err != nil {
 		t.Error("didn't expect error", err)
 	}
 }
@@ -481,10 +485,10 @@ func TestTransactionDoubleNonce(t *testing.T) {
 	tx3, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(100), 1000000, big.NewInt(1), nil), signer, key)
 
 	// Add the first two transaction, ensure higher priced stays only
-	if replace, err := pool.add(tx1, false); err != nil || replace {
+	if replace, err := pool.add(tx1, false, false); err != nil || replace {
 		t.Errorf("first transaction insert failed (%v) or reported replacement (%v)", err, replace)
 	}
-	if replace, err := pool.add(tx2, false); err != nil || !replace {
+	if replace, err := pool.add(tx2, false, false); err != nil || !replace {
 		t.Errorf("second transaction insert failed (%v) or not reported replacement (%v)", err, replace)
 	}
 	<-pool.requestPromoteExecutables(newAccountSet(signer, addr))
@@ -496,7 +500,7 @@ func TestTransactionDoubleNonce(t *testing.T) {
 	}
 
 	// Add the third transaction and ensure it's not saved (smaller price)
-	pool.add(tx3, false)
+	pool.add(tx3, false, false)
 	<-pool.requestPromoteExecutables(newAccountSet(signer, addr))
 	if pool.pending[addr].Len() != 1 {
 		t.Error("expected 1 pending transactions, got", pool.pending[addr].Len())
@@ -519,7 +523,7 @@ func TestTransactionMissingNonce(t *testing.T) {
 	addr := crypto.PubkeyToAddress(key.PublicKey)
 	testAddBalance(pool, addr, big.NewInt(100000000000000))
 	tx := transaction(1, 100000, key)
-	if _, err := pool.add(tx, false); err != nil {
+	if _, err := pool.add(tx, false, false); err != nil {
 		t.Error("didn't expect error", err)
 	}
 	if len(pool.pending) != 0 {
@@ -1761,6 +1765,504 @@ func TestTransactionPoolUnderpricing(t *testing.T) {
 	}
 }
 
+// Tests that the L1 data fee of transactions is taken into account when ranking
+// them for eviction and when checking the price bump of replacements.
+func TestTransactionPoolUnderpricingL1DataFee(t *testing.T) {
+	t.Parallel()
+
+	// Create the pool with an L1 gas price oracle charging for blob space
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetState(rcfg.L1GasPriceOracleAddress, rcfg.L1BlobBaseFeeSlot, common.BigToHash(big.NewInt(1000)))
+	statedb.SetState(rcfg.L1GasPriceOracleAddress, rcfg.BlobScalarSlot, common.BigToHash(rcfg.Precision))
+	blockchain := &testBlockChain{1000000, statedb, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.GlobalSlots = 1
+	config.GlobalQueue = 1
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+	<-pool.initDoneCh
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(params.Ether))
+	}
+	// Replacing a large transaction requires bumping its total fee, so a 10%
+	// gas price bump alone is not enough
+	data := make([]byte, 1000)
+	rand.Read(data)
+	large := func(gasPrice int64) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(0), 50000, big.NewInt(gasPrice), data), types.HomesteadSigner{}, keys[1])
+		return tx
+	}
+	if err := pool.addRemoteSync(large(10)); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(large(11)); err != ErrReplaceUnderpriced {
+		t.Fatalf("replacement error mismatch: have %v, want %v", err, ErrReplaceUnderpriced)
+	}
+	replaced := large(15)
+	if err := pool.addRemoteSync(replaced); err != nil {
+		t.Fatalf("failed to replace transaction: %v", err)
+	}
+	// The large transaction with a lower gas price pays more in total than a
+	// small one because of its L1 data fee, so a new transaction must evict
+	// the small transaction
+	cheap := pricedTransaction(0, 50000, big.NewInt(20), keys[0])
+	if err := pool.addRemoteSync(cheap); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(pricedTransaction(0, 50000, big.NewInt(21), keys[2])); err != nil {
+		t.Fatalf("failed to add well priced transaction: %v", err)
+	}
+	if pool.Get(cheap.Hash()) != nil {
+		t.Fatalf("cheapest transaction by total fee not evicted")
+	}
+	if pool.Get(replaced.Hash()) == nil {
+		t.Fatalf("large transaction evicted despite its L1 data fee")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// testCircuitChecker is a CircuitChecker rejecting a fixed set of transactions,
+// and estimating that transactions up to a gas limit fit.
+type testCircuitChecker struct {
+	overflow map[common.Hash]bool
+	fitGas   uint64
+	checked  int
+}
+
+func (c *testCircuitChecker) EstimateTx(tx *types.Transaction) (bool, error) {
+	return tx.Gas() <= c.fitGas, nil
+}
+
+func (c *testCircuitChecker) CheckTx(tx *types.Transaction) error {
+	c.checked++
+	if c.overflow[tx.Hash()] {
+		return ErrCircuitCapacityOverflow
+	}
+	return nil
+}
+
+// Tests that transactions overflowing the circuit capacity are rejected, that
+// only valid transactions the estimate can't admit are checked, and that
+// transactions over the check budget are admitted unchecked.
+func TestTransactionPoolCircuitCheck(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{1000000, statedb, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.CircuitCheckBudget = 2
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+	<-pool.initDoneCh
+
+	key, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(params.Ether))
+
+	overflowing := transaction(1, 100000, key)
+	checker := &testCircuitChecker{overflow: map[common.Hash]bool{overflowing.Hash(): true}, fitGas: params.TxGas}
+	pool.SetCircuitChecker(checker)
+
+	// Transactions the estimate admits are not checked
+	if err := pool.AddRemote(transaction(0, params.TxGas, key)); err != nil {
+		t.Fatalf("failed to add estimated transaction: %v", err)
+	}
+	// Invalid transactions and failed replacements are not checked
+	if err := pool.AddRemote(pricedTransaction(0, 100000, big.NewInt(1), key)); !errors.Is(err, ErrReplaceUnderpriced) {
+		t.Fatalf("underpriced replacement error mismatch: have %v, want %v", err, ErrReplaceUnderpriced)
+	}
+	if err := pool.AddRemote(transaction(1, 100000000, key)); !errors.Is(err, ErrGasLimit) {
+		t.Fatalf("gas limit error mismatch: have %v, want %v", err, ErrGasLimit)
+	}
+	if checker.checked != 0 {
+		t.Fatalf("checked transaction count mismatch: have %d, want 0", checker.checked)
+	}
+	// Other transactions are checked within the budget, and admitted unchecked
+	// over it
+	if err := pool.AddRemote(overflowing); !errors.Is(err, ErrCircuitCapacityOverflow) {
+		t.Fatalf("overflowing transaction error mismatch: have %v, want %v", err, ErrCircuitCapacityOverflow)
+	}
+	if err := pool.AddRemote(transaction(2, 100000, key)); err != nil {
+		t.Fatalf("failed to add checked transaction: %v", err)
+	}
+	checker.overflow[transaction(3, 100000, key).Hash()] = true
+	if err := pool.AddRemote(transaction(3, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction over the check budget: %v", err)
+	}
+	if checker.checked != 2 {
+		t.Fatalf("checked transaction count mismatch: have %d, want 2", checker.checked)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that only the local public transactions pending for long are reported
+// as stale, to be rebroadcast.
+func TestTransactionPoolStale(t *testing.T) {
+	t.Parallel()
+
+	pool, local := setupTxPool()
+	defer pool.Stop()
+
+	remote, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(local.PublicKey), big.NewInt(params.Ether))
+	testAddBalance(pool, crypto.PubkeyToAddress(remote.PublicKey), big.NewInt(params.Ether))
+
+	public := transaction(0, 100000, local)
+	if err := pool.AddLocal(public); err != nil {
+		t.Fatalf("failed to add local transaction: %v", err)
+	}
+	if err := pool.AddPrivate(transaction(1, 100000, local), time.Minute); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(transaction(0, 100000, remote)); err != nil {
+		t.Fatalf("failed to add remote transaction: %v", err)
+	}
+	<-pool.requestPromoteExecutables(newAccountSet(pool.signer))
+
+	if stale := pool.Stale(time.Hour); len(stale) != 0 {
+		t.Fatalf("recent transactions reported stale: %v", stale)
+	}
+	if stale := pool.Stale(0); len(stale) != 1 || stale[0].Hash() != public.Hash() {
+		t.Fatalf("stale transactions mismatch: have %v, want %v", stale, public.Hash())
+	}
+}
+
+// Tests that private transactions are kept out of the journal and dropped once
+// their lifetime is over, while their status remains available.
+func TestTransactionPoolPrivate(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{1000000, statedb, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.PrivateLifetime = time.Minute
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+	<-pool.initDoneCh
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, addr, big.NewInt(params.Ether))
+
+	events, publicEvents := make(chan NewTxsEvent, 4), make(chan NewTxsEvent, 4)
+	sub, publicSub := pool.SubscribeNewTxsEvent(events), pool.SubscribePublicTxsEvent(publicEvents)
+	defer sub.Unsubscribe()
+	defer publicSub.Unsubscribe()
+
+	public, private := transaction(0, 100000, key), transaction(1, 100000, key)
+	if err := pool.AddLocal(public); err != nil {
+		t.Fatalf("failed to add public transaction: %v", err)
+	}
+	if err := pool.AddPrivate(private, time.Hour); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if err := pool.AddPrivate(public, 0); err != ErrAlreadyKnown {
+		t.Fatalf("known transaction error mismatch: have %v, want %v", err, ErrAlreadyKnown)
+	}
+	if pool.IsPrivate(public.Hash()) || !pool.IsPrivate(private.Hash()) {
+		t.Fatalf("private flags mismatch: public %v, private %v", pool.IsPrivate(public.Hash()), pool.IsPrivate(private.Hash()))
+	}
+	if pending, _ := pool.Stats(); pending != 2 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 2)
+	}
+	// Private transactions are only announced to the full subscription
+	if err := validateEvents(events, 2); err != nil {
+		t.Fatalf("transaction event firing failed: %v", err)
+	}
+	if err := validateEvents(publicEvents, 1); err != nil {
+		t.Fatalf("public transaction event firing failed: %v", err)
+	}
+	// Private transactions are left out of the public content
+	if pending, _ := pool.PublicContent(); len(pending[addr]) != 1 || pending[addr][0].Hash() != public.Hash() {
+		t.Fatalf("public content mismatch: have %v, want %v", pending[addr], public.Hash())
+	}
+	if pending, _ := pool.PublicContentFrom(addr); len(pending) != 1 || pending[0].Hash() != public.Hash() {
+		t.Fatalf("public account content mismatch: have %v, want %v", pending, public.Hash())
+	}
+	if pool.GetPublic(private.Hash()) != nil || pool.GetPublic(public.Hash()) == nil {
+		t.Fatalf("public lookup mismatch")
+	}
+	pool.mu.Lock()
+	journaled := pool.journaled()
+	pool.mu.Unlock()
+	for _, list := range journaled {
+		for _, tx := range list {
+			if tx.Hash() == private.Hash() {
+				t.Fatalf("private transaction journaled")
+			}
+		}
+	}
+	// The lifetime is capped to the configured one
+	info, ok := pool.PrivateStatus(private.Hash())
+	if !ok {
+		t.Fatalf("private transaction not tracked")
+	}
+	if lifetime := info.Deadline.Sub(info.Added); lifetime > config.PrivateLifetime {
+		t.Fatalf("private lifetime mismatch: have %v, want at most %v", lifetime, config.PrivateLifetime)
+	}
+	pool.mu.Lock()
+	pool.expirePrivate(info.Deadline.Add(time.Second))
+	pool.mu.Unlock()
+
+	if pool.Has(private.Hash()) || !pool.Has(public.Hash()) {
+		t.Fatalf("expired transaction not dropped")
+	}
+	if info, ok := pool.PrivateStatus(private.Hash()); !ok || !info.Expired {
+		t.Fatalf("expired status mismatch: have %v (tracked %v), want expired", info, ok)
+	}
+	if pool.IsPrivate(private.Hash()) {
+		t.Fatalf("expired transaction still private")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that conditional transactions are only admitted if their conditions
+// hold for the next block, and are never journaled.
+func TestTransactionPoolConditional(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{1000000, statedb, new(event.Feed)}
+
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+	<-pool.initDoneCh
+
+	key, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(params.Ether))
+
+	contract, slot, value := common.Address{0xc0}, common.Hash{0x01}, common.Hash{0x02}
+	pool.mu.Lock()
+	pool.currentState.SetState(contract, slot, value)
+	pool.mu.Unlock()
+
+	// The pool is at the genesis block, the next block is block 1
+	head, next := (*hexutil.Big)(big.NewInt(0)), (*hexutil.Big)(big.NewInt(1))
+	tests := []struct {
+		cond *types.TransactionConditional
+		err  error
+	}{
+		{&types.TransactionConditional{BlockNumberMin: (*hexutil.Big)(big.NewInt(2))}, ErrConditionNotMet},
+		{&types.TransactionConditional{BlockNumberMax: head}, ErrConditionNotMet},
+		{&types.TransactionConditional{KnownAccounts: types.KnownAccounts{contract: {StorageSlots: map[common.Hash]common.Hash{slot: {}}}}}, ErrConditionNotMet},
+		{&types.TransactionConditional{KnownAccounts: types.KnownAccounts{contract: {StorageRoot: &common.Hash{}}}}, ErrConditionNotMet},
+		{&types.TransactionConditional{BlockNumberMin: next, BlockNumberMax: next, KnownAccounts: types.KnownAccounts{contract: {StorageSlots: map[common.Hash]common.Hash{slot: value}}}}, nil},
+	}
+	for i, tt := range tests {
+		tx := transaction(0, 100000, key)
+		tx.SetConditional(tt.cond)
+		if err := pool.AddLocal(tx); !errors.Is(err, tt.err) {
+			t.Fatalf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	pool.mu.Lock()
+	journaled := pool.journaled()
+	pool.mu.Unlock()
+	if len(journaled) != 0 {
+		t.Fatalf("conditional transaction journaled")
+	}
+}
+
+// Tests that the sender policy rejects denied and rate limited senders, drops
+// the transactions of newly rejected senders and is persisted across restarts.
+func TestTransactionPoolPolicy(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{1000000, statedb, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.PolicyFile = filepath.Join(t.TempDir(), "policy.json")
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+	<-pool.initDoneCh
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	addrs := make([]common.Address, len(keys))
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+		testAddBalance(pool, addrs[i], big.NewInt(params.Ether))
+	}
+	if err := pool.AddRemote(transaction(0, 100000, keys[0])); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	// Deny the first sender and rate limit the others, except the allowed one
+	policy := TxPolicy{RateLimit: 0.001, RateBurst: 2, Deny: addrs[:1], Allow: addrs[2:]}
+	if err := pool.SetPolicy(policy); err != nil {
+		t.Fatalf("failed to set policy: %v", err)
+	}
+	if pending, queued := pool.Stats(); pending+queued != 0 {
+		t.Fatalf("transactions of denied sender not dropped: %d pending, %d queued", pending, queued)
+	}
+	if err := pool.AddRemote(transaction(0, 100000, keys[0])); !errors.Is(err, ErrSenderDenied) {
+		t.Fatalf("denied sender error mismatch: have %v, want %v", err, ErrSenderDenied)
+	}
+	for i := uint64(0); i < policy.RateBurst; i++ {
+		if err := pool.AddRemote(transaction(i, 100000, keys[1])); err != nil {
+			t.Fatalf("failed to add transaction %d within burst: %v", i, err)
+		}
+		// Transactions rejected by the pool don't count towards the rate limit
+		if i == 0 {
+			if err := pool.AddRemote(pricedTransaction(i, 90000, big.NewInt(1), keys[1])); !errors.Is(err, ErrReplaceUnderpriced) {
+				t.Fatalf("underpriced replacement error mismatch: have %v, want %v", err, ErrReplaceUnderpriced)
+			}
+		}
+	}
+	if err := pool.AddRemote(transaction(policy.RateBurst, 100000, keys[1])); !errors.Is(err, ErrSenderRateLimited) {
+		t.Fatalf("rate limited sender error mismatch: have %v, want %v", err, ErrSenderRateLimited)
+	}
+	// Reinjected and local transactions are not rate limited
+	pool.mu.Lock()
+	errs, _ := pool.addTxsLocked([]*types.Transaction{transaction(policy.RateBurst, 100000, keys[1])}, false, true)
+	pool.mu.Unlock()
+	if errs[0] != nil {
+		t.Fatalf("failed to reinject transaction of rate limited sender: %v", errs[0])
+	}
+	if err := pool.AddLocal(transaction(policy.RateBurst+1, 100000, keys[1])); err != nil {
+		t.Fatalf("failed to add local transaction of rate limited sender: %v", err)
+	}
+	for i := uint64(0); i <= policy.RateBurst; i++ {
+		if err := pool.AddRemote(transaction(i, 100000, keys[2])); err != nil {
+			t.Fatalf("failed to add transaction %d of allowed sender: %v", i, err)
+		}
+	}
+	// The policy is reloaded on restart, and allowlist-only mode drops the
+	// transactions of the senders not allowed
+	restarted := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer restarted.Stop()
+	if have := restarted.Policy(); len(have.Deny) != 1 || have.Deny[0] != addrs[0] || have.RateBurst != policy.RateBurst {
+		t.Fatalf("persisted policy mismatch: have %+v, want %+v", have, policy)
+	}
+	policy.AllowlistOnly = true
+	if err := pool.SetPolicy(policy); err != nil {
+		t.Fatalf("failed to set policy: %v", err)
+	}
+	if pending, _ := pool.Stats(); pending != int(policy.RateBurst)+1 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, policy.RateBurst+1)
+	}
+	if err := pool.AddRemote(transaction(0, 100000, keys[1])); !errors.Is(err, ErrSenderNotAllowed) {
+		t.Fatalf("not allowed sender error mismatch: have %v, want %v", err, ErrSenderNotAllowed)
+	}
+	if err := pool.SetPolicy(TxPolicy{RateLimit: 1}); err == nil {
+		t.Fatalf("invalid policy accepted")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that transactions dropped from the pool without being included are
+// remembered along with the reason of their eviction.
+func TestTransactionPoolEvictions(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(params.Ether))
+
+	// Replaced transactions are evicted
+	replaced, replacement := pricedTransaction(0, 100000, big.NewInt(1), key), pricedTransaction(0, 100000, big.NewInt(2), key)
+	if err := pool.addRemoteSync(replaced); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(replacement); err != nil {
+		t.Fatalf("failed to add replacement transaction: %v", err)
+	}
+	if eviction, ok := pool.Eviction(replaced.Hash()); !ok || eviction.Reason != evictReplaced {
+		t.Fatalf("replaced eviction mismatch: have %v (%v), want %q", eviction, ok, evictReplaced)
+	}
+	// Transactions removed by the miner are evicted
+	pool.RemoveTx(replacement.Hash(), true)
+	eviction, ok := pool.Eviction(replacement.Hash())
+	if !ok || eviction.Reason != evictMiner {
+		t.Fatalf("removed eviction mismatch: have %v (%v), want %q", eviction, ok, evictMiner)
+	}
+	if eviction.Added.UnixMilli() != replacement.Time().UnixMilli() || eviction.Evicted.Before(eviction.Added) {
+		t.Fatalf("eviction times mismatch: added %v, evicted %v", eviction.Added, eviction.Evicted)
+	}
+	// Evictions are forgotten when transactions enter the pool again
+	if err := pool.addRemoteSync(replacement); err != nil {
+		t.Fatalf("failed to add evicted transaction: %v", err)
+	}
+	if _, ok := pool.Eviction(replacement.Hash()); ok {
+		t.Fatalf("eviction of pooled transaction remembered")
+	}
+	// Transactions below the minimum gas price are evicted
+	pool.SetGasPrice(big.NewInt(3))
+	if eviction, ok := pool.Eviction(replacement.Hash()); !ok || eviction.Reason != evictGasPrice {
+		t.Fatalf("gas price eviction mismatch: have %v (%v), want %q", eviction, ok, evictGasPrice)
+	}
+	// Transactions exceeding the block gas limit are evicted
+	overGas := pricedTransaction(1, 100000, big.NewInt(3), key)
+	if err := pool.addRemoteSync(pricedTransaction(0, 21000, big.NewInt(3), key)); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(overGas); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	atomic.StoreUint64(&pool.chain.(*testBlockChain).gasLimit, 50000)
+	<-pool.requestReset(nil, nil)
+
+	if eviction, ok := pool.Eviction(overGas.Hash()); !ok || eviction.Reason != evictGasLimit {
+		t.Fatalf("gas limit eviction mismatch: have %v (%v), want %q", eviction, ok, evictGasLimit)
+	}
+}
+
+// Tests that evictions are persisted in the database set on the pool, and are
+// only remembered for the retention window.
+func TestTransactionPoolEvictionRetention(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	db := rawdb.NewMemoryDatabase()
+	pool.SetEvictionDatabase(db)
+
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(params.Ether))
+
+	tx := transaction(0, 100000, key)
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	pool.RemoveTx(tx.Hash(), true)
+
+	if record := rawdb.ReadTxEviction(db, tx.Hash()); record == nil || record.Reason != evictMiner {
+		t.Fatalf("persisted eviction mismatch: have %v, want %q", record, evictMiner)
+	}
+	// Evictions outside the retention window are neither returned nor kept
+	if n := pool.evicted.prune(time.Now()); n != 0 {
+		t.Fatalf("evictions pruned within retention window: %d", n)
+	}
+	pool.evicted.retention = 0
+	if _, ok := pool.Eviction(tx.Hash()); ok {
+		t.Fatalf("eviction returned outside retention window")
+	}
+	if n := pool.evicted.prune(time.Now().Add(time.Millisecond)); n != 1 {
+		t.Fatalf("pruned evictions mismatch: have %d, want %d", n, 1)
+	}
+	if rawdb.ReadTxEviction(db, tx.Hash()) != nil {
+		t.Fatalf("pruned eviction still persisted")
+	}
+}
+
 // Tests that more expensive transactions push out cheap ones from the pool, but
 // without producing instability by creating gaps that start jumping transactions
 // back and forth between queued/pending.
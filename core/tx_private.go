@@ -0,0 +1,133 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+)
+
+// privateTxRetention is the amount of time the expiry of a private transaction
+// is remembered for status queries.
+const privateTxRetention = time.Hour
+
+// PrivateTxInfo is the tracking information of a private transaction.
+type PrivateTxInfo struct {
+	Added    time.Time // Time the transaction was submitted
+	Deadline time.Time // Time after which the transaction is dropped if not included
+	Expired  bool      // Whether the transaction reached its deadline
+	Remote   bool      // Whether the transaction was forwarded instead of kept in the pool
+}
+
+// privateTxSet tracks the transactions submitted privately to the pool. They
+// are never announced to peers and are dropped if not included before their
+// deadline. The set has its own lock so that the network handler can query it
+// without contending on the pool lock.
+type privateTxSet struct {
+	txs  map[common.Hash]*PrivateTxInfo
+	lock sync.RWMutex
+}
+
+// newPrivateTxSet creates a new, empty private transaction set.
+func newPrivateTxSet() *privateTxSet {
+	return &privateTxSet{txs: make(map[common.Hash]*PrivateTxInfo)}
+}
+
+// add starts tracking a private transaction until the given deadline.
+func (s *privateTxSet) add(hash common.Hash, deadline time.Time, remote bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.txs[hash] = &PrivateTxInfo{Added: time.Now(), Deadline: deadline, Remote: remote}
+}
+
+// remove stops tracking a private transaction.
+func (s *privateTxSet) remove(hash common.Hash) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.txs, hash)
+}
+
+// contains checks if a transaction is private and not yet expired.
+func (s *privateTxSet) contains(hash common.Hash) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	info := s.txs[hash]
+	return info != nil && !info.Expired
+}
+
+// filter returns the transactions of the list that are not private, reusing
+// the list if none is.
+func (s *privateTxSet) filter(txs types.Transactions) types.Transactions {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var kept types.Transactions
+	for i, tx := range txs {
+		if info := s.txs[tx.Hash()]; info != nil && !info.Expired {
+			if kept == nil {
+				kept = append(make(types.Transactions, 0, len(txs)), txs[:i]...)
+			}
+			continue
+		}
+		if kept != nil {
+			kept = append(kept, tx)
+		}
+	}
+	if kept == nil {
+		return txs
+	}
+	return kept
+}
+
+// get returns a copy of the tracking information of a private transaction.
+func (s *privateTxSet) get(hash common.Hash) (PrivateTxInfo, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if info := s.txs[hash]; info != nil {
+		return *info, true
+	}
+	return PrivateTxInfo{}, false
+}
+
+// expire marks the private transactions past their deadline as expired and
+// returns those still in the pool, which the caller should drop. Expired
+// transactions are forgotten after privateTxRetention.
+func (s *privateTxSet) expire(now time.Time, inPool func(common.Hash) bool) []common.Hash {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var dropped []common.Hash
+	for hash, info := range s.txs {
+		switch {
+		case info.Expired:
+			if now.Sub(info.Deadline) > privateTxRetention {
+				delete(s.txs, hash)
+			}
+		case now.After(info.Deadline):
+			info.Expired = true
+			if inPool(hash) {
+				dropped = append(dropped, hash)
+			}
+		}
+	}
+	return dropped
+}
+
+// count returns the number of private transactions that have not expired.
+func (s *privateTxSet) count() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var n int
+	for _, info := range s.txs {
+		if !info.Expired {
+			n++
+		}
+	}
+	return n
+}
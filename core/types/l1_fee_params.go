@@ -0,0 +1,14 @@
+package types
+
+import "math/big"
+
+// L1FeeParams are the parameters of the L1GasPriceOracle contract that the L1
+// data fee of a transaction is derived from.
+type L1FeeParams struct {
+	L1BaseFee     *big.Int
+	Overhead      *big.Int
+	Scalar        *big.Int
+	L1BlobBaseFee *big.Int
+	CommitScalar  *big.Int
+	BlobScalar    *big.Int
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 	"math/big"
 
 	"github.com/scroll-tech/go-ethereum/common"
@@ -24,6 +25,29 @@ type BlockTrace struct {
 	StartL1QueueIndex uint64             `json:"startL1QueueIndex"`
 }
 
+// BatchWitness contains a deduplicated witness for executing a contiguous range
+// of blocks, as required by chunk and batch provers.
+type BatchWitness struct {
+	ChainID uint64 `json:"chainID"`
+	Version string `json:"version"`
+	// StorageTrace holds proofs against the state root before the first block
+	// for every account and storage slot touched anywhere in the range, and the
+	// deletion proofs of all blocks.
+	StorageTrace *StorageTrace        `json:"storageTrace"`
+	Bytecodes    []*BytecodeTrace     `json:"codes"`
+	Blocks       []*BatchWitnessBlock `json:"blocks"`
+}
+
+// BatchWitnessBlock contains the per-block part of a BatchWitness.
+type BatchWitnessBlock struct {
+	Coinbase          *AccountWrapper    `json:"coinbase"`
+	Header            *Header            `json:"header"`
+	Transactions      []*TransactionData `json:"transactions"`
+	ExecutionResults  []*ExecutionResult `json:"executionResults"`
+	WithdrawTrieRoot  common.Hash        `json:"withdraw_trie_root,omitempty"`
+	StartL1QueueIndex uint64             `json:"startL1QueueIndex"`
+}
+
 // BytecodeTrace stores all accessed bytecodes
 type BytecodeTrace struct {
 	CodeSize         uint64        `json:"codeSize"`
@@ -177,6 +201,76 @@ func NewTransactionData(tx *Transaction, blockNumber uint64, config *params.Chai
 	return result
 }
 
+// ToTransaction reconstructs the signed transaction from its trace
+// representation and checks that it hashes to the recorded transaction hash.
+func (t *TransactionData) ToTransaction() (*Transaction, error) {
+	data, err := hexutil.Decode(t.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data of tx %s: %w", t.TxHash, err)
+	}
+	var inner TxData
+	switch t.Type {
+	case LegacyTxType:
+		inner = &LegacyTx{
+			Nonce:    t.Nonce,
+			GasPrice: t.GasPrice.ToInt(),
+			Gas:      t.Gas,
+			To:       t.To,
+			Value:    t.Value.ToInt(),
+			Data:     data,
+			V:        t.V.ToInt(),
+			R:        t.R.ToInt(),
+			S:        t.S.ToInt(),
+		}
+	case AccessListTxType:
+		inner = &AccessListTx{
+			ChainID:    t.ChainId.ToInt(),
+			Nonce:      t.Nonce,
+			GasPrice:   t.GasPrice.ToInt(),
+			Gas:        t.Gas,
+			To:         t.To,
+			Value:      t.Value.ToInt(),
+			Data:       data,
+			AccessList: t.AccessList,
+			V:          t.V.ToInt(),
+			R:          t.R.ToInt(),
+			S:          t.S.ToInt(),
+		}
+	case DynamicFeeTxType:
+		inner = &DynamicFeeTx{
+			ChainID:    t.ChainId.ToInt(),
+			Nonce:      t.Nonce,
+			GasTipCap:  t.GasTipCap.ToInt(),
+			GasFeeCap:  t.GasFeeCap.ToInt(),
+			Gas:        t.Gas,
+			To:         t.To,
+			Value:      t.Value.ToInt(),
+			Data:       data,
+			AccessList: t.AccessList,
+			V:          t.V.ToInt(),
+			R:          t.R.ToInt(),
+			S:          t.S.ToInt(),
+		}
+	case L1MessageTxType:
+		// The queue index of L1 messages is recorded as the nonce.
+		inner = &L1MessageTx{
+			QueueIndex: t.Nonce,
+			Gas:        t.Gas,
+			To:         t.To,
+			Value:      t.Value.ToInt(),
+			Data:       data,
+			Sender:     t.From,
+		}
+	default:
+		return nil, fmt.Errorf("tx %s: %w", t.TxHash, ErrTxTypeNotSupported)
+	}
+	tx := NewTx(inner)
+	if hash := tx.Hash().String(); hash != t.TxHash {
+		return nil, fmt.Errorf("tx hash mismatch: have %s, want %s", hash, t.TxHash)
+	}
+	return tx, nil
+}
+
 // WrapProof turn the bytes array into proof type (array of hexutil.Bytes)
 func WrapProof(proofBytes [][]byte) (wrappedProof []hexutil.Bytes) {
 	wrappedProof = make([]hexutil.Bytes, len(proofBytes))
@@ -61,6 +61,8 @@ type Transaction struct {
 	inner TxData    // Consensus contents of a transaction
 	time  time.Time // Time first seen locally (spam avoidance)
 
+	conditional atomic.Value // Preconditions of a conditional transaction, not encoded
+
 	// caches
 	hash atomic.Value
 	size atomic.Value
@@ -0,0 +1,93 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+)
+
+// TransactionConditional is the set of preconditions of a conditional
+// transaction, which may only be included in a block where all of them hold.
+// The conditions are not part of the transaction encoding.
+type TransactionConditional struct {
+	KnownAccounts  KnownAccounts   `json:"knownAccounts"`
+	BlockNumberMin *hexutil.Big    `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *hexutil.Big    `json:"blockNumberMax,omitempty"`
+	TimestampMin   *hexutil.Uint64 `json:"timestampMin,omitempty"`
+	TimestampMax   *hexutil.Uint64 `json:"timestampMax,omitempty"`
+}
+
+// KnownAccounts maps accounts to their expected storage.
+type KnownAccounts map[common.Address]KnownAccount
+
+// KnownAccount is the expected storage of an account, given either as its
+// storage root or as the values of some of its slots. It is encoded in JSON
+// as either the root hash or an object mapping slots to values.
+type KnownAccount struct {
+	StorageRoot  *common.Hash
+	StorageSlots map[common.Hash]common.Hash
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a KnownAccount) MarshalJSON() ([]byte, error) {
+	if a.StorageRoot != nil {
+		return json.Marshal(a.StorageRoot)
+	}
+	return json.Marshal(a.StorageSlots)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *KnownAccount) UnmarshalJSON(input []byte) error {
+	var root common.Hash
+	if err := json.Unmarshal(input, &root); err == nil {
+		*a = KnownAccount{StorageRoot: &root}
+		return nil
+	}
+	var slots map[common.Hash]common.Hash
+	if err := json.Unmarshal(input, &slots); err != nil {
+		return errors.New("known account must be a storage root or a map of storage slots")
+	}
+	*a = KnownAccount{StorageSlots: slots}
+	return nil
+}
+
+// Conditional returns the preconditions of the transaction if it was submitted
+// as a conditional transaction, or nil.
+func (tx *Transaction) Conditional() *TransactionConditional {
+	if cond := tx.conditional.Load(); cond != nil {
+		return cond.(*TransactionConditional)
+	}
+	return nil
+}
+
+// SetConditional attaches preconditions to the transaction. They are kept in
+// memory only and lost when the transaction is encoded.
+func (tx *Transaction) SetConditional(cond *TransactionConditional) {
+	tx.conditional.Store(cond)
+}
+
+// Cost returns the number of storage lookups required to check the conditions.
+func (c *TransactionConditional) Cost() int {
+	var cost int
+	for _, account := range c.KnownAccounts {
+		if account.StorageRoot != nil {
+			cost++
+		} else {
+			cost += len(account.StorageSlots)
+		}
+	}
+	return cost
+}
+
+// Validate checks that the block number and timestamp ranges are not empty.
+func (c *TransactionConditional) Validate() error {
+	if c.BlockNumberMin != nil && c.BlockNumberMax != nil && c.BlockNumberMin.ToInt().Cmp(c.BlockNumberMax.ToInt()) > 0 {
+		return errors.New("block number minimum exceeds maximum")
+	}
+	if c.TimestampMin != nil && c.TimestampMax != nil && *c.TimestampMin > *c.TimestampMax {
+		return errors.New("timestamp minimum exceeds maximum")
+	}
+	return nil
+}
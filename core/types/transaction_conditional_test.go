@@ -0,0 +1,47 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+)
+
+func TestTransactionConditionalJSON(t *testing.T) {
+	input := `{"knownAccounts":{` +
+		`"0x00000000000000000000000000000000000000aa":"0x0000000000000000000000000000000000000000000000000000000000000001",` +
+		`"0x00000000000000000000000000000000000000bb":{"0x0000000000000000000000000000000000000000000000000000000000000002":"0x0000000000000000000000000000000000000000000000000000000000000003"}},` +
+		`"blockNumberMin":"0x1","timestampMax":"0x64"}`
+
+	var cond TransactionConditional
+	if err := json.Unmarshal([]byte(input), &cond); err != nil {
+		t.Fatalf("failed to decode conditions: %v", err)
+	}
+	root := common.HexToHash("0x01")
+	want := KnownAccounts{
+		common.HexToAddress("0xaa"): {StorageRoot: &root},
+		common.HexToAddress("0xbb"): {StorageSlots: map[common.Hash]common.Hash{common.HexToHash("0x02"): common.HexToHash("0x03")}},
+	}
+	if !reflect.DeepEqual(cond.KnownAccounts, want) {
+		t.Fatalf("known accounts mismatch: have %v, want %v", cond.KnownAccounts, want)
+	}
+	if cond.Cost() != 2 {
+		t.Fatalf("cost mismatch: have %d, want 2", cond.Cost())
+	}
+	output, err := json.Marshal(&cond)
+	if err != nil {
+		t.Fatalf("failed to encode conditions: %v", err)
+	}
+	if string(output) != input {
+		t.Fatalf("encoding mismatch:\nhave %s\nwant %s", output, input)
+	}
+	if err := json.Unmarshal([]byte(`{"knownAccounts":{"0x00000000000000000000000000000000000000aa":1}}`), &cond); err == nil {
+		t.Fatalf("invalid known account accepted")
+	}
+	min, max := hexutil.Uint64(2), hexutil.Uint64(1)
+	if err := (&TransactionConditional{TimestampMin: &min, TimestampMax: &max}).Validate(); err == nil {
+		t.Fatalf("empty timestamp range accepted")
+	}
+}
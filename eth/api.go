@@ -37,7 +37,9 @@ import (
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/internal/ethapi"
 	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/miner"
 	"github.com/scroll-tech/go-ethereum/rlp"
+	"github.com/scroll-tech/go-ethereum/rollup/sync_service"
 	"github.com/scroll-tech/go-ethereum/rpc"
 	"github.com/scroll-tech/go-ethereum/trie"
 )
@@ -113,6 +115,12 @@ func (api *PrivateMinerAPI) Stop() {
 	api.e.StopMining()
 }
 
+// Status returns the block production status of the miner, including the block
+// being built and the reason the last one was closed for.
+func (api *PrivateMinerAPI) Status() miner.Status {
+	return api.e.Miner().Status()
+}
+
 // SetExtra sets the extra data string that is included when this miner mines a block.
 func (api *PrivateMinerAPI) SetExtra(extra string) (bool, error) {
 	if err := api.e.Miner().SetExtra([]byte(extra)); err != nil {
@@ -253,6 +261,30 @@ func (api *PrivateAdminAPI) ImportChain(file string) (bool, error) {
 	return true, nil
 }
 
+// TxpoolPolicy replaces the sender admission policy of the transaction pool if
+// one is given, and returns the policy in effect. The policy is persisted and
+// survives restarts.
+func (api *PrivateAdminAPI) TxpoolPolicy(policy *core.TxPolicy) (*core.TxPolicy, error) {
+	if policy != nil {
+		if err := api.eth.TxPool().SetPolicy(*policy); err != nil {
+			return nil, err
+		}
+	}
+	current := api.eth.TxPool().Policy()
+	return &current, nil
+}
+
+// SequencerMaintenance enters or leaves sequencer maintenance, and returns the
+// resulting block production status. Entering it seals the block being built
+// and rejects new transactions until maintenance is left.
+func (api *PrivateAdminAPI) SequencerMaintenance(enable bool) (*miner.Status, error) {
+	if err := api.eth.Miner().SetMaintenance(enable); err != nil {
+		return nil, err
+	}
+	status := api.eth.Miner().Status()
+	return &status, nil
+}
+
 // PublicDebugAPI is the collection of Ethereum full node APIs exposed
 // over the public debugging endpoint.
 type PublicDebugAPI struct {
@@ -354,7 +386,11 @@ func (api *PrivateDebugAPI) GetBadBlocks(ctx context.Context) ([]*BadBlockArgs,
 // AccountRangeMaxResults is the maximum number of results to be returned per call
 const AccountRangeMaxResults = 256
 
-// AccountRange enumerates all accounts in the given block and start point in paging request
+// AccountRange enumerates all accounts in the given block and start point in paging request.
+// Accounts are returned in the iteration order of the state trie, which for the zktrie
+// is the little-endian bit-path order of the hashed addresses, not their numeric order.
+// The returned next key is the hashed address to pass as start to fetch the following
+// page; it is not an upper bound of the keys returned so far.
 func (api *PublicDebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start []byte, maxResults int, nocode, nostorage, incompletes bool) (state.IteratorDump, error) {
 	var stateDb *state.StateDB
 	var err error
@@ -440,10 +476,14 @@ func (api *PrivateDebugAPI) StorageRangeAt(blockHash common.Hash, txIndex int, c
 func storageRangeAt(st state.Trie, start []byte, maxResult int) (StorageRangeResult, error) {
 	it := trie.NewIterator(st.NodeIterator(start))
 	result := StorageRangeResult{Storage: storageMap{}}
+	_, zktrie := st.(*trie.ZkTrie)
 	for i := 0; i < maxResult && it.Next(); i++ {
-		_, content, _, err := rlp.Split(it.Value)
-		if err != nil {
-			return StorageRangeResult{}, err
+		content := it.Value
+		if !zktrie {
+			var err error
+			if _, content, _, err = rlp.Split(it.Value); err != nil {
+				return StorageRangeResult{}, err
+			}
 		}
 		e := storageEntry{Value: common.BytesToHash(content)}
 		if preimage := st.GetKey(it.Key); preimage != nil {
@@ -668,6 +708,13 @@ func (api *ScrollAPI) GetLatestRelayedQueueIndex(ctx context.Context) (queueInde
 	return &lastIncluded, nil
 }
 
+// GetL1MessageBacklog returns the L1 messages synced from L1 but not processed
+// by the chain, with the age of the oldest one and whether it crossed the alert
+// threshold.
+func (api *ScrollAPI) GetL1MessageBacklog(ctx context.Context) sync_service.Backlog {
+	return api.eth.l1Backlog.Backlog()
+}
+
 // rpcMarshalBlock uses the generalized output filler, then adds the total difficulty field, which requires
 // a `ScrollAPI`.
 func (api *ScrollAPI) rpcMarshalBlock(ctx context.Context, b *types.Block, fullTx bool) (map[string]interface{}, error) {
@@ -682,7 +729,96 @@ func (api *ScrollAPI) rpcMarshalBlock(ctx context.Context, b *types.Block, fullT
 	} else {
 		fields["rowConsumption"] = nil
 	}
-	return fields, err
+	// The parameters in effect during the block are the ones after its parent,
+	// they are missing for blocks imported without state.
+	fields["l1FeeParams"] = newRPCL1FeeParams(rawdb.ReadL1FeeParams(api.eth.ChainDb(), b.ParentHash()))
+	receipts, err := api.eth.APIBackend.GetReceipts(ctx, b.Hash())
+	if err != nil {
+		return nil, err
+	}
+	totalL1Fee := new(big.Int)
+	for _, receipt := range receipts {
+		if receipt.L1Fee != nil {
+			totalL1Fee.Add(totalL1Fee, receipt.L1Fee)
+		}
+	}
+	fields["totalL1Fee"] = (*hexutil.Big)(totalL1Fee)
+	return fields, nil
+}
+
+// rpcL1FeeParams is the RPC-layer representation of the L1GasPriceOracle
+// parameters the L1 data fee is derived from.
+type rpcL1FeeParams struct {
+	L1BaseFee     *hexutil.Big `json:"l1BaseFee"`
+	Overhead      *hexutil.Big `json:"overhead"`
+	Scalar        *hexutil.Big `json:"scalar"`
+	L1BlobBaseFee *hexutil.Big `json:"l1BlobBaseFee"`
+	CommitScalar  *hexutil.Big `json:"commitScalar"`
+	BlobScalar    *hexutil.Big `json:"blobScalar"`
+}
+
+func newRPCL1FeeParams(params *types.L1FeeParams) *rpcL1FeeParams {
+	if params == nil {
+		return nil
+	}
+	return &rpcL1FeeParams{
+		L1BaseFee:     (*hexutil.Big)(params.L1BaseFee),
+		Overhead:      (*hexutil.Big)(params.Overhead),
+		Scalar:        (*hexutil.Big)(params.Scalar),
+		L1BlobBaseFee: (*hexutil.Big)(params.L1BlobBaseFee),
+		CommitScalar:  (*hexutil.Big)(params.CommitScalar),
+		BlobScalar:    (*hexutil.Big)(params.BlobScalar),
+	}
+}
+
+// feeHistoryResult is the result of scroll_feeHistory: the eth_feeHistory result
+// extended with the L1 data fee statistics of every block.
+type feeHistoryResult struct {
+	OldestBlock  *hexutil.Big      `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big  `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big    `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64         `json:"gasUsedRatio"`
+	L1FeeReward  [][]*hexutil.Big  `json:"l1FeeReward,omitempty"`
+	TotalL1Fee   []*hexutil.Big    `json:"totalL1Fee,omitempty"`
+	L1FeeParams  []*rpcL1FeeParams `json:"l1FeeParams,omitempty"`
+}
+
+// FeeHistory returns the fee history of eth_feeHistory together with the L1 data
+// fee statistics of every block in the range: the requested percentiles of the
+// L1 data fees paid by its L2 transactions, the total L1 data fee paid in the
+// block and the L1GasPriceOracle parameters at the start of the block.
+func (api *ScrollAPI) FeeHistory(ctx context.Context, blockCount rpc.DecimalOrHex, lastBlock rpc.BlockNumber, rewardPercentiles []float64, l1FeePercentiles []float64) (*feeHistoryResult, error) {
+	history, err := api.eth.APIBackend.gpo.L1FeeHistory(ctx, int(blockCount), lastBlock, rewardPercentiles, l1FeePercentiles)
+	if err != nil {
+		return nil, err
+	}
+	toHex := func(values []*big.Int) []*hexutil.Big {
+		result := make([]*hexutil.Big, len(values))
+		for i, v := range values {
+			result[i] = (*hexutil.Big)(v)
+		}
+		return result
+	}
+	results := &feeHistoryResult{
+		OldestBlock:  (*hexutil.Big)(history.OldestBlock),
+		GasUsedRatio: history.GasUsedRatio,
+	}
+	for _, reward := range history.Reward {
+		results.Reward = append(results.Reward, toHex(reward))
+	}
+	for _, reward := range history.L1FeeReward {
+		results.L1FeeReward = append(results.L1FeeReward, toHex(reward))
+	}
+	if history.BaseFee != nil {
+		results.BaseFee = toHex(history.BaseFee)
+	}
+	if history.TotalL1Fee != nil {
+		results.TotalL1Fee = toHex(history.TotalL1Fee)
+	}
+	for _, params := range history.L1FeeParams {
+		results.L1FeeParams = append(results.L1FeeParams, newRPCL1FeeParams(params))
+	}
+	return results, nil
 }
 
 // GetBlockByHash returns the requested block. When fullTx is true all transactions in the block are returned in full
@@ -764,6 +900,42 @@ func (api *ScrollAPI) EstimateL1DataFee(ctx context.Context, args ethapi.Transac
 	return &result, nil
 }
 
+// feeForecastResult is the result of scroll_feeForecast.
+type feeForecastResult struct {
+	OldestBlock   *hexutil.Big   `json:"oldestBlock"`
+	Samples       hexutil.Uint64 `json:"samples"`
+	Current       *hexutil.Big   `json:"current"`
+	Forecast      []*hexutil.Big `json:"forecast"`
+	L1BaseFee     []*hexutil.Big `json:"l1BaseFee"`
+	L1BlobBaseFee []*hexutil.Big `json:"l1BlobBaseFee"`
+}
+
+// FeeForecast returns a forecast of the L1 data fee of a signed transaction of
+// the given encoded size: the fee under the latest L1GasPriceOracle parameters,
+// and the requested percentiles of the fee, the L1 base fee and the L1 blob
+// base fee over the given number of recent blocks.
+func (api *ScrollAPI) FeeForecast(ctx context.Context, size hexutil.Uint64, blockCount rpc.DecimalOrHex, percentiles []float64) (*feeForecastResult, error) {
+	forecast, err := api.eth.APIBackend.gpo.L1FeeForecast(ctx, uint64(size), int(blockCount), percentiles)
+	if err != nil {
+		return nil, err
+	}
+	toHex := func(values []*big.Int) []*hexutil.Big {
+		result := make([]*hexutil.Big, len(values))
+		for i, v := range values {
+			result[i] = (*hexutil.Big)(v)
+		}
+		return result
+	}
+	return &feeForecastResult{
+		OldestBlock:   (*hexutil.Big)(forecast.OldestBlock),
+		Samples:       hexutil.Uint64(forecast.Samples),
+		Current:       (*hexutil.Big)(forecast.Current),
+		Forecast:      toHex(forecast.Forecast),
+		L1BaseFee:     toHex(forecast.L1BaseFee),
+		L1BlobBaseFee: toHex(forecast.L1BlobBaseFee),
+	}, nil
+}
+
 // RPCTransaction is the standard RPC transaction return type with some additional skip-related fields.
 type RPCTransaction struct {
 	ethapi.RPCTransaction
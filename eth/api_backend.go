@@ -35,8 +35,10 @@ import (
 	"github.com/scroll-tech/go-ethereum/eth/gasprice"
 	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/event"
+	"github.com/scroll-tech/go-ethereum/internal/ethapi"
 	"github.com/scroll-tech/go-ethereum/miner"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/preconf"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
 
@@ -245,6 +247,20 @@ func (b *EthAPIBackend) SubscribePendingLogsEvent(ch chan<- []*types.Log) event.
 	return b.eth.miner.SubscribePendingLogs(ch)
 }
 
+func (b *EthAPIBackend) SubscribePendingBlockEvent(ch chan<- core.PendingBlockEvent) event.Subscription {
+	return b.eth.miner.SubscribePendingBlock(ch)
+}
+
+func (b *EthAPIBackend) Preconfirmations() preconf.Source {
+	if preconfs := b.eth.miner.Preconfirmations(); preconfs != nil {
+		return preconfs
+	}
+	if b.eth.preconfFollower != nil {
+		return b.eth.preconfFollower
+	}
+	return nil
+}
+
 func (b *EthAPIBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
 	return b.eth.BlockChain().SubscribeChainEvent(ch)
 }
@@ -262,21 +278,69 @@ func (b *EthAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscri
 }
 
 func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	if b.eth.miner.InMaintenance() {
+		return core.ErrSequencerMaintenance
+	}
 	// will `VerifyFee` & `validateTx` in txPool.AddLocal
 	return b.eth.txPool.AddLocal(signedTx)
 }
 
+func (b *EthAPIBackend) ValidateTx(signedTx *types.Transaction) error {
+	return b.eth.txPool.Validate(signedTx)
+}
+
+func (b *EthAPIBackend) TxForwarder() *ethapi.TxForwarder {
+	return b.eth.txForwarder
+}
+
+func (b *EthAPIBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction, lifetime time.Duration) error {
+	if b.eth.privateForwarder == nil {
+		// Only the sequencer keeps private transactions, a follower would
+		// hold them until they expire.
+		if !b.eth.IsMining() {
+			return core.ErrPrivateTxUnsupported
+		}
+		if b.eth.miner.InMaintenance() {
+			return core.ErrSequencerMaintenance
+		}
+		return b.eth.txPool.AddPrivate(signedTx, lifetime)
+	}
+	if err := b.eth.privateForwarder.SendPrivate(ctx, signedTx, lifetime); err != nil {
+		return err
+	}
+	// Track the transaction locally too, so that its status can be queried
+	// and it is not propagated if it reaches this node.
+	b.eth.txPool.TrackPrivate(signedTx.Hash(), lifetime)
+	return nil
+}
+
+func (b *EthAPIBackend) GetPrivateTxStatus(hash common.Hash) (core.PrivateTxInfo, bool) {
+	return b.eth.txPool.PrivateStatus(hash)
+}
+
+func (b *EthAPIBackend) GetPoolTxStatus(hash common.Hash) core.TxStatus {
+	return b.eth.txPool.Status([]common.Hash{hash})[0]
+}
+
+func (b *EthAPIBackend) GetPoolTxEviction(hash common.Hash) (core.TxEviction, bool) {
+	return b.eth.txPool.Eviction(hash)
+}
+
 func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 	pending := b.eth.txPool.Pending(false)
 	var txs types.Transactions
 	for _, batch := range pending {
-		txs = append(txs, batch...)
+		for _, tx := range batch {
+			if !b.eth.txPool.IsPrivate(tx.Hash()) {
+				txs = append(txs, tx)
+			}
+		}
 	}
 	return txs, nil
 }
 
 func (b *EthAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction {
-	return b.eth.txPool.Get(hash)
+	return b.eth.txPool.GetPublic(hash)
 }
 
 func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
@@ -297,11 +361,11 @@ func (b *EthAPIBackend) StatsWithMinBaseFee(minBaseFee *big.Int) (pending int, q
 }
 
 func (b *EthAPIBackend) TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
-	return b.eth.TxPool().Content()
+	return b.eth.TxPool().PublicContent()
 }
 
 func (b *EthAPIBackend) TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
-	return b.eth.TxPool().ContentFrom(addr)
+	return b.eth.TxPool().PublicContentFrom(addr)
 }
 
 func (b *EthAPIBackend) TxPool() *core.TxPool {
@@ -309,7 +373,7 @@ func (b *EthAPIBackend) TxPool() *core.TxPool {
 }
 
 func (b *EthAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
-	return b.eth.TxPool().SubscribeNewTxsEvent(ch)
+	return b.eth.TxPool().SubscribePublicTxsEvent(ch)
 }
 
 func (b *EthAPIBackend) SyncProgress() ethereum.SyncProgress {
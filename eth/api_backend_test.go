@@ -0,0 +1,155 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/consensus/ethash"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/eth/ethconfig"
+	"github.com/scroll-tech/go-ethereum/node"
+	"github.com/scroll-tech/go-ethereum/params"
+)
+
+// newTestBackend creates a full node funding the given account, with the
+// transaction pool journal disabled.
+func newTestBackend(t *testing.T, addr common.Address) (*node.Node, *Ethereum) {
+	t.Helper()
+
+	stack, err := node.New(&node.Config{})
+	if err != nil {
+		t.Fatalf("can't create node: %v", err)
+	}
+	config := &ethconfig.Config{
+		Genesis: &core.Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   core.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		},
+		Ethash: ethash.Config{PowMode: ethash.ModeFake},
+		TxPool: core.DefaultTxPoolConfig,
+	}
+	config.TxPool.Journal = ""
+	config.TxPool.PolicyFile = ""
+	ethservice, err := New(stack, config, nil)
+	if err != nil {
+		t.Fatalf("can't create eth service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("can't start node: %v", err)
+	}
+	return stack, ethservice
+}
+
+// Tests that followers reject private transactions they can't forward, and that
+// private transactions are left out of the public pool queries and events.
+func TestPrivateTransactions(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	stack, ethservice := newTestBackend(t, addr)
+	defer stack.Close()
+	backend := ethservice.APIBackend
+
+	signer := types.LatestSigner(params.TestChainConfig)
+	newTx := func(nonce uint64) *types.Transaction {
+		tx, err := types.SignTx(types.NewTransaction(nonce, addr, big.NewInt(1), params.TxGas, big.NewInt(params.InitialBaseFee*2), nil), signer, key)
+		if err != nil {
+			t.Fatalf("failed to sign transaction: %v", err)
+		}
+		return tx
+	}
+	public, private := newTx(0), newTx(1)
+
+	// The node is not sequencing and doesn't forward private transactions
+	if err := backend.SendPrivateTx(context.Background(), private, time.Minute); !errors.Is(err, core.ErrPrivateTxUnsupported) {
+		t.Fatalf("follower private transaction error mismatch: have %v, want %v", err, core.ErrPrivateTxUnsupported)
+	}
+	events := make(chan core.NewTxsEvent, 4)
+	sub := backend.SubscribeNewTxsEvent(events)
+	defer sub.Unsubscribe()
+
+	if err := backend.SendTx(context.Background(), public); err != nil {
+		t.Fatalf("failed to add public transaction: %v", err)
+	}
+	if err := ethservice.TxPool().AddPrivate(private, time.Minute); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	// Only the public transaction is announced to subscribers
+	select {
+	case ev := <-events:
+		if len(ev.Txs) != 1 || ev.Txs[0].Hash() != public.Hash() {
+			t.Fatalf("announced transactions mismatch: have %v, want %v", ev.Txs, public.Hash())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("public transaction not announced")
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("private transaction announced: %v", ev.Txs)
+	case <-time.After(50 * time.Millisecond):
+	}
+	// Private transactions are left out of the pool content and lookups
+	if pending, _ := backend.TxPoolContent(); len(pending[addr]) != 1 || pending[addr][0].Hash() != public.Hash() {
+		t.Fatalf("pool content mismatch: have %v, want %v", pending[addr], public.Hash())
+	}
+	if pending, _ := backend.TxPoolContentFrom(addr); len(pending) != 1 || pending[0].Hash() != public.Hash() {
+		t.Fatalf("account pool content mismatch: have %v, want %v", pending, public.Hash())
+	}
+	if txs, _ := backend.GetPoolTransactions(); len(txs) != 1 || txs[0].Hash() != public.Hash() {
+		t.Fatalf("pool transactions mismatch: have %v, want %v", txs, public.Hash())
+	}
+	if backend.GetPoolTransaction(private.Hash()) != nil {
+		t.Fatalf("private transaction returned by lookup")
+	}
+	if backend.GetPoolTransaction(public.Hash()) == nil {
+		t.Fatalf("public transaction not returned by lookup")
+	}
+}
+
+// Tests that a heartbeat interval below the clique block period is rejected.
+func TestHeartbeatBelowCliquePeriod(t *testing.T) {
+	stack, err := node.New(&node.Config{})
+	if err != nil {
+		t.Fatalf("can't create node: %v", err)
+	}
+	defer stack.Close()
+
+	chainConfig := *params.AllCliqueProtocolChanges
+	chainConfig.Clique = &params.CliqueConfig{Period: 3, Epoch: 30000}
+	config := &ethconfig.Config{
+		Genesis: &core.Genesis{
+			Config:    &chainConfig,
+			ExtraData: append(append(make([]byte, 32), common.Address{1}.Bytes()...), make([]byte, 65)...),
+			BaseFee:   big.NewInt(params.InitialBaseFee),
+		},
+		TxPool: core.DefaultTxPoolConfig,
+	}
+	config.Miner.HeartbeatInterval = 2 * time.Second
+	if _, err := New(stack, config, nil); err == nil || !strings.Contains(err.Error(), "heartbeat") {
+		t.Fatalf("heartbeat interval error mismatch: have %v", err)
+	}
+}
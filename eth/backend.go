@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/scroll-tech/go-ethereum/accounts"
+	"github.com/scroll-tech/go-ethereum/accounts/external"
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/consensus"
@@ -56,6 +57,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/rlp"
 	"github.com/scroll-tech/go-ethereum/rollup/ccc"
+	"github.com/scroll-tech/go-ethereum/rollup/preconf"
 	"github.com/scroll-tech/go-ethereum/rollup/rollup_sync_service"
 	"github.com/scroll-tech/go-ethereum/rollup/sync_service"
 	"github.com/scroll-tech/go-ethereum/rpc"
@@ -71,7 +73,11 @@ type Ethereum struct {
 
 	// Handlers
 	txPool             *core.TxPool
+	privateForwarder   *ethapi.TxForwarder // Forwarder of private transactions to the sequencers, if any
+	txForwarder        *ethapi.TxForwarder // Forwarder of RPC transactions to the sequencers, if any
+	preconfFollower    *preconf.Follower   // Relay of the sequencer preconfirmations, if any
 	syncService        *sync_service.SyncService
+	l1Backlog          *sync_service.BacklogWatchdog
 	rollupSyncService  *rollup_sync_service.RollupSyncService
 	asyncChecker       *ccc.AsyncChecker
 	blockchain         *core.BlockChain
@@ -85,6 +91,7 @@ type Ethereum struct {
 	eventMux       *event.TypeMux
 	engine         consensus.Engine
 	accountManager *accounts.Manager
+	sealSigner     accounts.Wallet // External signer sealing blocks, if any
 
 	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
@@ -144,6 +151,11 @@ func New(stack *node.Node, config *ethconfig.Config, l1Client sync_service.EthCl
 	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
 
+	// A heartbeat shorter than the block period would seal blocks ahead of it
+	if interval := config.Miner.HeartbeatInterval; interval > 0 && chainConfig.Clique != nil && interval < time.Duration(chainConfig.Clique.Period)*time.Second {
+		return nil, fmt.Errorf("heartbeat interval %v below the clique period of %ds", interval, chainConfig.Clique.Period)
+	}
+
 	if err := pruner.RecoverPruning(stack.ResolvePath(""), chainDb, stack.ResolvePath(config.TrieCleanCacheJournal)); err != nil {
 		log.Error("Failed to recover state", "error", err)
 	}
@@ -218,15 +230,53 @@ func New(stack *node.Node, config *ethconfig.Config, l1Client sync_service.EthCl
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
+	if config.TxPool.PolicyFile != "" {
+		config.TxPool.PolicyFile = stack.ResolvePath(config.TxPool.PolicyFile)
+	}
 	eth.txPool = core.NewTxPool(config.TxPool, chainConfig, eth.blockchain)
+	eth.txPool.SetEvictionDatabase(chainDb)
+	if config.TxPool.CircuitCheck {
+		eth.txPool.SetCircuitChecker(ccc.NewTxChecker(eth.blockchain, config.CCCMaxWorkers))
+	}
+	if len(config.TxForwardURLs) > 0 {
+		eth.txForwarder, err = ethapi.NewTxForwarder(config.TxForwardURLs, config.TxForwardRetries, config.TxForwardNoLocal)
+		if err != nil {
+			return nil, fmt.Errorf("cannot initialize transaction forwarding: %w", err)
+		}
+	}
+	eth.privateForwarder = eth.txForwarder
+	if config.PrivateTxForward != "" {
+		eth.privateForwarder, err = ethapi.NewTxForwarder([]string{config.PrivateTxForward}, config.TxForwardRetries, true)
+		if err != nil {
+			return nil, fmt.Errorf("cannot initialize private transaction forwarding: %w", err)
+		}
+	}
+	if config.PreconfirmationsURL != "" {
+		engine, ok := eth.engine.(*clique.Clique)
+		if !ok {
+			return nil, errors.New("preconfirmations can only be verified with clique")
+		}
+		eth.preconfFollower = preconf.NewFollower(config.PreconfirmationsURL, func(signer common.Address) bool {
+			return engine.IsSigner(eth.blockchain, eth.blockchain.CurrentHeader(), signer)
+		}, eth.blockchain.CurrentHeader)
+	}
 
 	// initialize and start L1 message sync service
 	eth.syncService, err = sync_service.NewSyncService(context.Background(), chainConfig, stack.Config(), eth.chainDb, l1Client)
 	if err != nil {
 		return nil, fmt.Errorf("cannot initialize L1 sync service: %w", err)
 	}
+	if engine, ok := eth.engine.(*clique.Clique); ok {
+		eth.syncService.SetSignerUpdateHook(func(startBlock uint64) {
+			engine.InvalidateSnapshots(eth.blockchain, startBlock)
+		})
+	}
 	eth.syncService.Start()
 
+	// watch the L1 messages the sequencer keeps not processing
+	eth.l1Backlog = sync_service.NewBacklogWatchdog(eth.chainDb, eth.blockchain.CurrentHeader, config.L1MessageBacklogThreshold)
+	eth.l1Backlog.Start()
+
 	if config.EnableRollupVerify {
 		// initialize and start rollup event sync service
 		eth.rollupSyncService, err = rollup_sync_service.NewRollupSyncService(context.Background(), chainConfig, eth.chainDb, l1Client, eth.blockchain, stack)
@@ -257,7 +307,19 @@ func New(stack *node.Node, config *ethconfig.Config, l1Client sync_service.EthCl
 		return nil, err
 	}
 
-	eth.miner = miner.New(eth, &config.Miner, chainConfig, eth.EventMux(), eth.engine, eth.isLocalBlock)
+	var lease miner.Lease
+	if config.Miner.HALease != "" {
+		if lease, err = miner.NewLease(config.Miner.HALease); err != nil {
+			return nil, err
+		}
+	}
+	if config.Miner.Signer != "" {
+		if eth.sealSigner, err = external.NewExternalSigner(config.Miner.Signer); err != nil {
+			return nil, fmt.Errorf("cannot connect to block signer at %s: %w", config.Miner.Signer, err)
+		}
+		log.Info("Sealing blocks with external signer", "endpoint", config.Miner.Signer)
+	}
+	eth.miner = miner.New(eth, &config.Miner, chainConfig, eth.EventMux(), eth.engine, eth.isLocalBlock, lease)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
 
 	eth.APIBackend = &EthAPIBackend{stack.Config().ExtRPCEnabled(), stack.Config().AllowUnprotectedTxs, eth, nil}
@@ -330,8 +392,8 @@ func (s *Ethereum) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
-	// Append all the local APIs and return
-	return append(apis, []rpc.API{
+	// Append all the local APIs
+	apis = append(apis, []rpc.API{
 		{
 			Namespace: "eth",
 			Version:   "1.0",
@@ -382,6 +444,16 @@ func (s *Ethereum) APIs() []rpc.API {
 			Public:    false,
 		},
 	}...)
+	// Serve the lease of highly available sequencers on other hosts
+	if s.config.Miner.HALeaseServe != "" {
+		apis = append(apis, rpc.API{
+			Namespace: "lease",
+			Version:   "1.0",
+			Service:   miner.NewLeaseService(miner.NewFileLease(s.config.Miner.HALeaseServe)),
+			Public:    false,
+		})
+	}
+	return apis
 }
 
 func (s *Ethereum) ResetWithGenesisBlock(gb *types.Block) {
@@ -504,12 +576,20 @@ func (s *Ethereum) StartMining(threads int) error {
 			return fmt.Errorf("etherbase missing: %v", err)
 		}
 		if clique, ok := s.engine.(*clique.Clique); ok {
-			wallet, err := s.accountManager.Find(accounts.Account{Address: eb})
-			if wallet == nil || err != nil {
-				log.Error("Etherbase account unavailable locally", "err", err)
-				return fmt.Errorf("signer missing: %v", err)
+			// Blocks are signed by the external block signer if configured,
+			// without requiring it to list its accounts
+			wallet := s.sealSigner
+			if wallet == nil {
+				if wallet, err = s.accountManager.Find(accounts.Account{Address: eb}); wallet == nil || err != nil {
+					log.Error("Etherbase account unavailable locally", "err", err)
+					return fmt.Errorf("signer missing: %v", err)
+				}
 			}
+			clique.SetSignTimeout(s.config.Miner.SignerTimeout)
 			clique.Authorize(eb, wallet.SignData)
+			if preconfs := s.miner.Preconfirmations(); preconfs != nil {
+				preconfs.Authorize(eb, wallet.SignText)
+			}
 		}
 		// If mining is started, we can disable the transaction rejection mechanism
 		// introduced to speed sync times.
@@ -593,7 +673,17 @@ func (s *Ethereum) Stop() error {
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
 	s.txPool.Stop()
+	if s.privateForwarder != nil && s.privateForwarder != s.txForwarder {
+		s.privateForwarder.Close()
+	}
+	if s.txForwarder != nil {
+		s.txForwarder.Close()
+	}
+	if s.preconfFollower != nil {
+		s.preconfFollower.Close()
+	}
 	s.syncService.Stop()
+	s.l1Backlog.Stop()
 	if s.config.EnableRollupVerify {
 		s.rollupSyncService.Stop()
 	}
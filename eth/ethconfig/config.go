@@ -86,6 +86,9 @@ var Defaults = Config{
 		GasCeil:  8000000,
 		GasPrice: big.NewInt(params.GWei),
 		Recommit: 3 * time.Second,
+
+		HALeaseTimeout: miner.DefaultLeaseTimeout,
+		SignerTimeout:  clique.DefaultSignTimeout,
 	},
 	TxPool:        core.DefaultTxPoolConfig,
 	RPCGasCap:     50000000,
@@ -93,6 +96,8 @@ var Defaults = Config{
 	GPO:           FullNodeGPO,
 	RPCTxFeeCap:   1,  // 1 ether
 	MaxBlockRange: -1, // Default unconfigured value: no block range limit for backward compatibility
+
+	TxForwardRetries: 3,
 }
 
 func init() {
@@ -218,6 +223,26 @@ type Config struct {
 
 	// List of peer ids that take part in the shadow-fork
 	ShadowForkPeerIDs []string
+
+	// RPC endpoint of the sequencer private transactions are forwarded to.
+	// If empty, they are forwarded to TxForwardURLs if set, and otherwise
+	// kept in the local pool.
+	PrivateTxForward string
+
+	// RPC endpoints of the sequencers the transactions submitted over RPC are
+	// forwarded to, the number of retries on connection failures, and whether
+	// forwarded transactions skip the local pool.
+	TxForwardURLs    []string
+	TxForwardRetries int
+	TxForwardNoLocal bool
+
+	// RPC endpoint of the sequencer whose preconfirmations are verified and
+	// relayed to local subscribers. It must support subscriptions.
+	PreconfirmationsURL string
+
+	// Age of the oldest L1 message not processed by the chain beyond which the
+	// backlog is alerted. Zero selects the default threshold.
+	L1MessageBacklogThreshold time.Duration `toml:",omitempty"`
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain configuration.
@@ -28,12 +28,19 @@ import (
 	"github.com/scroll-tech/go-ethereum"
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/event"
+	"github.com/scroll-tech/go-ethereum/rollup/preconf"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
 
+// errPreconfirmationsUnavailable is returned when subscribing to preconfirmations
+// on a node that neither publishes nor relays them.
+var errPreconfirmationsUnavailable = errors.New("preconfirmations unavailable")
+
 // filter is a helper struct that holds meta information over the filter type
 // and associated subscription in the event system.
 type filter struct {
@@ -177,6 +184,110 @@ func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context) (*rpc.Su
 	return rpcSub, nil
 }
 
+// pendingBlockUpdate is the RPC representation of a pending block event.
+type pendingBlockUpdate struct {
+	Type             string                                `json:"type"` // "transaction", "sealed" or "aborted"
+	BlockNumber      hexutil.Uint64                        `json:"blockNumber"`
+	ParentHash       common.Hash                           `json:"parentHash"`
+	BlockHash        *common.Hash                          `json:"blockHash,omitempty"`
+	TransactionIndex *hexutil.Uint                         `json:"transactionIndex,omitempty"`
+	Transaction      *types.Transaction                    `json:"transaction,omitempty"`
+	Receipt          *types.Receipt                        `json:"receipt,omitempty"`
+	StateDiff        map[common.Address]*state.AccountDiff `json:"stateDiff,omitempty"`
+}
+
+func newPendingBlockUpdate(ev core.PendingBlockEvent) *pendingBlockUpdate {
+	update := &pendingBlockUpdate{
+		BlockNumber: hexutil.Uint64(ev.Header.Number.Uint64()),
+		ParentHash:  ev.Header.ParentHash,
+	}
+	switch {
+	case ev.Tx != nil:
+		index := hexutil.Uint(ev.Index)
+		update.Type = "transaction"
+		update.TransactionIndex = &index
+		update.Transaction = ev.Tx
+		update.Receipt = ev.Receipt
+		update.StateDiff = ev.StateDiff
+	case ev.Aborted:
+		update.Type = "aborted"
+	default:
+		update.Type = "sealed"
+		update.BlockHash = &ev.Hash
+	}
+	return update
+}
+
+// PendingBlock creates a subscription that streams the block being built by the
+// sequencer: every transaction appended to it with its receipt and the state of
+// the accounts it modified, followed by the sealing or abortion of the block.
+// Streaming starts with the next block built after subscribing.
+func (api *PublicFilterAPI) PendingBlock(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan core.PendingBlockEvent, 128)
+		pendingBlockSub := api.backend.SubscribePendingBlockEvent(events)
+
+		for {
+			select {
+			case ev := <-events:
+				notifier.Notify(rpcSub.ID, newPendingBlockUpdate(ev))
+			case <-rpcSub.Err():
+				pendingBlockSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				pendingBlockSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// Preconfirmations creates a subscription that is triggered each time the sequencer
+// preconfirms a transaction, i.e. the transaction passed execution and the circuit
+// capacity check of the block being built. Preconfirmations are signed by the
+// sequencer and verified by follower nodes before being relayed.
+func (api *PublicFilterAPI) Preconfirmations(ctx context.Context) (*rpc.Subscription, error) {
+	source := api.backend.Preconfirmations()
+	if source == nil {
+		return &rpc.Subscription{}, errPreconfirmationsUnavailable
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		preconfs := make(chan *preconf.Preconfirmation, 128)
+		preconfSub := source.Subscribe(preconfs)
+
+		for {
+			select {
+			case p := <-preconfs:
+				notifier.Notify(rpcSub.ID, p)
+			case <-rpcSub.Err():
+				preconfSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				preconfSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // NewBlockFilter creates a filter that fetches blocks that are imported into the chain.
 // It is part of the filter package since polling goes with eth_getFilterChanges.
 //
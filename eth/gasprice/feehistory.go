@@ -62,6 +62,11 @@ type processedFees struct {
 	reward               []*big.Int
 	baseFee, nextBaseFee *big.Int
 	gasUsedRatio         float64
+
+	// L1 data fee statistics, only filled in for L1FeeHistory
+	l1FeeReward []*big.Int
+	totalL1Fee  *big.Int
+	l1FeeParams *types.L1FeeParams
 }
 
 // txGasAndReward is sorted in ascending order based on reward
@@ -139,6 +144,51 @@ func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64) {
 	}
 }
 
+// processL1Fees fills in the L1 data fee statistics of a block: the total L1
+// data fee paid by its transactions, the requested percentiles of the L1 data
+// fee of its L2 transactions and the L1GasPriceOracle parameters in effect at
+// the start of the block. The block and its receipts must be present.
+func (oracle *Oracle) processL1Fees(ctx context.Context, bf *blockFees, percentiles []float64) {
+	if bf.block == nil || (bf.receipts == nil && len(bf.block.Transactions()) != 0) {
+		log.Error("Block or receipts are missing while L1 fees are requested")
+		return
+	}
+	if bf.blockNumber > 0 {
+		if parent, err := oracle.backend.HeaderByNumber(ctx, rpc.BlockNumber(bf.blockNumber-1)); err == nil && parent != nil {
+			if state, err := oracle.backend.StateAt(parent.Root); err == nil && state != nil {
+				bf.results.l1FeeParams = fees.ReadL1FeeParams(state)
+			}
+		}
+	}
+	bf.results.totalL1Fee = new(big.Int)
+	var l1Fees []*big.Int
+	for i, tx := range bf.block.Transactions() {
+		if tx.IsL1MessageTx() || bf.receipts[i].L1Fee == nil {
+			continue
+		}
+		bf.results.totalL1Fee.Add(bf.results.totalL1Fee, bf.receipts[i].L1Fee)
+		l1Fees = append(l1Fees, bf.receipts[i].L1Fee)
+	}
+	if len(percentiles) == 0 {
+		return
+	}
+	bf.results.l1FeeReward = make([]*big.Int, len(percentiles))
+	if len(l1Fees) == 0 {
+		for i := range bf.results.l1FeeReward {
+			bf.results.l1FeeReward[i] = new(big.Int)
+		}
+		return
+	}
+	sort.Slice(l1Fees, func(i, j int) bool { return l1Fees[i].Cmp(l1Fees[j]) < 0 })
+	for i, p := range percentiles {
+		index := int(math.Ceil(float64(len(l1Fees))*p/100)) - 1
+		if index < 0 {
+			index = 0
+		}
+		bf.results.l1FeeReward[i] = new(big.Int).Set(l1Fees[index])
+	}
+}
+
 // resolveBlockRange resolves the specified block range to absolute block numbers while also
 // enforcing backend specific limitations. The pending block and corresponding receipts are
 // also returned if requested and available.
@@ -206,11 +256,85 @@ func (oracle *Oracle) resolveBlockRange(ctx context.Context, lastBlock rpc.Block
 // Note: baseFee includes the next block after the newest of the returned range, because this
 // value can be derived from the newest block.
 func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+	oldestBlock, results, err := oracle.feeHistory(ctx, blocks, unresolvedLastBlock, rewardPercentiles, nil, false)
+	if err != nil || len(results) == 0 {
+		return common.Big0, nil, nil, nil, err
+	}
+	var (
+		reward       = make([][]*big.Int, len(results))
+		baseFee      = make([]*big.Int, len(results)+1)
+		gasUsedRatio = make([]float64, len(results))
+	)
+	for i, res := range results {
+		reward[i], baseFee[i], baseFee[i+1], gasUsedRatio[i] = res.reward, res.baseFee, res.nextBaseFee, res.gasUsedRatio
+	}
+	if len(rewardPercentiles) == 0 {
+		reward = nil
+	}
+	return new(big.Int).SetUint64(oldestBlock), reward, baseFee, gasUsedRatio, nil
+}
+
+// L1FeeHistory is the Scroll extension of FeeHistory. In addition to the data
+// returned by FeeHistory, it reports for every block of the range:
+//   - l1FeeReward: the requested percentiles of the L1 data fee of the L2 transactions
+//     in the block, weighted by transaction count
+//   - totalL1Fee: the sum of the L1 data fees paid in the block
+//   - l1FeeParams: the L1GasPriceOracle parameters at the start of the block, if the
+//     state of its parent is available
+func (oracle *Oracle) L1FeeHistory(ctx context.Context, blocks int, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles, l1FeePercentiles []float64) (*L1FeeHistory, error) {
+	for i, p := range l1FeePercentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+		}
+		if i > 0 && p < l1FeePercentiles[i-1] {
+			return nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, l1FeePercentiles[i-1], i, p)
+		}
+	}
+	oldestBlock, results, err := oracle.feeHistory(ctx, blocks, unresolvedLastBlock, rewardPercentiles, l1FeePercentiles, true)
+	if err != nil {
+		return nil, err
+	}
+	history := &L1FeeHistory{OldestBlock: new(big.Int)}
+	if len(results) == 0 {
+		return history, nil
+	}
+	history.OldestBlock.SetUint64(oldestBlock)
+	history.BaseFee = make([]*big.Int, len(results)+1)
+	for i, res := range results {
+		if len(rewardPercentiles) != 0 {
+			history.Reward = append(history.Reward, res.reward)
+		}
+		if len(l1FeePercentiles) != 0 {
+			history.L1FeeReward = append(history.L1FeeReward, res.l1FeeReward)
+		}
+		history.BaseFee[i], history.BaseFee[i+1] = res.baseFee, res.nextBaseFee
+		history.GasUsedRatio = append(history.GasUsedRatio, res.gasUsedRatio)
+		history.TotalL1Fee = append(history.TotalL1Fee, res.totalL1Fee)
+		history.L1FeeParams = append(history.L1FeeParams, res.l1FeeParams)
+	}
+	return history, nil
+}
+
+// L1FeeHistory is the result of Oracle.L1FeeHistory.
+type L1FeeHistory struct {
+	OldestBlock  *big.Int
+	Reward       [][]*big.Int
+	BaseFee      []*big.Int
+	GasUsedRatio []float64
+	L1FeeReward  [][]*big.Int
+	TotalL1Fee   []*big.Int
+	L1FeeParams  []*types.L1FeeParams
+}
+
+// feeHistory processes the requested range of blocks and returns the first
+// block of the actually processed range together with the per-block results.
+// If withL1 is set, the L1 data fee statistics are gathered as well.
+func (oracle *Oracle) feeHistory(ctx context.Context, blocks int, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles, l1FeePercentiles []float64, withL1 bool) (uint64, []processedFees, error) {
 	if blocks < 1 {
-		return common.Big0, nil, nil, nil, nil // returning with no data and no error means there are no retrievable blocks
+		return 0, nil, nil // returning with no data and no error means there are no retrievable blocks
 	}
 	maxFeeHistory := oracle.maxHeaderHistory
-	if len(rewardPercentiles) != 0 {
+	if len(rewardPercentiles) != 0 || withL1 {
 		maxFeeHistory = oracle.maxBlockHistory
 	}
 	if blocks > maxFeeHistory {
@@ -219,10 +343,10 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 	}
 	for i, p := range rewardPercentiles {
 		if p < 0 || p > 100 {
-			return common.Big0, nil, nil, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+			return 0, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
 		}
 		if i > 0 && p < rewardPercentiles[i-1] {
-			return common.Big0, nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
+			return 0, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
 		}
 	}
 	var (
@@ -232,7 +356,7 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 	)
 	pendingBlock, pendingReceipts, lastBlock, blocks, err := oracle.resolveBlockRange(ctx, unresolvedLastBlock, blocks)
 	if err != nil || blocks == 0 {
-		return common.Big0, nil, nil, nil, err
+		return 0, nil, err
 	}
 	oldestBlock := lastBlock + 1 - uint64(blocks)
 
@@ -240,10 +364,16 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 		next    = oldestBlock
 		results = make(chan *blockFees, blocks)
 	)
-	percentileKey := make([]byte, 8*len(rewardPercentiles))
-	for i, p := range rewardPercentiles {
+	percentileKey := make([]byte, 8*(len(rewardPercentiles)+len(l1FeePercentiles))+1)
+	for i, p := range append(append([]float64{}, rewardPercentiles...), l1FeePercentiles...) {
 		binary.LittleEndian.PutUint64(percentileKey[i*8:(i+1)*8], math.Float64bits(p))
 	}
+	// Separate the reward and L1 fee percentiles in the cache key, as well as
+	// results with and without L1 data fee statistics.
+	percentileKey[len(percentileKey)-1] = byte(len(rewardPercentiles))
+	if withL1 {
+		percentileKey = append(percentileKey, 1)
+	}
 	for i := 0; i < maxBlockFetchers && i < blocks; i++ {
 		go func() {
 			for {
@@ -258,6 +388,9 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 					fees.block, fees.receipts = pendingBlock, pendingReceipts
 					fees.header = fees.block.Header()
 					oracle.processBlock(fees, rewardPercentiles)
+					if withL1 {
+						oracle.processL1Fees(ctx, fees, l1FeePercentiles)
+					}
 					results <- fees
 				} else {
 					cacheKey := struct {
@@ -269,7 +402,7 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 						fees.results = p.(processedFees)
 						results <- fees
 					} else {
-						if len(rewardPercentiles) != 0 {
+						if len(rewardPercentiles) != 0 || withL1 {
 							fees.block, fees.err = oracle.backend.BlockByNumber(ctx, rpc.BlockNumber(blockNumber))
 							if fees.block != nil && fees.err == nil {
 								fees.receipts, fees.err = oracle.backend.GetReceipts(ctx, fees.block.Hash())
@@ -280,6 +413,9 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 						}
 						if fees.header != nil && fees.err == nil {
 							oracle.processBlock(fees, rewardPercentiles)
+							if withL1 {
+								oracle.processL1Fees(ctx, fees, l1FeePercentiles)
+							}
 							if fees.err == nil {
 								oracle.historyCache.Add(cacheKey, fees.results)
 							}
@@ -292,19 +428,17 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 		}()
 	}
 	var (
-		reward       = make([][]*big.Int, blocks)
-		baseFee      = make([]*big.Int, blocks+1)
-		gasUsedRatio = make([]float64, blocks)
+		processed    = make([]processedFees, blocks)
 		firstMissing = blocks
 	)
 	for ; blocks > 0; blocks-- {
 		fees := <-results
 		if fees.err != nil {
-			return common.Big0, nil, nil, nil, fees.err
+			return 0, nil, fees.err
 		}
 		i := int(fees.blockNumber - oldestBlock)
 		if fees.results.baseFee != nil {
-			reward[i], baseFee[i], baseFee[i+1], gasUsedRatio[i] = fees.results.reward, fees.results.baseFee, fees.results.nextBaseFee, fees.results.gasUsedRatio
+			processed[i] = fees.results
 		} else {
 			// getting no block and no error means we are requesting into the future (might happen because of a reorg)
 			if i < firstMissing {
@@ -312,14 +446,5 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 			}
 		}
 	}
-	if firstMissing == 0 {
-		return common.Big0, nil, nil, nil, nil
-	}
-	if len(rewardPercentiles) != 0 {
-		reward = reward[:firstMissing]
-	} else {
-		reward = nil
-	}
-	baseFee, gasUsedRatio = baseFee[:firstMissing+1], gasUsedRatio[:firstMissing]
-	return new(big.Int).SetUint64(oldestBlock), reward, baseFee, gasUsedRatio, nil
+	return oldestBlock, processed[:firstMissing], nil
 }
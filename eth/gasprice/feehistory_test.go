@@ -87,3 +87,33 @@ func TestFeeHistory(t *testing.T) {
 		}
 	}
 }
+
+func TestL1FeeHistory(t *testing.T) {
+	backend := newTestBackend(t, big.NewInt(16), false, 0)
+	oracle := NewOracle(backend, Config{MaxHeaderHistory: 1000, MaxBlockHistory: 1000})
+
+	history, err := oracle.L1FeeHistory(context.Background(), 10, 30, []float64{0, 10}, []float64{50, 100})
+	if err != nil {
+		t.Fatalf("failed to get L1 fee history: %v", err)
+	}
+	if first := history.OldestBlock.Uint64(); first != 21 {
+		t.Fatalf("first block mismatch, want %d, got %d", 21, first)
+	}
+	if len(history.Reward) != 10 || len(history.BaseFee) != 11 || len(history.GasUsedRatio) != 10 {
+		t.Fatalf("fee history length mismatch: reward %d, baseFee %d, gasUsedRatio %d", len(history.Reward), len(history.BaseFee), len(history.GasUsedRatio))
+	}
+	if len(history.L1FeeReward) != 10 || len(history.TotalL1Fee) != 10 || len(history.L1FeeParams) != 10 {
+		t.Fatalf("L1 fee history length mismatch: reward %d, total %d, params %d", len(history.L1FeeReward), len(history.TotalL1Fee), len(history.L1FeeParams))
+	}
+	for i, reward := range history.L1FeeReward {
+		if len(reward) != 2 || history.TotalL1Fee[i] == nil {
+			t.Fatalf("block %d: missing L1 fee statistics", i)
+		}
+		if reward[0].Cmp(reward[1]) > 0 || reward[1].Cmp(history.TotalL1Fee[i]) > 0 {
+			t.Fatalf("block %d: inconsistent L1 fee statistics: percentiles %v, total %v", i, reward, history.TotalL1Fee[i])
+		}
+	}
+	if _, err := oracle.L1FeeHistory(context.Background(), 10, 30, nil, []float64{20, 10}); !errors.Is(err, errInvalidPercentile) {
+		t.Fatalf("error mismatch, want %v, got %v", errInvalidPercentile, err)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/core"
 	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/event"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/params"
@@ -63,6 +64,7 @@ type OracleBackend interface {
 	ChainConfig() *params.ChainConfig
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
 	StateAt(root common.Hash) (*state.StateDB, error)
+	ChainDb() ethdb.Database
 	Stats() (pending int, queued int)
 	StatsWithMinBaseFee(minBaseFee *big.Int) (pending int, queued int)
 }
@@ -83,6 +85,7 @@ type Oracle struct {
 	congestedThreshold                int      // Number of pending transactions to consider the network congested and suggest a minimum tip cap.
 	defaultBasePrice                  *big.Int // Base price to set when CongestedThreshold is reached before Curie (EIP 1559).
 	historyCache                      *lru.Cache
+	l1FeeParamsCache                  *lru.Cache // L1 fee parameters after each block, keyed by block hash
 }
 
 // NewOracle returns a new gasprice oracle which can recommend suitable
@@ -135,6 +138,7 @@ func NewOracle(backend OracleBackend, params Config) *Oracle {
 	}
 
 	cache, _ := lru.New(2048)
+	l1FeeParamsCache, _ := lru.New(2048)
 	headEvent := make(chan core.ChainHeadEvent, 1)
 	backend.SubscribeChainHeadEvent(headEvent)
 	go func() {
@@ -159,6 +163,7 @@ func NewOracle(backend OracleBackend, params Config) *Oracle {
 		congestedThreshold: congestedThreshold,
 		defaultBasePrice:   defaultBasePrice,
 		historyCache:       cache,
+		l1FeeParamsCache:   l1FeeParamsCache,
 	}
 }
 
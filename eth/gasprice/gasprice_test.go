@@ -30,6 +30,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/core/vm"
 	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/event"
 	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/rpc"
@@ -39,6 +40,7 @@ const testHead = 32
 
 type testBackend struct {
 	chain          *core.BlockChain
+	db             ethdb.Database
 	pending        bool // pending block available
 	pendingTxCount int
 }
@@ -165,7 +167,7 @@ func newTestBackend(t *testing.T, londonBlock *big.Int, pending bool, pendingTxC
 		t.Fatalf("Failed to create local chain, %v", err)
 	}
 	chain.InsertChain(blocks)
-	return &testBackend{chain: chain, pending: pending, pendingTxCount: pendingTxCount}
+	return &testBackend{chain: chain, db: diskdb, pending: pending, pendingTxCount: pendingTxCount}
 }
 
 func (b *testBackend) CurrentHeader() *types.Header {
@@ -177,7 +179,11 @@ func (b *testBackend) GetBlockByNumber(number uint64) *types.Block {
 }
 
 func (b *testBackend) StateAt(root common.Hash) (*state.StateDB, error) {
-	return nil, nil
+	return b.chain.StateAt(root)
+}
+
+func (b *testBackend) ChainDb() ethdb.Database {
+	return b.db
 }
 
 func TestSuggestTipCap(t *testing.T) {
@@ -0,0 +1,154 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rollup/fees"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+var errNoL1FeeSamples = errors.New("no L1 fee parameters available in the requested range")
+
+// L1FeeForecast is a forecast of the L1 data fee of a transaction, derived from
+// the history of the L1GasPriceOracle parameters over recent blocks.
+type L1FeeForecast struct {
+	OldestBlock   *big.Int   // First block of the sampled range
+	Samples       int        // Number of blocks whose parameters were available
+	Current       *big.Int   // L1 data fee under the parameters of the latest block
+	Forecast      []*big.Int // Requested percentiles of the L1 data fee over the range
+	L1BaseFee     []*big.Int // Requested percentiles of the L1 base fee over the range
+	L1BlobBaseFee []*big.Int // Requested percentiles of the L1 blob base fee over the range
+}
+
+// L1FeeForecast samples the L1GasPriceOracle parameters after each of the
+// latest blocks and returns the requested percentiles of the L1 data fee a
+// signed transaction of the given encoded size would have paid under them.
+// The fee of the next block is computed with the rules of the next block, so
+// forecasts stay valid across the Curie fork. Blocks whose state is not
+// available are skipped.
+func (oracle *Oracle) L1FeeForecast(ctx context.Context, size uint64, blocks int, percentiles []float64) (*L1FeeForecast, error) {
+	if blocks < 1 {
+		blocks = oracle.checkBlocks
+	}
+	if blocks > oracle.maxHeaderHistory {
+		log.Warn("Sanitizing L1 fee forecast length", "requested", blocks, "truncated", oracle.maxHeaderHistory)
+		blocks = oracle.maxHeaderHistory
+	}
+	for i, p := range percentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+		}
+		if i > 0 && p < percentiles[i-1] {
+			return nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, percentiles[i-1], i, p)
+		}
+	}
+	head, err := oracle.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(blocks) > head.Number.Uint64()+1 {
+		blocks = int(head.Number.Uint64() + 1)
+	}
+	var (
+		next     = new(big.Int).Add(head.Number, common.Big1)
+		oldest   = head.Number.Uint64() + 1 - uint64(blocks)
+		samples  []*types.L1FeeParams
+		l1Fees   []*big.Int
+		forecast = &L1FeeForecast{OldestBlock: new(big.Int).SetUint64(oldest)}
+	)
+	for number := head.Number.Uint64(); number+1 > oldest; number-- {
+		params, err := oracle.l1FeeParamsAt(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		if params == nil {
+			continue
+		}
+		fee := fees.L1DataFeeForSize(oracle.backend.ChainConfig(), next, params, size)
+		if forecast.Current == nil {
+			forecast.Current = fee
+		}
+		samples = append(samples, params)
+		l1Fees = append(l1Fees, fee)
+	}
+	if len(samples) == 0 {
+		return nil, errNoL1FeeSamples
+	}
+	forecast.Samples = len(samples)
+
+	l1BaseFees := make([]*big.Int, len(samples))
+	l1BlobBaseFees := make([]*big.Int, len(samples))
+	for i, params := range samples {
+		l1BaseFees[i], l1BlobBaseFees[i] = params.L1BaseFee, params.L1BlobBaseFee
+	}
+	sort.Slice(l1Fees, func(i, j int) bool { return l1Fees[i].Cmp(l1Fees[j]) < 0 })
+	sort.Slice(l1BaseFees, func(i, j int) bool { return l1BaseFees[i].Cmp(l1BaseFees[j]) < 0 })
+	sort.Slice(l1BlobBaseFees, func(i, j int) bool { return l1BlobBaseFees[i].Cmp(l1BlobBaseFees[j]) < 0 })
+
+	forecast.Forecast = make([]*big.Int, len(percentiles))
+	forecast.L1BaseFee = make([]*big.Int, len(percentiles))
+	forecast.L1BlobBaseFee = make([]*big.Int, len(percentiles))
+	for i, p := range percentiles {
+		index := int(math.Ceil(float64(len(samples))*p/100)) - 1
+		if index < 0 {
+			index = 0
+		}
+		forecast.Forecast[i] = new(big.Int).Set(l1Fees[index])
+		forecast.L1BaseFee[i] = new(big.Int).Set(l1BaseFees[index])
+		forecast.L1BlobBaseFee[i] = new(big.Int).Set(l1BlobBaseFees[index])
+	}
+	return forecast, nil
+}
+
+// l1FeeParamsAt returns the L1GasPriceOracle parameters after the block with
+// the given number. They are read from the record stored when the block was
+// imported, falling back to the state of the block if the record is missing.
+// Nil is returned if neither is available.
+func (oracle *Oracle) l1FeeParamsAt(ctx context.Context, number uint64) (*types.L1FeeParams, error) {
+	header, err := oracle.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, nil
+	}
+	if params, ok := oracle.l1FeeParamsCache.Get(header.Hash()); ok {
+		return params.(*types.L1FeeParams), nil
+	}
+	params := rawdb.ReadL1FeeParams(oracle.backend.ChainDb(), header.Hash())
+	if params == nil {
+		state, err := oracle.backend.StateAt(header.Root)
+		if err != nil || state == nil {
+			log.Debug("State not available for L1 fee forecast", "number", number, "hash", header.Hash(), "err", err)
+			return nil, nil
+		}
+		params = fees.ReadL1FeeParams(state)
+	}
+	oracle.l1FeeParamsCache.Add(header.Hash(), params)
+	return params, nil
+}
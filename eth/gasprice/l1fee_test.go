@@ -0,0 +1,80 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/rollup/fees"
+)
+
+func TestL1FeeForecast(t *testing.T) {
+	backend := newTestBackend(t, big.NewInt(16), false, 0)
+	oracle := NewOracle(backend, Config{Blocks: 20, MaxHeaderHistory: 1000, MaxBlockHistory: 1000})
+
+	forecast, err := oracle.L1FeeForecast(context.Background(), 1000, 10, []float64{50, 100})
+	if err != nil {
+		t.Fatalf("failed to forecast L1 fee: %v", err)
+	}
+	if forecast.Samples != 10 || forecast.OldestBlock.Uint64() != testHead-9 {
+		t.Fatalf("sample range mismatch: samples %d, oldest %d", forecast.Samples, forecast.OldestBlock)
+	}
+	head := backend.chain.CurrentBlock()
+	state, _ := backend.StateAt(head.Root())
+	want := fees.L1DataFeeForSize(backend.ChainConfig(), new(big.Int).Add(head.Number(), common.Big1), fees.ReadL1FeeParams(state), 1000)
+	if forecast.Current.Cmp(want) != 0 || want.Sign() == 0 {
+		t.Fatalf("current L1 fee mismatch: want %v, got %v", want, forecast.Current)
+	}
+	if len(forecast.Forecast) != 2 || forecast.Forecast[0].Cmp(forecast.Forecast[1]) > 0 {
+		t.Fatalf("invalid forecast percentiles: %v", forecast.Forecast)
+	}
+	if len(forecast.L1BaseFee) != 2 || len(forecast.L1BlobBaseFee) != 2 {
+		t.Fatalf("invalid parameter percentiles: %v, %v", forecast.L1BaseFee, forecast.L1BlobBaseFee)
+	}
+	if _, err := oracle.L1FeeForecast(context.Background(), 1000, 10, []float64{20, 10}); !errors.Is(err, errInvalidPercentile) {
+		t.Fatalf("error mismatch, want %v, got %v", errInvalidPercentile, err)
+	}
+}
+
+func TestL1FeeForecastStoredParams(t *testing.T) {
+	backend := newTestBackend(t, big.NewInt(16), false, 0)
+	oracle := NewOracle(backend, Config{Blocks: 20, MaxHeaderHistory: 1000, MaxBlockHistory: 1000})
+
+	// The parameters stored at import take precedence over the state
+	head := backend.chain.CurrentBlock()
+	state, _ := backend.StateAt(head.Root())
+	params := fees.ReadL1FeeParams(state)
+	params.L1BaseFee = new(big.Int).Mul(params.L1BaseFee, big.NewInt(10))
+	rawdb.WriteL1FeeParams(backend.db, head.Hash(), params)
+
+	forecast, err := oracle.L1FeeForecast(context.Background(), 1000, 1, []float64{50})
+	if err != nil {
+		t.Fatalf("failed to forecast L1 fee: %v", err)
+	}
+	want := fees.L1DataFeeForSize(backend.ChainConfig(), new(big.Int).Add(head.Number(), common.Big1), params, 1000)
+	if forecast.Current.Cmp(want) != 0 {
+		t.Fatalf("current L1 fee mismatch: want %v, got %v", want, forecast.Current)
+	}
+	if forecast.L1BaseFee[0].Cmp(params.L1BaseFee) != 0 {
+		t.Fatalf("L1 base fee mismatch: want %v, got %v", params.L1BaseFee, forecast.L1BaseFee[0])
+	}
+}
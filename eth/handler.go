@@ -45,6 +45,14 @@ const (
 	// txChanSize is the size of channel listening to NewTxsEvent.
 	// The number is referenced from the size of tx pool.
 	txChanSize = 4096
+
+	// txRebroadcastInterval is the time between two rounds of rebroadcasting
+	// the stale local transactions.
+	txRebroadcastInterval = 5 * time.Minute
+
+	// txRebroadcastAge is the time after which a local transaction still
+	// pending is rebroadcast to peers.
+	txRebroadcastAge = 10 * time.Minute
 )
 
 var (
@@ -72,6 +80,14 @@ type txPool interface {
 	// SubscribeNewTxsEvent should return an event subscription of
 	// NewTxsEvent and send events to the given channel.
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
+
+	// IsPrivate returns whether the transaction with the given hash was
+	// submitted privately and must not be propagated to peers.
+	IsPrivate(hash common.Hash) bool
+
+	// Stale returns the local executable transactions first seen longer than
+	// the given age ago, which are periodically rebroadcast.
+	Stale(age time.Duration) types.Transactions
 }
 
 // handlerConfig is the collection of initialization parameters to create a full
@@ -414,6 +430,10 @@ func (h *handler) Start(maxPeers int) {
 	h.txsSub = h.txpool.SubscribeNewTxsEvent(h.txsCh)
 	go h.txBroadcastLoop()
 
+	// rebroadcast stale local transactions
+	h.wg.Add(1)
+	go h.txRebroadcastLoop()
+
 	// broadcast mined blocks
 	h.wg.Add(1)
 	h.minedBlockSub = h.eventMux.Subscribe(core.NewMinedBlockEvent{})
@@ -497,6 +517,11 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		if tx.IsL1MessageTx() {
 			continue
 		}
+		// Private and conditional transactions are kept from peers, the latter
+		// since peers would ignore their conditions
+		if h.txpool.IsPrivate(tx.Hash()) || tx.Conditional() != nil {
+			continue
+		}
 		peers := onlyShadowForkPeers(h.shadowForkPeerIDs, h.peers.peersWithoutTransaction(tx.Hash()))
 		// Send the tx unconditionally to a subset of our peers
 		numDirect := int(math.Sqrt(float64(len(peers))))
@@ -548,6 +573,44 @@ func (h *handler) txBroadcastLoop() {
 	}
 }
 
+// RebroadcastTransactions sends the transactions directly to all peers, even to
+// the ones they were already propagated to, since these may have dropped them.
+func (h *handler) RebroadcastTransactions(txs types.Transactions) {
+	var hashes []common.Hash
+	for _, tx := range txs {
+		if tx.IsL1MessageTx() || h.txpool.IsPrivate(tx.Hash()) || tx.Conditional() != nil {
+			continue
+		}
+		hashes = append(hashes, tx.Hash())
+	}
+	if len(hashes) == 0 {
+		return
+	}
+	peers := onlyShadowForkPeers(h.shadowForkPeerIDs, h.peers.all())
+	for _, peer := range peers {
+		peer.AsyncSendTransactions(hashes)
+	}
+	log.Debug("Transactions rebroadcast", "count", len(hashes), "peers", len(peers))
+}
+
+// txRebroadcastLoop periodically rebroadcasts the local transactions pending
+// for long, in case peers dropped them.
+func (h *handler) txRebroadcastLoop() {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(txRebroadcastInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.RebroadcastTransactions(h.txpool.Stale(txRebroadcastAge))
+		case <-h.quitSync:
+			return
+		}
+	}
+}
+
 // onlyShadowForkPeers filters out peers that are not part of the shadow fork
 func onlyShadowForkPeers[peerT interface {
 	ID() string
@@ -38,7 +38,25 @@ type ethHandler handler
 
 func (h *ethHandler) Chain() *core.BlockChain     { return h.chain }
 func (h *ethHandler) StateBloom() *trie.SyncBloom { return h.stateBloom }
-func (h *ethHandler) TxPool() eth.TxPool          { return h.txpool }
+func (h *ethHandler) TxPool() eth.TxPool          { return publicTxPool{h.txpool} }
+
+// publicTxPool is the view of the transaction pool served to peers, hiding the
+// private and conditional transactions.
+type publicTxPool struct {
+	txPool
+}
+
+// Get retrieves the transaction from the local txpool with the given hash,
+// unless it is private or conditional.
+func (p publicTxPool) Get(hash common.Hash) *types.Transaction {
+	if p.IsPrivate(hash) {
+		return nil
+	}
+	if tx := p.txPool.Get(hash); tx != nil && tx.Conditional() == nil {
+		return tx
+	}
+	return nil
+}
 
 // RunPeer is invoked when a peer joins on the `eth` protocol.
 func (h *ethHandler) RunPeer(peer *eth.Peer, hand eth.Handler) error {
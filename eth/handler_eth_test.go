@@ -446,6 +446,79 @@ func testTransactionPropagation(t *testing.T, protocol uint) {
 	}
 }
 
+// Tests that stale transactions are rebroadcast to all the peers, regardless of
+// whether they were already propagated to them.
+func TestTransactionRebroadcast66(t *testing.T) { testTransactionRebroadcast(t, eth.ETH66) }
+
+func testTransactionRebroadcast(t *testing.T, protocol uint) {
+	t.Parallel()
+
+	source := newTestHandler()
+	defer source.close()
+
+	sinks := make([]*testHandler, 2)
+	for i := 0; i < len(sinks); i++ {
+		sinks[i] = newTestHandler()
+		defer sinks[i].close()
+
+		sinks[i].handler.acceptTxs = 1 // mark synced to accept transactions
+	}
+	for i, sink := range sinks {
+		sink := sink // Closure for gorotuine below
+
+		sourcePipe, sinkPipe := p2p.MsgPipe()
+		defer sourcePipe.Close()
+		defer sinkPipe.Close()
+
+		sourcePeer := eth.NewPeer(protocol, p2p.NewPeerPipe(enode.ID{byte(i)}, "", nil, sourcePipe), sourcePipe, source.txpool)
+		sinkPeer := eth.NewPeer(protocol, p2p.NewPeerPipe(enode.ID{0}, "", nil, sinkPipe), sinkPipe, sink.txpool)
+		defer sourcePeer.Close()
+		defer sinkPeer.Close()
+
+		go source.handler.runEthPeer(sourcePeer, func(peer *eth.Peer) error {
+			return eth.Handle((*ethHandler)(source.handler), peer)
+		})
+		go sink.handler.runEthPeer(sinkPeer, func(peer *eth.Peer) error {
+			return eth.Handle((*ethHandler)(sink.handler), peer)
+		})
+	}
+	for start := time.Now(); source.handler.peers.len() < len(sinks); {
+		if time.Since(start) > time.Second {
+			t.Fatalf("peers not connected: have %d, want %d", source.handler.peers.len(), len(sinks))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	txChs := make([]chan core.NewTxsEvent, len(sinks))
+	for i := 0; i < len(sinks); i++ {
+		txChs[i] = make(chan core.NewTxsEvent, 16)
+
+		sub := sinks[i].txpool.SubscribeNewTxsEvent(txChs[i])
+		defer sub.Unsubscribe()
+	}
+	// Place the transactions in the source pool without announcing them, as if
+	// they were dropped by the peers, and rebroadcast them
+	txs := make([]*types.Transaction, 16)
+	for nonce := range txs {
+		tx := types.NewTransaction(uint64(nonce), common.Address{}, big.NewInt(0), 100000, big.NewInt(0), nil)
+		tx, _ = types.SignTx(tx, types.HomesteadSigner{}, testKey)
+
+		txs[nonce] = tx
+		source.txpool.pool[tx.Hash()] = tx
+	}
+	source.handler.RebroadcastTransactions(source.txpool.Stale(txRebroadcastAge))
+
+	for i := range sinks {
+		for arrived := 0; arrived < len(txs); {
+			select {
+			case event := <-txChs[i]:
+				arrived += len(event.Txs)
+			case <-time.NewTimer(time.Second).C:
+				t.Fatalf("sink %d: transaction rebroadcast timed out: have %d, want %d", i, arrived, len(txs))
+			}
+		}
+	}
+}
+
 // Tests that post eth protocol handshake, clients perform a mutual checkpoint
 // challenge to validate each other's chains. Hash mismatches, or missing ones
 // during a fast sync should lead to the peer getting dropped.
@@ -21,6 +21,7 @@ import (
 	"sort"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -80,6 +81,12 @@ func (p *testTxPool) Get(hash common.Hash) *types.Transaction {
 	return p.pool[hash]
 }
 
+// IsPrivate returns whether the transaction is private, which is never the
+// case in the test pool.
+func (p *testTxPool) IsPrivate(hash common.Hash) bool {
+	return false
+}
+
 // AddRemotes appends a batch of transactions to the pool, and notifies any
 // listeners if the addition channel is non nil
 func (p *testTxPool) AddRemotes(txs []*types.Transaction) []error {
@@ -109,6 +116,18 @@ func (p *testTxPool) Pending(enforceTips bool) map[common.Address]types.Transact
 	return batches
 }
 
+// Stale returns all the transactions known to the pool, regardless of age.
+func (p *testTxPool) Stale(age time.Duration) types.Transactions {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	txs := make(types.Transactions, 0, len(p.pool))
+	for _, tx := range p.pool {
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
 // SubscribeNewTxsEvent should return an event subscription of NewTxsEvent and
 // send events to the given channel.
 func (p *testTxPool) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
@@ -3,6 +3,7 @@ package tracers
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/scroll-tech/go-ethereum/consensus"
 	"github.com/scroll-tech/go-ethereum/core"
@@ -17,13 +18,19 @@ import (
 
 var errNoScrollTracerWrapper = errors.New("no ScrollTracerWrapper")
 
+// maxBatchWitnessBlocks is the maximum number of blocks a single batch witness
+// may cover.
+const maxBatchWitnessBlocks = 1000
+
 type TraceBlock interface {
 	GetBlockTraceByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, config *TraceConfig) (trace *types.BlockTrace, err error)
 	GetTxBlockTraceOnTopOfBlock(ctx context.Context, tx *types.Transaction, blockNrOrHash rpc.BlockNumberOrHash, config *TraceConfig) (*types.BlockTrace, error)
+	GetBatchWitness(ctx context.Context, fromBlock, toBlock rpc.BlockNumber, config *TraceConfig) (*types.BatchWitness, error)
 }
 
 type scrollTracerWrapper interface {
 	CreateTraceEnvAndGetBlockTrace(*params.ChainConfig, core.ChainContext, consensus.Engine, ethdb.Database, *state.StateDB, *types.Block, *types.Block, bool) (*types.BlockTrace, error)
+	CreateTraceEnvAndGetBatchWitness(*params.ChainConfig, core.ChainContext, consensus.Engine, ethdb.Database, *state.StateDB, *types.Block, []*types.Block) (*types.BatchWitness, error)
 }
 
 // GetBlockTraceByNumberOrHash replays the block and returns the structured BlockTrace by hash or number.
@@ -79,6 +86,57 @@ func (api *API) GetTxBlockTraceOnTopOfBlock(ctx context.Context, tx *types.Trans
 	return api.createTraceEnvAndGetBlockTrace(ctx, config, block)
 }
 
+// GetBatchWitness replays the blocks in the inclusive range [fromBlock, toBlock] and
+// returns a single witness covering all of them, with account and storage proofs
+// against the state before fromBlock, deduplicated bytecodes and deletion proofs,
+// and the per-block execution results.
+func (api *API) GetBatchWitness(ctx context.Context, fromBlock, toBlock rpc.BlockNumber, config *TraceConfig) (*types.BatchWitness, error) {
+	if api.scrollTracerWrapper == nil {
+		return nil, errNoScrollTracerWrapper
+	}
+	from, err := api.blockByNumber(ctx, fromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.blockByNumber(ctx, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	if from.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	if from.NumberU64() > to.NumberU64() {
+		return nil, fmt.Errorf("invalid block range: from %d > to %d", from.NumberU64(), to.NumberU64())
+	}
+	if count := to.NumberU64() - from.NumberU64() + 1; count > maxBatchWitnessBlocks {
+		return nil, fmt.Errorf("block range too large: %d > %d", count, maxBatchWitnessBlocks)
+	}
+	blocks := []*types.Block{from}
+	for number := from.NumberU64() + 1; number <= to.NumberU64(); number++ {
+		block, err := api.blockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+
+	parent, err := api.blockByNumberAndHash(ctx, rpc.BlockNumber(from.NumberU64()-1), from.ParentHash())
+	if err != nil {
+		return nil, err
+	}
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	statedb, err := api.backend.StateAtBlock(ctx, parent, reexec, nil, true, true)
+	if err != nil {
+		return nil, err
+	}
+
+	chaindb := api.backend.ChainDb()
+	return api.scrollTracerWrapper.CreateTraceEnvAndGetBatchWitness(api.backend.ChainConfig(), api.chainContext(ctx), api.backend.Engine(), chaindb, statedb, parent, blocks)
+}
+
 // Make trace environment for current block, and then get the trace for the block.
 func (api *API) createTraceEnvAndGetBlockTrace(ctx context.Context, config *TraceConfig, block *types.Block) (*types.BlockTrace, error) {
 	if config == nil {
@@ -0,0 +1,123 @@
+package tracers_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/consensus"
+	"github.com/scroll-tech/go-ethereum/consensus/clique"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/stateless"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/core/vm"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/eth/tracers"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/tracing"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// batchTestBackend serves the chain accesses of GetBatchWitness. It lives in an
+// external test package as rollup/tracing imports the tracers package.
+type batchTestBackend struct {
+	tracers.Backend
+	chain   *core.BlockChain
+	chaindb ethdb.Database
+}
+
+func (b *batchTestBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return b.chain.GetHeaderByHash(hash), nil
+}
+
+func (b *batchTestBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	return b.chain.GetHeaderByNumber(uint64(number)), nil
+}
+
+func (b *batchTestBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return b.chain.GetBlockByHash(hash), nil
+}
+
+func (b *batchTestBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	return b.chain.GetBlockByNumber(uint64(number)), nil
+}
+
+func (b *batchTestBackend) ChainConfig() *params.ChainConfig { return b.chain.Config() }
+func (b *batchTestBackend) Engine() consensus.Engine         { return b.chain.Engine() }
+func (b *batchTestBackend) ChainDb() ethdb.Database          { return b.chaindb }
+
+func (b *batchTestBackend) StateAtBlock(ctx context.Context, block *types.Block, reexec uint64, base *state.StateDB, checkLive, preferDisk bool) (*state.StateDB, error) {
+	return b.chain.StateAt(block.Root())
+}
+
+func TestGetBatchWitness(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		config = *params.TestChainConfig
+	)
+	config.Ethash = nil
+	config.Clique = &params.CliqueConfig{Period: 1, Epoch: 30000}
+	config.Scroll.UseZktrie = true
+	config.CurieBlock = big.NewInt(2)
+
+	db := rawdb.NewMemoryDatabase()
+	engine := clique.New(config.Clique, db)
+	gspec := &core.Genesis{
+		Config:    &config,
+		ExtraData: make([]byte, 32+common.AddressLength+crypto.SignatureLength),
+		Alloc:     core.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+	}
+	copy(gspec.ExtraData[32:], addr[:])
+	genesis := gspec.MustCommit(db)
+	chain, err := core.NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	signer := types.LatestSigner(&config)
+	for parent := genesis; parent.NumberU64() < 3; parent = chain.CurrentBlock() {
+		recipient := common.Address{byte(parent.NumberU64() + 1)}
+		blocks, _ := core.GenerateChain(&config, parent, engine, db, 1, func(i int, b *core.BlockGen) {
+			b.SetDifficulty(big.NewInt(2))
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(addr), recipient, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, key)
+			b.AddTx(tx)
+		})
+		header := blocks[0].Header()
+		header.Extra = make([]byte, 32+crypto.SignatureLength)
+		sig, _ := crypto.Sign(clique.SealHash(header).Bytes(), key)
+		copy(header.Extra[32:], sig)
+		if _, err := chain.InsertChain(types.Blocks{blocks[0].WithSeal(header)}); err != nil {
+			t.Fatalf("failed to insert block %d: %v", blocks[0].NumberU64(), err)
+		}
+	}
+
+	api := tracers.NewAPI(&batchTestBackend{chain: chain, chaindb: db}, tracing.NewTracerWrapper())
+	witness, err := api.GetBatchWitness(context.Background(), 1, 3, nil)
+	if err != nil {
+		t.Fatalf("failed to get batch witness: %v", err)
+	}
+	if len(witness.Blocks) != 3 {
+		t.Fatalf("block count mismatch: have %d, want 3", len(witness.Blocks))
+	}
+	if witness.StorageTrace.RootBefore != genesis.Root() {
+		t.Fatalf("root before mismatch: have %x, want %x", witness.StorageTrace.RootBefore, genesis.Root())
+	}
+	if root := chain.CurrentBlock().Root(); witness.StorageTrace.RootAfter != root {
+		t.Fatalf("root after mismatch: have %x, want %x", witness.StorageTrace.RootAfter, root)
+	}
+	if _, err := stateless.VerifyBatch(&config, witness); err != nil {
+		t.Fatalf("failed to verify batch witness: %v", err)
+	}
+
+	for i, tc := range []struct{ from, to rpc.BlockNumber }{{0, 1}, {3, 2}, {2, 4}} {
+		if _, err := api.GetBatchWitness(context.Background(), tc.from, tc.to, nil); err == nil {
+			t.Errorf("test %d: range [%d, %d] did not fail", i, tc.from, tc.to)
+		}
+	}
+}
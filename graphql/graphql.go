@@ -28,6 +28,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/common/math"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/eth/filters"
@@ -168,6 +169,36 @@ func (at *AccessTuple) StorageKeys(ctx context.Context) []common.Hash {
 	return at.storageKeys
 }
 
+// L1FeeParams represents the L1 gas price oracle parameters the L1 data fee is
+// derived from.
+type L1FeeParams struct {
+	params *types.L1FeeParams
+}
+
+func (p *L1FeeParams) L1BaseFee(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*p.params.L1BaseFee)
+}
+
+func (p *L1FeeParams) Overhead(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*p.params.Overhead)
+}
+
+func (p *L1FeeParams) Scalar(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*p.params.Scalar)
+}
+
+func (p *L1FeeParams) L1BlobBaseFee(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*p.params.L1BlobBaseFee)
+}
+
+func (p *L1FeeParams) CommitScalar(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*p.params.CommitScalar)
+}
+
+func (p *L1FeeParams) BlobScalar(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*p.params.BlobScalar)
+}
+
 // Transaction represents an Ethereum transaction.
 // backend and hash are mandatory; all others will be fetched when required.
 type Transaction struct {
@@ -385,6 +416,27 @@ func (t *Transaction) GasUsed(ctx context.Context) (*Long, error) {
 	return &ret, nil
 }
 
+func (t *Transaction) L1Fee(ctx context.Context) (*hexutil.Big, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	if receipt.L1Fee == nil {
+		return (*hexutil.Big)(new(big.Int)), nil
+	}
+	return (*hexutil.Big)(receipt.L1Fee), nil
+}
+
+func (t *Transaction) L1FeeParams(ctx context.Context) (*L1FeeParams, error) {
+	if _, err := t.resolve(ctx); err != nil {
+		return nil, err
+	}
+	if t.block == nil {
+		return nil, nil
+	}
+	return t.block.L1FeeParams(ctx)
+}
+
 func (t *Transaction) CumulativeGasUsed(ctx context.Context) (*Long, error) {
 	receipt, err := t.getReceipt(ctx)
 	if err != nil || receipt == nil {
@@ -595,6 +647,34 @@ func (b *Block) BaseFeePerGas(ctx context.Context) (*hexutil.Big, error) {
 	return (*hexutil.Big)(header.BaseFee), nil
 }
 
+func (b *Block) L1FeeParams(ctx context.Context) (*L1FeeParams, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil || header.Number.Sign() == 0 {
+		return nil, err
+	}
+	// The parameters in effect during the block are the ones after its parent,
+	// they are missing for blocks imported without state.
+	params := rawdb.ReadL1FeeParams(b.backend.ChainDb(), header.ParentHash)
+	if params == nil {
+		return nil, nil
+	}
+	return &L1FeeParams{params}, nil
+}
+
+func (b *Block) TotalL1Fee(ctx context.Context) (hexutil.Big, error) {
+	receipts, err := b.resolveReceipts(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	total := new(big.Int)
+	for _, receipt := range receipts {
+		if receipt.L1Fee != nil {
+			total.Add(total, receipt.L1Fee)
+		}
+	}
+	return hexutil.Big(*total), nil
+}
+
 func (b *Block) Parent(ctx context.Context) (*Block, error) {
 	// If the block header hasn't been fetched, and we'll need it, fetch it.
 	if b.numberOrHash == nil && b.header == nil {
@@ -138,6 +138,30 @@ const schema string = `
         #Envelope transaction support
         type: Int
         accessList: [AccessTuple!]
+        # L1Fee is the L1 data fee, in wei, paid by this transaction. If the
+        # transaction has not yet been mined, this field will be null.
+        l1Fee: BigInt
+        # L1FeeParams are the L1 gas price oracle parameters in effect at the
+        # start of the block this transaction was mined in. If the transaction
+        # has not yet been mined, this field will be null.
+        l1FeeParams: L1FeeParams
+    }
+
+    # L1FeeParams are the parameters of the L1 gas price oracle contract the L1
+    # data fee of a transaction is derived from.
+    type L1FeeParams {
+        # L1BaseFee is the L1 base fee, in wei, reported to the oracle.
+        l1BaseFee: BigInt!
+        # Overhead is the fixed L1 gas overhead before the Curie upgrade.
+        overhead: BigInt!
+        # Scalar is the L1 fee scalar before the Curie upgrade.
+        scalar: BigInt!
+        # L1BlobBaseFee is the L1 blob base fee, in wei, reported to the oracle.
+        l1BlobBaseFee: BigInt!
+        # CommitScalar is the calldata commit scalar after the Curie upgrade.
+        commitScalar: BigInt!
+        # BlobScalar is the blob data scalar after the Curie upgrade.
+        blobScalar: BigInt!
     }
 
     # BlockFilterCriteria encapsulates log filter criteria for a filter applied
@@ -231,6 +255,12 @@ const schema string = `
         # EstimateGas estimates the amount of gas that will be required for
         # successful execution of a transaction at the current block's state.
         estimateGas(data: CallData!): Long!
+        # L1FeeParams are the L1 gas price oracle parameters in effect at the
+        # start of this block. This field will be null for the genesis block.
+        l1FeeParams: L1FeeParams
+        # TotalL1Fee is the sum of the L1 data fees, in wei, paid by the
+        # transactions in this block.
+        totalL1Fee: BigInt!
     }
 
     # CallData represents the data associated with a local contract call.
@@ -38,6 +38,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/consensus/ethash"
 	"github.com/scroll-tech/go-ethereum/consensus/misc"
 	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/core/vm"
@@ -160,19 +161,18 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 	pending, queue := s.b.TxPoolContent()
 	curHeader := s.b.CurrentHeader()
 
-	// get latest L1 base fee
+	// get latest L1 fee parameters
 	state, err := s.b.StateAt(curHeader.Root)
 	if err != nil || state == nil {
 		log.Error("State not found", "number", curHeader.Number, "hash", curHeader.Hash().Hex(), "state", state, "err", err)
 		return nil
 	}
-	l1BaseFee := fees.GetL1BaseFee(state)
 
 	// Flatten the pending transactions
 	for account, txs := range pending {
 		dump := make(map[string]*RPCTransaction)
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, curHeader, s.b.ChainConfig(), l1BaseFee)
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPoolTransaction(tx, curHeader, s.b.ChainConfig(), state)
 		}
 		content["pending"][account.Hex()] = dump
 	}
@@ -180,7 +180,7 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 	for account, txs := range queue {
 		dump := make(map[string]*RPCTransaction)
 		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, curHeader, s.b.ChainConfig(), l1BaseFee)
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPoolTransaction(tx, curHeader, s.b.ChainConfig(), state)
 		}
 		content["queued"][account.Hex()] = dump
 	}
@@ -193,38 +193,110 @@ func (s *PublicTxPoolAPI) ContentFrom(addr common.Address) map[string]map[string
 	pending, queue := s.b.TxPoolContentFrom(addr)
 	curHeader := s.b.CurrentHeader()
 
-	// get latest L1 base fee
+	// get latest L1 fee parameters
 	state, err := s.b.StateAt(curHeader.Root)
 	if err != nil || state == nil {
 		log.Error("State not found", "number", curHeader.Number, "hash", curHeader.Hash().Hex(), "state", state, "err", err)
 		return nil
 	}
-	l1BaseFee := fees.GetL1BaseFee(state)
 
 	// Build the pending transactions
 	dump := make(map[string]*RPCTransaction, len(pending))
 	for _, tx := range pending {
-		dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, curHeader, s.b.ChainConfig(), l1BaseFee)
+		dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPoolTransaction(tx, curHeader, s.b.ChainConfig(), state)
 	}
 	content["pending"] = dump
 
 	// Build the queued transactions
 	dump = make(map[string]*RPCTransaction, len(queue))
 	for _, tx := range queue {
-		dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, curHeader, s.b.ChainConfig(), l1BaseFee)
+		dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPoolTransaction(tx, curHeader, s.b.ChainConfig(), state)
 	}
 	content["queued"] = dump
 
 	return content
 }
 
-// Status returns the number of pending and queued transaction in the pool.
-func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
-	pending, queue := s.b.Stats()
-	return map[string]hexutil.Uint{
-		"pending": hexutil.Uint(pending),
-		"queued":  hexutil.Uint(queue),
+// TxPoolTxStatus is the status of a single transaction in the pool. Status is
+// one of "pending", "queued", "included", "skipped" (by the sequencer, e.g. for
+// exceeding the circuit capacity) or "evicted" (dropped from the pool without
+// being included).
+type TxPoolTxStatus struct {
+	Status      string          `json:"status"`
+	Reason      string          `json:"reason,omitempty"`
+	FirstSeen   *hexutil.Uint64 `json:"firstSeen,omitempty"`
+	Evicted     *hexutil.Uint64 `json:"evicted,omitempty"`
+	BlockHash   *common.Hash    `json:"blockHash,omitempty"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber,omitempty"`
+}
+
+// TxPoolStatus is the result of txpool_status: the number of pending and queued
+// transactions in the pool, or the status of a single transaction.
+type TxPoolStatus struct {
+	Pending *hexutil.Uint `json:"pending,omitempty"`
+	Queued  *hexutil.Uint `json:"queued,omitempty"`
+	*TxPoolTxStatus
+}
+
+// Status returns the number of pending and queued transaction in the pool, or
+// the status of a single transaction if its hash is given. The status of an
+// unknown transaction is nil. Evictions are only remembered for a while.
+func (s *PublicTxPoolAPI) Status(ctx context.Context, hash *common.Hash) (*TxPoolStatus, error) {
+	if hash == nil {
+		pending, queue := s.b.Stats()
+		pendingCount, queuedCount := hexutil.Uint(pending), hexutil.Uint(queue)
+		return &TxPoolStatus{Pending: &pendingCount, Queued: &queuedCount}, nil
+	}
+	status, err := s.txStatus(ctx, *hash)
+	if status == nil || err != nil {
+		return nil, err
+	}
+	return &TxPoolStatus{TxPoolTxStatus: status}, nil
+}
+
+// txStatus looks a transaction up in the pool, the chain, the skipped
+// transactions and the recent pool evictions, in this order.
+func (s *PublicTxPoolAPI) txStatus(ctx context.Context, hash common.Hash) (*TxPoolTxStatus, error) {
+	if status := s.b.GetPoolTxStatus(hash); status != core.TxStatusUnknown {
+		// The transaction may have left the pool in between, look it up elsewhere then
+		if tx := s.b.GetPoolTransaction(hash); tx != nil {
+			name := "queued"
+			if status == core.TxStatusPending {
+				name = "pending"
+			}
+			return &TxPoolTxStatus{Status: name, FirstSeen: unixTime(tx.Time())}, nil
+		}
 	}
+	tx, blockHash, blockNumber, _, err := s.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx != nil {
+		return &TxPoolTxStatus{Status: "included", BlockHash: &blockHash, BlockNumber: (*hexutil.Uint64)(&blockNumber)}, nil
+	}
+	if stx := rawdb.ReadSkippedTransaction(s.b.ChainDb(), hash); stx != nil {
+		return &TxPoolTxStatus{
+			Status:      "skipped",
+			Reason:      stx.Reason,
+			BlockHash:   stx.BlockHash,
+			BlockNumber: (*hexutil.Uint64)(&stx.BlockNumber),
+		}, nil
+	}
+	if eviction, ok := s.b.GetPoolTxEviction(hash); ok {
+		return &TxPoolTxStatus{
+			Status:    "evicted",
+			Reason:    eviction.Reason,
+			FirstSeen: unixTime(eviction.Added),
+			Evicted:   unixTime(eviction.Evicted),
+		}, nil
+	}
+	return nil, nil
+}
+
+// unixTime converts a time to its RPC representation as Unix seconds.
+func unixTime(t time.Time) *hexutil.Uint64 {
+	u := hexutil.Uint64(t.Unix())
+	return &u
 }
 
 // Inspect retrieves the content of the transaction pool and flattens it into an
@@ -1075,6 +1147,39 @@ func (e *revertError) ErrorData() interface{} {
 	return e.reason
 }
 
+// circuitCapacityError is an API error returned for transactions that would
+// overflow the circuit capacity of an empty block and thus never be included.
+type circuitCapacityError struct {
+	error
+}
+
+// ErrorCode returns the JSON error code for a circuit capacity overflow.
+func (e *circuitCapacityError) ErrorCode() int {
+	return -32010
+}
+
+// conditionError is an API error returned for conditional transactions whose
+// conditions do not hold.
+type conditionError struct {
+	error
+}
+
+// ErrorCode returns the JSON error code for unmet transaction conditions.
+func (e *conditionError) ErrorCode() int {
+	return -32003
+}
+
+// maintenanceError is an API error returned for transactions submitted while
+// the sequencer is in maintenance, which may be retried later.
+type maintenanceError struct {
+	error
+}
+
+// ErrorCode returns the JSON error code for a sequencer in maintenance.
+func (e *maintenanceError) ErrorCode() int {
+	return -32011
+}
+
 // Call executes the given transaction on the state for the given block number.
 //
 // Additionally, the caller can specify a batch of contract for fields overriding.
@@ -1347,6 +1452,9 @@ type RPCTransaction struct {
 	// L1 message transaction fields:
 	Sender     *common.Address `json:"sender,omitempty"`
 	QueueIndex *hexutil.Uint64 `json:"queueIndex,omitempty"`
+
+	// L1 data fee of pooled transactions in the next block
+	L1Fee *hexutil.Big `json:"l1Fee,omitempty"`
 }
 
 // NewRPCTransaction returns a transaction that will serialize to the RPC
@@ -1412,6 +1520,16 @@ func newRPCPendingTransaction(tx *types.Transaction, current *types.Header, conf
 	return NewRPCTransaction(tx, common.Hash{}, blockNumber, 0, baseFee, config)
 }
 
+// newRPCPoolTransaction returns a pooled transaction that will serialize to the
+// RPC representation, including the L1 data fee it would pay in the next block.
+func newRPCPoolTransaction(tx *types.Transaction, current *types.Header, config *params.ChainConfig, state *state.StateDB) *RPCTransaction {
+	result := newRPCPendingTransaction(tx, current, config, fees.GetL1BaseFee(state))
+	if l1DataFee, err := fees.CalculateL1DataFee(tx, state, config, new(big.Int).Add(current.Number, common.Big1)); err == nil {
+		result.L1Fee = (*hexutil.Big)(l1DataFee)
+	}
+	return result
+}
+
 // newRPCTransactionFromBlockIndex returns a transaction that will serialize to the RPC representation.
 func newRPCTransactionFromBlockIndex(b *types.Block, index uint64, config *params.ChainConfig) *RPCTransaction {
 	txs := b.Transactions()
@@ -1767,6 +1885,18 @@ func (s *PublicTransactionPoolAPI) sign(addr common.Address, tx *types.Transacti
 
 // SubmitTransaction is a helper function that submits tx to txPool and logs a message.
 func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
+	send := b.SendTx
+	if f := b.TxForwarder(); f != nil {
+		send = func(ctx context.Context, tx *types.Transaction) error {
+			return f.send(ctx, b, tx)
+		}
+	}
+	return submitTransaction(ctx, b, tx, send)
+}
+
+// submitTransaction is a helper function that submits tx with the given send
+// function and logs a message.
+func submitTransaction(ctx context.Context, b Backend, tx *types.Transaction, send func(context.Context, *types.Transaction) error) (common.Hash, error) {
 	// If the transaction fee cap is already specified, ensure the
 	// fee of the given transaction is _reasonable_.
 	if err := checkTxFee(tx.GasPrice(), tx.Gas(), b.RPCTxFeeCap()); err != nil {
@@ -1776,7 +1906,16 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 		// Ensure only eip155 signed transactions are submitted if EIP155Required is set.
 		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
 	}
-	if err := b.SendTx(ctx, tx); err != nil {
+	if err := send(ctx, tx); err != nil {
+		if errors.Is(err, core.ErrCircuitCapacityOverflow) {
+			return common.Hash{}, &circuitCapacityError{err}
+		}
+		if errors.Is(err, core.ErrConditionNotMet) {
+			return common.Hash{}, &conditionError{err}
+		}
+		if errors.Is(err, core.ErrSequencerMaintenance) {
+			return common.Hash{}, &maintenanceError{err}
+		}
 		return common.Hash{}, err
 	}
 	// Print a log with full tx details for manual investigations and interventions
@@ -1854,6 +1993,100 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, input
 	return SubmitTransaction(ctx, s.b, tx)
 }
 
+// maxConditionalCost is the maximum number of storage lookups required to check
+// the conditions of a conditional transaction.
+const maxConditionalCost = 1000
+
+// SendRawTransactionConditional adds the signed transaction to the transaction
+// pool like SendRawTransaction, for inclusion only in a block where the given
+// conditions hold. They are checked on submission and again right before the
+// execution of the transaction. Conditional transactions are not propagated to
+// peers.
+func (s *PublicTransactionPoolAPI) SendRawTransactionConditional(ctx context.Context, input hexutil.Bytes, cond types.TransactionConditional) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if cost := cond.Cost(); cost > maxConditionalCost {
+		return common.Hash{}, fmt.Errorf("conditions too expensive: %d storage lookups, maximum %d", cost, maxConditionalCost)
+	}
+	if err := cond.Validate(); err != nil {
+		return common.Hash{}, err
+	}
+	tx.SetConditional(&cond)
+	return SubmitTransaction(ctx, s.b, tx)
+}
+
+// PrivateTxArgs represents the options of a private transaction submission.
+type PrivateTxArgs struct {
+	// Lifetime is the number of seconds after which the transaction is dropped
+	// if not included. The node default is used if unset or too large.
+	Lifetime *hexutil.Uint64 `json:"lifetime"`
+}
+
+// SendPrivateRawTransaction adds the signed transaction to the transaction pool
+// like SendRawTransaction, but without announcing it to the network, which
+// protects it from front-running. If the node is configured to, the transaction
+// is forwarded to the sequencer instead. The transaction is dropped if it is not
+// included within its lifetime.
+func (s *PublicTransactionPoolAPI) SendPrivateRawTransaction(ctx context.Context, input hexutil.Bytes, args *PrivateTxArgs) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	var lifetime time.Duration
+	if args != nil && args.Lifetime != nil {
+		lifetime = time.Duration(*args.Lifetime) * time.Second
+	}
+	return submitTransaction(ctx, s.b, tx, func(ctx context.Context, tx *types.Transaction) error {
+		return s.b.SendPrivateTx(ctx, tx, lifetime)
+	})
+}
+
+// PrivateTxStatus is the status of a private transaction. Status is one of
+// "pending", "forwarded" (to the sequencer), "included", "expired" or "dropped"
+// (replaced or evicted before its deadline).
+type PrivateTxStatus struct {
+	Status      string          `json:"status"`
+	Submitted   hexutil.Uint64  `json:"submitted"`
+	Deadline    hexutil.Uint64  `json:"deadline"`
+	BlockHash   *common.Hash    `json:"blockHash,omitempty"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber,omitempty"`
+}
+
+// GetPrivateTransactionStatus returns the status of a transaction submitted with
+// SendPrivateRawTransaction to this node, or nil if it is unknown. Statuses are
+// kept for a while after the deadline of the transaction.
+func (s *PublicTransactionPoolAPI) GetPrivateTransactionStatus(ctx context.Context, hash common.Hash) (*PrivateTxStatus, error) {
+	info, ok := s.b.GetPrivateTxStatus(hash)
+	if !ok {
+		return nil, nil
+	}
+	status := &PrivateTxStatus{
+		Submitted: hexutil.Uint64(info.Added.Unix()),
+		Deadline:  hexutil.Uint64(info.Deadline.Unix()),
+	}
+	tx, blockHash, blockNumber, _, err := s.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case tx != nil:
+		status.Status = "included"
+		status.BlockHash = &blockHash
+		status.BlockNumber = (*hexutil.Uint64)(&blockNumber)
+	case info.Expired:
+		status.Status = "expired"
+	case info.Remote:
+		status.Status = "forwarded"
+	case s.b.GetPoolTxStatus(hash) != core.TxStatusUnknown:
+		status.Status = "pending"
+	default:
+		status.Status = "dropped"
+	}
+	return status, nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccack256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
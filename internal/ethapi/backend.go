@@ -34,6 +34,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/event"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/preconf"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
 
@@ -75,6 +76,12 @@ type Backend interface {
 
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
+	ValidateTx(signedTx *types.Transaction) error
+	TxForwarder() *TxForwarder // nil if transactions are not forwarded
+	SendPrivateTx(ctx context.Context, signedTx *types.Transaction, lifetime time.Duration) error
+	GetPrivateTxStatus(txHash common.Hash) (core.PrivateTxInfo, bool)
+	GetPoolTxStatus(txHash common.Hash) core.TxStatus
+	GetPoolTxEviction(txHash common.Hash) (core.TxEviction, bool)
 	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error)
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
@@ -90,7 +97,9 @@ type Backend interface {
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
 	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
 	SubscribePendingLogsEvent(ch chan<- []*types.Log) event.Subscription
+	SubscribePendingBlockEvent(ch chan<- core.PendingBlockEvent) event.Subscription
 	SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription
+	Preconfirmations() preconf.Source // nil if preconfirmations are unavailable
 
 	ChainConfig() *params.ChainConfig
 	Engine() consensus.Engine
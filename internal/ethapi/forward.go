@@ -0,0 +1,234 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// txForwardRetryDelay is the delay before the first retry of a failed forward,
+// doubled for each subsequent retry.
+const txForwardRetryDelay = 200 * time.Millisecond
+
+var (
+	txForwardMeter      = metrics.NewRegisteredMeter("rpc/txforward/sent", nil)
+	txForwardFailMeter  = metrics.NewRegisteredMeter("rpc/txforward/failed", nil)
+	txForwardRetryMeter = metrics.NewRegisteredMeter("rpc/txforward/retried", nil)
+)
+
+// TxForwarder relays the transactions submitted to a follower node to one or
+// more sequencers, rather than relying on P2P propagation which is unreliable
+// when sequencers limit their peers. The sequencers are dialed on first use and
+// redialed after connection failures, so they need not be up when the node
+// starts.
+type TxForwarder struct {
+	urls    []string
+	retries int  // Number of retries per endpoint on connection failures
+	noLocal bool // Whether to skip the local pool after validating transactions
+
+	dial    func(ctx context.Context, url string) (*rpc.Client, error)
+	clients []*rpc.Client // Connection to each sequencer, nil until dialed
+	closed  bool
+	lock    sync.Mutex
+}
+
+// NewTxForwarder creates a forwarder to the given sequencer RPC endpoints.
+func NewTxForwarder(urls []string, retries int, noLocal bool) (*TxForwarder, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no transaction forwarding endpoint")
+	}
+	return &TxForwarder{
+		urls:    urls,
+		retries: retries,
+		noLocal: noLocal,
+		dial:    rpc.DialContext,
+		clients: make([]*rpc.Client, len(urls)),
+	}, nil
+}
+
+// Close closes the connections to the sequencers.
+func (f *TxForwarder) Close() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for i, client := range f.clients {
+		if client != nil {
+			client.Close()
+			f.clients[i] = nil
+		}
+	}
+	f.closed = true
+}
+
+// client returns the connection to the i-th sequencer, dialing it if needed.
+func (f *TxForwarder) client(ctx context.Context, i int) (*rpc.Client, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return nil, rpc.ErrClientQuit
+	}
+	if f.clients[i] == nil {
+		client, err := f.dial(ctx, f.urls[i])
+		if err != nil {
+			return nil, fmt.Errorf("cannot connect to %s: %w", f.urls[i], err)
+		}
+		f.clients[i] = client
+	}
+	return f.clients[i], nil
+}
+
+// drop closes the connection to the i-th sequencer after a failure, so that it
+// is redialed on next use.
+func (f *TxForwarder) drop(i int, client *rpc.Client) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.clients[i] == client {
+		client.Close()
+		f.clients[i] = nil
+	}
+}
+
+// isClosed returns whether the forwarder was closed.
+func (f *TxForwarder) isClosed() bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.closed
+}
+
+// send validates the transaction against the local pool, adding it unless the
+// forwarder skips the local pool, and forwards it to the sequencers. If the
+// transaction is in the local pool, forwarding failures are only logged since
+// the transaction still propagates over P2P.
+func (f *TxForwarder) send(ctx context.Context, b Backend, tx *types.Transaction) error {
+	if f.noLocal {
+		if err := b.ValidateTx(tx); err != nil {
+			return err
+		}
+		return f.forward(ctx, tx)
+	}
+	if err := b.SendTx(ctx, tx); err != nil {
+		return err
+	}
+	if err := f.forward(ctx, tx); err != nil {
+		log.Warn("Failed to forward transaction", "hash", tx.Hash(), "err", err)
+	}
+	return nil
+}
+
+// SendPrivate forwards a private transaction to the sequencers with the given
+// lifetime, zero meaning the default one of the sequencers.
+func (f *TxForwarder) SendPrivate(ctx context.Context, tx *types.Transaction, lifetime time.Duration) error {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	var args PrivateTxArgs
+	if lifetime > 0 {
+		seconds := hexutil.Uint64(lifetime / time.Second)
+		args.Lifetime = &seconds
+	}
+	return f.call(ctx, tx, "eth_sendPrivateRawTransaction", hexutil.Bytes(data), args)
+}
+
+// forward sends the transaction to the sequencers.
+func (f *TxForwarder) forward(ctx context.Context, tx *types.Transaction) error {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	// Conditional transactions are forwarded with their conditions
+	method, args := "eth_sendRawTransaction", []interface{}{hexutil.Bytes(data)}
+	if cond := tx.Conditional(); cond != nil {
+		method, args = "eth_sendRawTransactionConditional", append(args, cond)
+	}
+	return f.call(ctx, tx, method, args...)
+}
+
+// call invokes the sending method on all sequencers concurrently, succeeding if
+// any of them accepts the transaction. Failures to reach a sequencer are
+// retried, while errors returned by it are final.
+func (f *TxForwarder) call(ctx context.Context, tx *types.Transaction, method string, args ...interface{}) error {
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(f.urls))
+	)
+	for i := range f.urls {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = f.forwardTo(ctx, i, method, args...)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err == nil {
+			txForwardMeter.Mark(1)
+			return nil
+		}
+	}
+	txForwardFailMeter.Mark(1)
+	for i, err := range errs {
+		log.Debug("Failed to forward transaction", "hash", tx.Hash(), "endpoint", f.urls[i], "err", err)
+	}
+	// Return an error of the sequencer if any, as it is the most meaningful
+	// to the user.
+	for _, err := range errs {
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) {
+			return err
+		}
+	}
+	return errs[0]
+}
+
+// forwardTo calls the sending method on the i-th sequencer, redialing and
+// retrying on connection failures.
+func (f *TxForwarder) forwardTo(ctx context.Context, i int, method string, args ...interface{}) error {
+	delay := txForwardRetryDelay
+	for attempt := 0; ; attempt++ {
+		client, err := f.client(ctx, i)
+		if err == nil {
+			var hash common.Hash
+			if err = client.CallContext(ctx, &hash, method, args...); err == nil {
+				return nil
+			}
+			var rpcErr rpc.Error
+			if errors.As(err, &rpcErr) {
+				// The transaction may have reached the sequencer over P2P already
+				if strings.Contains(err.Error(), core.ErrAlreadyKnown.Error()) {
+					return nil
+				}
+				return err
+			}
+			f.drop(i, client)
+		}
+		if errors.Is(err, rpc.ErrClientQuit) && f.isClosed() {
+			return err
+		}
+		if attempt >= f.retries {
+			return err
+		}
+		txForwardRetryMeter.Mark(1)
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// testSequencer is an RPC service accepting raw transactions unless configured
+// to fail with a given error.
+type testSequencer struct {
+	err      error
+	calls    int
+	lifetime *hexutil.Uint64 // Lifetime of the last private transaction
+}
+
+func (s *testSequencer) SendPrivateRawTransaction(input hexutil.Bytes, args *PrivateTxArgs) (common.Hash, error) {
+	if args != nil {
+		s.lifetime = args.Lifetime
+	}
+	return s.SendRawTransaction(input)
+}
+
+func (s *testSequencer) SendRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	s.calls++
+	if s.err != nil {
+		return common.Hash{}, s.err
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// newTestForwarder creates a forwarder to in-process sequencers, failing the
+// first given number of dials to each of them.
+func newTestForwarder(t *testing.T, retries int, failedDials int, sequencers ...*testSequencer) *TxForwarder {
+	var (
+		urls    []string
+		servers = make(map[string]*rpc.Server)
+		dials   = make(map[string]int)
+		lock    sync.Mutex
+	)
+	for i, seq := range sequencers {
+		server := rpc.NewServer()
+		if err := server.RegisterName("eth", seq); err != nil {
+			t.Fatalf("failed to register sequencer: %v", err)
+		}
+		t.Cleanup(server.Stop)
+		url := fmt.Sprintf("inproc-%d", i)
+		urls, servers[url] = append(urls, url), server
+	}
+	f, err := NewTxForwarder(urls, retries, false)
+	if err != nil {
+		t.Fatalf("failed to create forwarder: %v", err)
+	}
+	f.dial = func(ctx context.Context, url string) (*rpc.Client, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if dials[url]++; dials[url] <= failedDials {
+			return nil, errors.New("connection refused")
+		}
+		return rpc.DialInProc(servers[url]), nil
+	}
+	t.Cleanup(f.Close)
+	return f
+}
+
+func TestTxForwarder(t *testing.T) {
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+
+	// A single sequencer accepting the transaction is enough
+	failing, accepting := &testSequencer{err: errors.New("nonce too low")}, &testSequencer{}
+	if err := newTestForwarder(t, 3, 0, failing, accepting).forward(context.Background(), tx); err != nil {
+		t.Fatalf("failed to forward transaction: %v", err)
+	}
+	// Errors of the sequencers are returned without retrying
+	failing.calls = 0
+	err := newTestForwarder(t, 3, 0, failing).forward(context.Background(), tx)
+	if err == nil || err.Error() != "nonce too low" {
+		t.Fatalf("forward error mismatch: have %v, want %v", err, failing.err)
+	}
+	if failing.calls != 1 {
+		t.Fatalf("sequencer call count mismatch: have %d, want 1", failing.calls)
+	}
+	// Transactions already known to the sequencer are forwarded
+	known := &testSequencer{err: errors.New("already known")}
+	if err := newTestForwarder(t, 3, 0, known).forward(context.Background(), tx); err != nil {
+		t.Fatalf("failed to forward known transaction: %v", err)
+	}
+	// Unreachable sequencers are redialed until the retries run out
+	if err := newTestForwarder(t, 1, 1, &testSequencer{}).forward(context.Background(), tx); err != nil {
+		t.Fatalf("failed to forward transaction after redial: %v", err)
+	}
+	if err := newTestForwarder(t, 1, 2, &testSequencer{}).forward(context.Background(), tx); err == nil {
+		t.Fatalf("forwarded transaction to unreachable sequencer")
+	}
+	// Broken connections are redialed
+	f := newTestForwarder(t, 1, 0, &testSequencer{})
+	if err := f.forward(context.Background(), tx); err != nil {
+		t.Fatalf("failed to forward transaction: %v", err)
+	}
+	f.clients[0].Close()
+	if err := f.forward(context.Background(), tx); err != nil {
+		t.Fatalf("failed to forward transaction after reconnect: %v", err)
+	}
+	// Closed forwarders fail without retrying
+	f.Close()
+	if err := f.forward(context.Background(), tx); !errors.Is(err, rpc.ErrClientQuit) {
+		t.Fatalf("forward error mismatch: have %v, want %v", err, rpc.ErrClientQuit)
+	}
+}
+
+func TestTxForwarderPrivate(t *testing.T) {
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+
+	seq := &testSequencer{}
+	if err := newTestForwarder(t, 0, 0, seq).SendPrivate(context.Background(), tx, time.Minute); err != nil {
+		t.Fatalf("failed to forward private transaction: %v", err)
+	}
+	if seq.calls != 1 || seq.lifetime == nil || *seq.lifetime != 60 {
+		t.Fatalf("private transaction forward mismatch: calls %d, lifetime %v", seq.calls, seq.lifetime)
+	}
+}
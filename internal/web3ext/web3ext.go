@@ -80,6 +80,12 @@ web3._extend({
 			params: 1,
 			inputFormatter: [null]
 		}),
+		new web3._extend.Method({
+			name: 'getEquivocations',
+			call: 'clique_getEquivocations',
+			params: 1,
+			inputFormatter: [null]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -153,6 +159,17 @@ web3._extend({
 			call: 'admin_importChain',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'txpoolPolicy',
+			call: 'admin_txpoolPolicy',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'sequencerMaintenance',
+			call: 'admin_sequencerMaintenance',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'sleepBlocks',
 			call: 'admin_sleepBlocks',
@@ -517,6 +534,22 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'sendRawTransactionConditional',
+			call: 'eth_sendRawTransactionConditional',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'sendPrivateRawTransaction',
+			call: 'eth_sendPrivateRawTransaction',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getPrivateTransactionStatus',
+			call: 'eth_getPrivateTransactionStatus',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'fillTransaction',
 			call: 'eth_fillTransaction',
@@ -658,7 +691,12 @@ web3._extend({
 			call: 'miner_getHashrate'
 		}),
 	],
-	properties: []
+	properties: [
+		new web3._extend.Property({
+			name: 'status',
+			getter: 'miner_status'
+		}),
+	]
 });
 `
 
@@ -772,6 +810,11 @@ web3._extend({
 			call: 'txpool_contentFrom',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'transactionStatus',
+			call: 'txpool_status',
+			params: 1,
+		}),
 	]
 });
 `
@@ -882,6 +925,12 @@ web3._extend({
 			params: 3,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null, null]
 		}),
+		new web3._extend.Method({
+			name: 'getBatchWitness',
+			call: 'scroll_getBatchWitness',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
 		new web3._extend.Method({
 			name: 'getL1MessageByIndex',
 			call: 'scroll_getL1MessageByIndex',
@@ -921,6 +970,17 @@ web3._extend({
 			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputBlockNumberFormatter],
 			outputFormatter: web3._extend.utils.toDecimal
 		}),
+		new web3._extend.Method({
+			name: 'feeHistory',
+			call: 'scroll_feeHistory',
+			params: 4,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'feeForecast',
+			call: 'scroll_feeForecast',
+			params: 3
+		}),
 	],
 	properties:
 	[
@@ -940,6 +1000,10 @@ web3._extend({
 			name: 'syncStatus',
 			getter: 'scroll_syncStatus',
 		}),
+		new web3._extend.Property({
+			name: 'l1MessageBacklog',
+			getter: 'scroll_getL1MessageBacklog',
+		}),
 	]
 });
 `
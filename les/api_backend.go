@@ -36,8 +36,10 @@ import (
 	"github.com/scroll-tech/go-ethereum/eth/gasprice"
 	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/event"
+	"github.com/scroll-tech/go-ethereum/internal/ethapi"
 	"github.com/scroll-tech/go-ethereum/light"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/preconf"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
 
@@ -201,6 +203,33 @@ func (b *LesApiBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return b.eth.txPool.Add(ctx, signedTx)
 }
 
+func (b *LesApiBackend) ValidateTx(signedTx *types.Transaction) error {
+	return errors.New("transaction validation is not supported by light clients")
+}
+
+func (b *LesApiBackend) TxForwarder() *ethapi.TxForwarder {
+	return nil
+}
+
+func (b *LesApiBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction, lifetime time.Duration) error {
+	return errors.New("private transactions are not supported by light clients")
+}
+
+func (b *LesApiBackend) GetPrivateTxStatus(hash common.Hash) (core.PrivateTxInfo, bool) {
+	return core.PrivateTxInfo{}, false
+}
+
+func (b *LesApiBackend) GetPoolTxStatus(hash common.Hash) core.TxStatus {
+	if b.eth.txPool.GetTransaction(hash) != nil {
+		return core.TxStatusPending
+	}
+	return core.TxStatusUnknown
+}
+
+func (b *LesApiBackend) GetPoolTxEviction(hash common.Hash) (core.TxEviction, bool) {
+	return core.TxEviction{}, false
+}
+
 func (b *LesApiBackend) RemoveTx(txHash common.Hash) {
 	b.eth.txPool.RemoveTx(txHash)
 }
@@ -264,6 +293,17 @@ func (b *LesApiBackend) SubscribePendingLogsEvent(ch chan<- []*types.Log) event.
 	})
 }
 
+func (b *LesApiBackend) SubscribePendingBlockEvent(ch chan<- core.PendingBlockEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
+func (b *LesApiBackend) Preconfirmations() preconf.Source {
+	return nil
+}
+
 func (b *LesApiBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
 	return b.eth.blockchain.SubscribeRemovedLogsEvent(ch)
 }
@@ -0,0 +1,467 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/tsdb/fileutil"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+const (
+	// DefaultLeaseTimeout is the default duration of the sequencer lease, after
+	// which a standby sequencer takes over.
+	DefaultLeaseTimeout = 15 * time.Second
+
+	// leaseLockRetries is the number of attempts to lock a lease file held by
+	// another sequencer, leaseLockDelay apart.
+	leaseLockRetries = 20
+	leaseLockDelay   = 25 * time.Millisecond
+
+	// leaseRetryDelay is the delay before building a block again after failing
+	// to claim its height under the lease.
+	leaseRetryDelay = time.Second
+)
+
+var (
+	// ErrLeaseNotHeld is returned when claiming a block under a lease that is
+	// held by another sequencer or expired.
+	ErrLeaseNotHeld = errors.New("sequencer lease not held")
+
+	// ErrLeaseHeightClaimed is returned when claiming a block at a height that
+	// was already signed under the lease.
+	ErrLeaseHeightClaimed = errors.New("block height already claimed under the sequencer lease")
+)
+
+// Lease is a leader-election lease shared by the sequencers of a highly available
+// setup. Only the holder of the lease produces blocks, and every block has to be
+// claimed under the lease before being published, so that no two blocks are ever
+// signed at the same height even if sequencers disagree on the lease holder. The
+// last claimed block is kept in the lease, so that the next holder can publish
+// it if its sequencer stopped before doing so.
+type Lease interface {
+	// Acquire takes or renews the lease for the given holder for the given
+	// duration, unless another holder has it. It returns whether the lease is
+	// held by the holder.
+	Acquire(ctx context.Context, holder string, duration time.Duration) (bool, error)
+
+	// Claim records that the holder of the lease signs the given block. It
+	// fails unless the lease is held by the holder and the height is above
+	// all the heights claimed before.
+	Claim(ctx context.Context, holder string, block *types.Block) error
+
+	// Last returns the last block claimed under the lease, or nil if none.
+	Last(ctx context.Context) (*types.Block, error)
+
+	// Release gives up the lease if held by the given holder, letting another
+	// sequencer take over without waiting for its expiry.
+	Release(ctx context.Context, holder string) error
+
+	// Close releases the resources of the lease backend.
+	Close() error
+}
+
+// NewLease opens the lease backend at the given location, which is either the
+// URL of an RPC endpoint serving a LeaseService or the path of a lease file
+// shared by the sequencers.
+//
+// A LeaseService is served by a node started with a lease file to serve, see
+// Config.HALeaseServe.
+func NewLease(location string) (Lease, error) {
+	for _, prefix := range []string{"http://", "https://", "ws://", "wss://"} {
+		if strings.HasPrefix(location, prefix) {
+			return newRPCLease(location)
+		}
+	}
+	if strings.HasSuffix(location, ".ipc") {
+		return newRPCLease(location)
+	}
+	return newFileLease(location), nil
+}
+
+// leaseState is the shared state of a lease.
+type leaseState struct {
+	Holder string      `json:"holder"`
+	Expiry time.Time   `json:"expiry"`
+	Height uint64      `json:"height"` // Number of the last block claimed under the lease
+	Hash   common.Hash `json:"hash"`   // Hash of the last block claimed under the lease
+
+	Block hexutil.Bytes `json:"block,omitempty"` // RLP encoding of the last block claimed under the lease
+}
+
+// acquire takes or renews the lease for the holder, unless another holder has
+// it and it has not expired.
+func (s *leaseState) acquire(holder string, now time.Time, duration time.Duration) bool {
+	if s.Holder != holder && now.Before(s.Expiry) {
+		return false
+	}
+	s.Holder, s.Expiry = holder, now.Add(duration)
+	return true
+}
+
+// claim records a block signed by the holder of the lease.
+func (s *leaseState) claim(holder string, now time.Time, block *types.Block) error {
+	if s.Holder != holder || !now.Before(s.Expiry) {
+		return ErrLeaseNotHeld
+	}
+	if block.NumberU64() <= s.Height {
+		return fmt.Errorf("%w: claimed #%d, last #%d [%x…]", ErrLeaseHeightClaimed, block.NumberU64(), s.Height, s.Hash[:4])
+	}
+	data, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return err
+	}
+	s.Height, s.Hash, s.Block = block.NumberU64(), block.Hash(), data
+	return nil
+}
+
+// last decodes the last block claimed under the lease.
+func (s *leaseState) last() (*types.Block, error) {
+	return decodeLeaseBlock(s.Block)
+}
+
+// decodeLeaseBlock decodes a block claimed under a lease, or returns nil if no
+// block is given.
+func decodeLeaseBlock(data []byte) (*types.Block, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(data, block); err != nil {
+		return nil, fmt.Errorf("invalid block claimed under sequencer lease: %w", err)
+	}
+	return block, nil
+}
+
+// release gives up the lease if held by the holder.
+func (s *leaseState) release(holder string) {
+	if s.Holder == holder {
+		s.Holder, s.Expiry = "", time.Time{}
+	}
+}
+
+// memoryLease is a lease held in memory, meant to be shared over RPC by a
+// LeaseService.
+type memoryLease struct {
+	state leaseState
+	lock  sync.Mutex
+}
+
+// NewMemoryLease creates a lease kept in memory.
+func NewMemoryLease() Lease {
+	return new(memoryLease)
+}
+
+func (l *memoryLease) Acquire(ctx context.Context, holder string, duration time.Duration) (bool, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.state.acquire(holder, time.Now(), duration), nil
+}
+
+func (l *memoryLease) Claim(ctx context.Context, holder string, block *types.Block) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.state.claim(holder, time.Now(), block)
+}
+
+func (l *memoryLease) Last(ctx context.Context) (*types.Block, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.state.last()
+}
+
+func (l *memoryLease) Release(ctx context.Context, holder string) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.state.release(holder)
+	return nil
+}
+
+func (l *memoryLease) Close() error {
+	return nil
+}
+
+// fileLease is a lease stored in a file shared by sequencers on the same host
+// or on a shared filesystem with working locks. Updates are done under an
+// exclusive lock of a sibling file.
+type fileLease struct {
+	path string
+}
+
+func newFileLease(path string) *fileLease {
+	return &fileLease{path: path}
+}
+
+// NewFileLease creates a lease stored in the file at the given path.
+func NewFileLease(path string) Lease {
+	return newFileLease(path)
+}
+
+// update applies the given function to the lease state under the file lock,
+// persisting the state if the function succeeds.
+func (l *fileLease) update(ctx context.Context, fn func(state *leaseState) error) error {
+	var (
+		lock fileutil.Releaser
+		err  error
+	)
+	for attempt := 0; ; attempt++ {
+		if lock, _, err = fileutil.Flock(l.path + ".lock"); err == nil {
+			break
+		}
+		if attempt >= leaseLockRetries {
+			return fmt.Errorf("cannot lock sequencer lease: %w", err)
+		}
+		select {
+		case <-time.After(leaseLockDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	defer lock.Release()
+
+	var state leaseState
+	data, err := os.ReadFile(l.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(data, &state); err != nil {
+			return fmt.Errorf("invalid sequencer lease: %w", err)
+		}
+	}
+	if err := fn(&state); err != nil {
+		return err
+	}
+	if data, err = json.Marshal(state); err != nil {
+		return err
+	}
+	tmp := l.path + ".new"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.path)
+}
+
+func (l *fileLease) Acquire(ctx context.Context, holder string, duration time.Duration) (bool, error) {
+	var held bool
+	err := l.update(ctx, func(state *leaseState) error {
+		held = state.acquire(holder, time.Now(), duration)
+		return nil
+	})
+	return held, err
+}
+
+func (l *fileLease) Claim(ctx context.Context, holder string, block *types.Block) error {
+	return l.update(ctx, func(state *leaseState) error {
+		return state.claim(holder, time.Now(), block)
+	})
+}
+
+func (l *fileLease) Last(ctx context.Context) (*types.Block, error) {
+	var block *types.Block
+	err := l.update(ctx, func(state *leaseState) (err error) {
+		block, err = state.last()
+		return err
+	})
+	return block, err
+}
+
+func (l *fileLease) Release(ctx context.Context, holder string) error {
+	return l.update(ctx, func(state *leaseState) error {
+		state.release(holder)
+		return nil
+	})
+}
+
+func (l *fileLease) Close() error {
+	return nil
+}
+
+// rpcLease is a lease served by a LeaseService over RPC.
+type rpcLease struct {
+	client *rpc.Client
+}
+
+func newRPCLease(url string) (*rpcLease, error) {
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to sequencer lease at %s: %w", url, err)
+	}
+	return &rpcLease{client: client}, nil
+}
+
+func (l *rpcLease) Acquire(ctx context.Context, holder string, duration time.Duration) (bool, error) {
+	var held bool
+	err := l.client.CallContext(ctx, &held, "lease_acquire", holder, uint64(duration/time.Millisecond))
+	return held, err
+}
+
+func (l *rpcLease) Claim(ctx context.Context, holder string, block *types.Block) error {
+	data, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return err
+	}
+	return l.client.CallContext(ctx, nil, "lease_claim", holder, hexutil.Bytes(data))
+}
+
+func (l *rpcLease) Last(ctx context.Context) (*types.Block, error) {
+	var data hexutil.Bytes
+	if err := l.client.CallContext(ctx, &data, "lease_last"); err != nil {
+		return nil, err
+	}
+	return decodeLeaseBlock(data)
+}
+
+func (l *rpcLease) Release(ctx context.Context, holder string) error {
+	return l.client.CallContext(ctx, nil, "lease_release", holder)
+}
+
+func (l *rpcLease) Close() error {
+	l.client.Close()
+	return nil
+}
+
+// LeaseService exposes a lease over RPC under the "lease" namespace, to be
+// shared by sequencers on different hosts.
+type LeaseService struct {
+	lease Lease
+}
+
+// NewLeaseService creates an RPC service for the given lease.
+func NewLeaseService(lease Lease) *LeaseService {
+	return &LeaseService{lease: lease}
+}
+
+// Acquire takes or renews the lease for the holder for the given number of
+// milliseconds.
+func (s *LeaseService) Acquire(ctx context.Context, holder string, duration uint64) (bool, error) {
+	return s.lease.Acquire(ctx, holder, time.Duration(duration)*time.Millisecond)
+}
+
+// Claim records a block signed by the holder of the lease, given as RLP.
+func (s *LeaseService) Claim(ctx context.Context, holder string, block hexutil.Bytes) error {
+	decoded, err := decodeLeaseBlock(block)
+	if err != nil {
+		return err
+	}
+	if decoded == nil {
+		return errors.New("missing block")
+	}
+	return s.lease.Claim(ctx, holder, decoded)
+}
+
+// Last returns the RLP of the last block claimed under the lease, or nothing
+// if none.
+func (s *LeaseService) Last(ctx context.Context) (hexutil.Bytes, error) {
+	block, err := s.lease.Last(ctx)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(block)
+}
+
+// Release gives up the lease if held by the holder.
+func (s *LeaseService) Release(ctx context.Context, holder string) error {
+	return s.lease.Release(ctx, holder)
+}
+
+// sequencerLease tracks the leadership of this node under a lease.
+type sequencerLease struct {
+	backend Lease
+	holder  string
+	timeout time.Duration
+	expiry  time.Time // Local deadline of the lease, zero if not held
+}
+
+// renew acquires or renews the lease if the node campaigns for leadership, and
+// releases it otherwise. It returns whether the node leads.
+func (l *sequencerLease) renew(campaign bool) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout/3)
+	defer cancel()
+
+	if !campaign {
+		if !l.expiry.IsZero() {
+			l.release(ctx)
+		}
+		return false
+	}
+	start := time.Now()
+	held, err := l.backend.Acquire(ctx, l.holder, l.timeout)
+	if err != nil {
+		log.Warn("Failed to renew sequencer lease", "holder", l.holder, "err", err)
+
+		// Keep leading until the lease expires, blocks are claimed anyway
+		return time.Now().Before(l.expiry)
+	}
+	switch {
+	case held && l.expiry.IsZero():
+		log.Info("Acquired sequencer lease", "holder", l.holder, "timeout", l.timeout)
+	case !held && !l.expiry.IsZero():
+		log.Warn("Lost sequencer lease", "holder", l.holder)
+	}
+	if held {
+		l.expiry = start.Add(l.timeout)
+	} else {
+		l.expiry = time.Time{}
+	}
+	return held
+}
+
+// release gives up the lease.
+func (l *sequencerLease) release(ctx context.Context) {
+	if err := l.backend.Release(ctx, l.holder); err != nil {
+		log.Warn("Failed to release sequencer lease", "holder", l.holder, "err", err)
+	} else {
+		log.Info("Released sequencer lease", "holder", l.holder)
+	}
+	l.expiry = time.Time{}
+}
+
+// claim records the block about to be published under the lease.
+func (l *sequencerLease) claim(block *types.Block) error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout/3)
+	defer cancel()
+
+	return l.backend.Claim(ctx, l.holder, block)
+}
+
+// last retrieves the last block claimed under the lease.
+func (l *sequencerLease) last() (*types.Block, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout/3)
+	defer cancel()
+
+	return l.backend.Last(ctx)
+}
@@ -0,0 +1,223 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/accounts"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/consensus/clique"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/core/vm"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/event"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+func TestMemoryLease(t *testing.T) {
+	testLease(t, NewMemoryLease())
+}
+
+func TestFileLease(t *testing.T) {
+	testLease(t, newFileLease(filepath.Join(t.TempDir(), "lease.json")))
+}
+
+func TestRPCLease(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("lease", NewLeaseService(NewMemoryLease())); err != nil {
+		t.Fatalf("failed to register lease service: %v", err)
+	}
+	defer server.Stop()
+
+	lease := &rpcLease{client: rpc.DialInProc(server)}
+	defer lease.Close()
+
+	testLease(t, lease)
+}
+
+func testLease(t *testing.T, lease Lease) {
+	ctx := context.Background()
+
+	// The first sequencer acquires the lease, the second one has to wait
+	if held, err := lease.Acquire(ctx, "a", 200*time.Millisecond); !held || err != nil {
+		t.Fatalf("failed to acquire lease: held %v, err %v", held, err)
+	}
+	if held, err := lease.Acquire(ctx, "b", time.Minute); held || err != nil {
+		t.Fatalf("acquired held lease: held %v, err %v", held, err)
+	}
+	blocks := make([]*types.Block, 4)
+	for i := range blocks {
+		blocks[i] = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i)), Extra: []byte{byte(i)}})
+	}
+	blocks[3] = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Extra: []byte{3}})
+
+	if block, err := lease.Last(ctx); block != nil || err != nil {
+		t.Fatalf("unclaimed lease last block mismatch: have %v, err %v", block, err)
+	}
+	if err := lease.Claim(ctx, "b", blocks[1]); !strings.Contains(errString(err), ErrLeaseNotHeld.Error()) {
+		t.Fatalf("claim error mismatch: have %v, want %v", err, ErrLeaseNotHeld)
+	}
+	if err := lease.Claim(ctx, "a", blocks[1]); err != nil {
+		t.Fatalf("failed to claim block: %v", err)
+	}
+	if err := lease.Claim(ctx, "a", blocks[3]); !strings.Contains(errString(err), ErrLeaseHeightClaimed.Error()) {
+		t.Fatalf("claim error mismatch: have %v, want %v", err, ErrLeaseHeightClaimed)
+	}
+	// The second sequencer takes over once the lease expires, but cannot sign
+	// at the heights claimed by the first one
+	time.Sleep(250 * time.Millisecond)
+	if held, err := lease.Acquire(ctx, "b", 200*time.Millisecond); !held || err != nil {
+		t.Fatalf("failed to acquire expired lease: held %v, err %v", held, err)
+	}
+	if err := lease.Claim(ctx, "a", blocks[2]); !strings.Contains(errString(err), ErrLeaseNotHeld.Error()) {
+		t.Fatalf("claim error mismatch: have %v, want %v", err, ErrLeaseNotHeld)
+	}
+	if err := lease.Claim(ctx, "b", blocks[3]); !strings.Contains(errString(err), ErrLeaseHeightClaimed.Error()) {
+		t.Fatalf("claim error mismatch: have %v, want %v", err, ErrLeaseHeightClaimed)
+	}
+	// The block claimed by the first sequencer is available for republishing
+	if block, err := lease.Last(ctx); err != nil || block == nil || block.Hash() != blocks[1].Hash() {
+		t.Fatalf("last claimed block mismatch: have %v, err %v, want %x", block, err, blocks[1].Hash())
+	}
+	if err := lease.Claim(ctx, "b", blocks[2]); err != nil {
+		t.Fatalf("failed to claim block: %v", err)
+	}
+	// Released leases are taken over immediately
+	if err := lease.Release(ctx, "b"); err != nil {
+		t.Fatalf("failed to release lease: %v", err)
+	}
+	if held, err := lease.Acquire(ctx, "a", time.Minute); !held || err != nil {
+		t.Fatalf("failed to acquire released lease: held %v, err %v", held, err)
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// Tests that a standby miner only starts once the lease of the active one
+// expires, and stops when it loses the lease.
+func TestMinerLease(t *testing.T) {
+	lease := NewMemoryLease()
+	if held, _ := lease.Acquire(context.Background(), "active", 300*time.Millisecond); !held {
+		t.Fatalf("failed to acquire lease")
+	}
+	miner, _ := createMinerWithLease(t, lease, "standby", 150*time.Millisecond)
+	defer miner.Close()
+
+	miner.Start(common.HexToAddress("0x12345"))
+	time.Sleep(100 * time.Millisecond)
+	if miner.Mining() {
+		t.Fatalf("standby mining while the lease is held")
+	}
+	waitForMiningState(t, miner, true)
+
+	// Losing the lease stops mining
+	mem := lease.(*memoryLease)
+	mem.lock.Lock()
+	mem.state.Holder, mem.state.Expiry = "active", time.Now().Add(time.Minute)
+	mem.lock.Unlock()
+	waitForMiningState(t, miner, false)
+}
+
+// Tests that a sequencer taking over the lease publishes the block claimed by
+// the previous holder if it stopped before publishing it, and builds on it.
+func TestLeaseRepublish(t *testing.T) {
+	var (
+		db          = rawdb.NewMemoryDatabase()
+		chainConfig = newCliqueChainConfig(&params.CliqueConfig{Period: 1, Epoch: 30000})
+	)
+
+	// The crashed sequencer seals and claims a block, without publishing it
+	crashed, b := newTestWorker(t, chainConfig, clique.New(chainConfig.Clique, db), db, 0)
+	sub := crashed.mux.Subscribe(core.NewMinedBlockEvent{})
+	crashed.start()
+	b.txPool.AddLocal(b.newRandomTx(false))
+
+	var claimed *types.Block
+	select {
+	case ev := <-sub.Chan():
+		claimed = ev.Data.(core.NewMinedBlockEvent).Block
+	case <-time.After(3 * time.Second):
+		t.Fatalf("no block sealed")
+	}
+	sub.Unsubscribe()
+	crashed.close()
+
+	lease := NewMemoryLease()
+	lease.(*memoryLease).state.Holder = "crashed"
+	lease.(*memoryLease).state.Expiry = time.Now().Add(time.Minute)
+	if err := lease.Claim(context.Background(), "crashed", claimed); err != nil {
+		t.Fatalf("failed to claim block: %v", err)
+	}
+	lease.(*memoryLease).state.Expiry = time.Time{}
+
+	// The standby sequencer shares the genesis but not the claimed block
+	db2 := rawdb.NewMemoryDatabase()
+	b.genesis.MustCommit(db2)
+	engine := clique.New(chainConfig.Clique, db2)
+	engine.Authorize(testBankAddress, func(account accounts.Account, s string, data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), testBankKey)
+	})
+	chain, _ := core.NewBlockChain(db2, nil, chainConfig, engine, vm.Config{}, nil, nil)
+	defer chain.Stop()
+	b2 := &testWorkerBackend{db: db2, chain: chain, txPool: core.NewTxPool(testTxPoolConfig, chainConfig, chain), genesis: b.genesis}
+
+	standby := newWorker(testConfig, chainConfig, engine, b2, new(event.TypeMux), nil, false)
+	standby.setEtherbase(testBankAddress)
+	standby.lease = &sequencerLease{backend: lease, holder: "standby", timeout: time.Minute}
+	defer standby.close()
+
+	if held, _ := lease.Acquire(context.Background(), "standby", time.Minute); !held {
+		t.Fatalf("failed to acquire lease")
+	}
+	sub = standby.mux.Subscribe(core.NewMinedBlockEvent{})
+	defer sub.Unsubscribe()
+	standby.start()
+	b2.txPool.AddLocal(b2.newRandomTx(false))
+
+	for i, want := range []uint64{1, 2} {
+		select {
+		case ev := <-sub.Chan():
+			block := ev.Data.(core.NewMinedBlockEvent).Block
+			if block.NumberU64() != want {
+				t.Fatalf("block %d number mismatch: have %d, want %d", i, block.NumberU64(), want)
+			}
+			if want == 1 {
+				if block.Hash() != claimed.Hash() {
+					t.Fatalf("republished block mismatch: have %x, want %x", block.Hash(), claimed.Hash())
+				}
+				tx, _ := types.SignTx(types.NewTransaction(1, testUserAddress, big.NewInt(1000), params.TxGas, big.NewInt(10*params.InitialBaseFee), nil), types.HomesteadSigner{}, testBankKey)
+				b2.txPool.AddLocal(tx)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no block %d published", want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// Reasons for which the worker closed the last block it was building.
+const (
+	CloseReasonDeadline        = "deadline"        // The block time elapsed
+	CloseReasonCircuitCapacity = "circuitCapacity" // The next transaction overflowed the circuit capacity
+	CloseReasonMaintenance     = "maintenance"     // The block was drained to enter maintenance
+	CloseReasonHeartbeat       = "heartbeat"       // The block was closed to keep up the heartbeat
+	CloseReasonAborted         = "aborted"         // The block was dropped for a new chain head
+)
+
+// errWorkerClosed is returned when requesting a worker that was closed.
+var errWorkerClosed = errors.New("worker closed")
+
+// PipelineStatus describes the block being built by the worker.
+type PipelineStatus struct {
+	Number     hexutil.Uint64 `json:"number"`
+	ParentHash common.Hash    `json:"parentHash"`
+	Sequencing bool           `json:"sequencing"` // False if only built for the pending block
+	Heartbeat  bool           `json:"heartbeat"`  // Whether the block is closed right away to keep up the heartbeat
+	StartedAt  uint64         `json:"startedAt"`  // Unix time in milliseconds
+}
+
+// Status describes the block production of the worker.
+type Status struct {
+	Running         bool            `json:"running"`
+	Maintenance     bool            `json:"maintenance"`
+	Pipeline        *PipelineStatus `json:"pipeline"` // nil if no block is being built
+	LastCloseReason string          `json:"lastCloseReason,omitempty"`
+	LastClosedAt    uint64          `json:"lastClosedAt,omitempty"` // Unix time in milliseconds
+	LastCommitError string          `json:"lastCommitError,omitempty"`
+}
+
+// maintenanceReq is a request to the main loop to enter or leave maintenance.
+type maintenanceReq struct {
+	enable bool
+	done   chan struct{}
+}
+
+// inMaintenance returns whether the worker is in maintenance.
+func (w *worker) inMaintenance() bool {
+	return atomic.LoadInt32(&w.maintenance) == 1
+}
+
+// isSequencing returns whether the worker builds blocks to seal, that is if it
+// is running and not in maintenance.
+func (w *worker) isSequencing() bool {
+	return w.isRunning() && !w.inMaintenance()
+}
+
+// setMaintenance enters or leaves maintenance. Entering it stops the block being
+// built and seals it with the transactions included so far, after which no
+// block is built until maintenance is left. It returns once the block is sealed.
+func (w *worker) setMaintenance(enable bool) error {
+	req := &maintenanceReq{enable: enable, done: make(chan struct{})}
+	select {
+	case w.maintenanceCh <- req:
+	case <-w.exitCh:
+		return errWorkerClosed
+	}
+	select {
+	case <-req.done:
+		return nil
+	case <-w.exitCh:
+		return errWorkerClosed
+	}
+}
+
+// handleMaintenance serves a maintenance request in the main loop.
+func (w *worker) handleMaintenance(req *maintenanceReq) {
+	defer close(req.done)
+
+	if !req.enable {
+		if atomic.CompareAndSwapInt32(&w.maintenance, 1, 0) {
+			log.Info("Leaving sequencer maintenance")
+			w.eth.TxPool().SetMaintenance(false)
+			w.startNewPipeline(time.Now().Unix())
+		}
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&w.maintenance, 0, 1) {
+		return
+	}
+	log.Info("Entering sequencer maintenance")
+	w.eth.TxPool().SetMaintenance(true)
+
+	// Drain the block being built, it is sealed as usual
+	if w.currentPipeline != nil && w.isSequencingPipeline() {
+		w.draining = true
+		w.currentPipeline.Stop()
+		if res := <-w.currentPipeline.ResultCh; res != nil {
+			w.handlePipelineResult(res)
+		}
+		w.draining = false
+	}
+	if w.currentPipeline == nil {
+		// Keep serving the pending block
+		w.startNewPipeline(time.Now().Unix())
+	}
+}
+
+// setPipelineStatus records the block being built, nil if none.
+func (w *worker) setPipelineStatus(status *PipelineStatus) {
+	w.statusMu.Lock()
+	defer w.statusMu.Unlock()
+
+	w.pipelineStatus = status
+}
+
+// isSequencingPipeline returns whether the block being built is to be sealed.
+func (w *worker) isSequencingPipeline() bool {
+	w.statusMu.RLock()
+	defer w.statusMu.RUnlock()
+
+	return w.pipelineStatus != nil && w.pipelineStatus.Sequencing
+}
+
+// isHeartbeatPipeline returns whether the block being built is closed right
+// away to keep up the heartbeat.
+func (w *worker) isHeartbeatPipeline() bool {
+	w.statusMu.RLock()
+	defer w.statusMu.RUnlock()
+
+	return w.pipelineStatus != nil && w.pipelineStatus.Heartbeat
+}
+
+// closePipelineStatus records that the block being built was closed for the
+// given reason, if it was built to be sealed.
+func (w *worker) closePipelineStatus(reason string) {
+	w.statusMu.Lock()
+	defer w.statusMu.Unlock()
+
+	if w.pipelineStatus != nil && w.pipelineStatus.Sequencing {
+		w.lastCloseReason, w.lastClosedAt = reason, time.Now()
+	}
+	w.pipelineStatus = nil
+}
+
+// setCommitError records the error of the last commit, nil if it succeeded.
+func (w *worker) setCommitError(err error) {
+	w.statusMu.Lock()
+	defer w.statusMu.Unlock()
+
+	w.lastCommitError = err
+}
+
+// status returns the block production status of the worker.
+func (w *worker) status() Status {
+	w.statusMu.RLock()
+	defer w.statusMu.RUnlock()
+
+	status := Status{
+		Running:         w.isRunning(),
+		Maintenance:     w.inMaintenance(),
+		LastCloseReason: w.lastCloseReason,
+	}
+	if w.pipelineStatus != nil {
+		pipeline := *w.pipelineStatus
+		status.Pipeline = &pipeline
+	}
+	if !w.lastClosedAt.IsZero() {
+		status.LastClosedAt = uint64(w.lastClosedAt.UnixMilli())
+	}
+	if w.lastCommitError != nil {
+		status.LastCommitError = w.lastCommitError.Error()
+	}
+	return status
+}
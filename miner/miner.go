@@ -18,8 +18,10 @@
 package miner
 
 import (
+	"context"
 	"fmt"
 	"math/big"
+	"os"
 	"sync"
 	"time"
 
@@ -34,6 +36,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/event"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/preconf"
 	"github.com/scroll-tech/go-ethereum/rollup/sync_service"
 )
 
@@ -59,6 +62,18 @@ type Config struct {
 
 	StoreSkippedTxTraces bool // Whether store the wrapped traces when storing a skipped tx
 	MaxAccountsNum       int  // Maximum number of accounts that miner will fetch the pending transactions of when building a new block
+
+	HALease        string        `toml:",omitempty"` // Location of the lease shared by highly available sequencers (empty = no HA)
+	HALeaseHolder  string        `toml:",omitempty"` // Name of this sequencer in the lease (default = hostname)
+	HALeaseTimeout time.Duration `toml:",omitempty"` // Duration of the lease, after which a standby sequencer takes over
+	HALeaseServe   string        `toml:",omitempty"` // Path of a lease file to serve to remote sequencers over RPC (empty = don't serve)
+
+	Signer        string        `toml:",omitempty"` // Endpoint of the external signer sealing blocks (empty = local account)
+	SignerTimeout time.Duration `toml:",omitempty"` // Maximum time to wait for the signature of a block
+
+	Preconfirmations bool `toml:",omitempty"` // Whether to publish signed preconfirmations of the transactions in the block being built
+
+	HeartbeatInterval time.Duration `toml:",omitempty"` // Maximum time between blocks, after which a possibly empty block is sealed (0 = no empty blocks)
 }
 
 // Miner creates blocks and searches for proof-of-work values.
@@ -68,6 +83,7 @@ type Miner struct {
 	coinbase common.Address
 	eth      Backend
 	engine   consensus.Engine
+	lease    *sequencerLease // Leadership among highly available sequencers, nil if not HA
 	exitCh   chan struct{}
 	startCh  chan common.Address
 	stopCh   chan struct{}
@@ -75,7 +91,9 @@ type Miner struct {
 	wg sync.WaitGroup
 }
 
-func New(eth Backend, config *Config, chainConfig *params.ChainConfig, mux *event.TypeMux, engine consensus.Engine, isLocalBlock func(block *types.Block) bool) *Miner {
+// New creates a miner. If a lease is given, the miner only produces blocks
+// while it holds the lease, so that a standby sequencer can take over.
+func New(eth Backend, config *Config, chainConfig *params.ChainConfig, mux *event.TypeMux, engine consensus.Engine, isLocalBlock func(block *types.Block) bool, lease Lease) *Miner {
 	miner := &Miner{
 		eth:     eth,
 		mux:     mux,
@@ -85,6 +103,17 @@ func New(eth Backend, config *Config, chainConfig *params.ChainConfig, mux *even
 		stopCh:  make(chan struct{}),
 		worker:  newWorker(config, chainConfig, engine, eth, mux, isLocalBlock, true),
 	}
+	if lease != nil {
+		miner.lease = &sequencerLease{backend: lease, holder: config.HALeaseHolder, timeout: config.HALeaseTimeout}
+		if miner.lease.timeout <= 0 {
+			log.Warn("Sanitizing invalid sequencer lease timeout", "provided", config.HALeaseTimeout, "updated", DefaultLeaseTimeout)
+			miner.lease.timeout = DefaultLeaseTimeout
+		}
+		if miner.lease.holder == "" {
+			miner.lease.holder, _ = os.Hostname()
+		}
+		miner.worker.lease = miner.lease
+	}
 	miner.wg.Add(1)
 	go miner.update()
 	return miner
@@ -107,6 +136,16 @@ func (miner *Miner) update() {
 	shouldStart := false
 	canStart := true
 	dlEventCh := events.Chan()
+
+	// Without a lease the miner always leads, otherwise it campaigns for the
+	// lease whenever it would mine.
+	leader := miner.lease == nil
+	var renew <-chan time.Time
+	if miner.lease != nil {
+		ticker := time.NewTicker(miner.lease.timeout / 3)
+		defer ticker.Stop()
+		renew = ticker.C
+	}
 	for {
 		select {
 		case ev := <-dlEventCh:
@@ -127,13 +166,13 @@ func (miner *Miner) update() {
 				}
 			case downloader.FailedEvent:
 				canStart = true
-				if shouldStart {
+				if shouldStart && leader {
 					miner.SetEtherbase(miner.coinbase)
 					miner.worker.start()
 				}
 			case downloader.DoneEvent:
 				canStart = true
-				if shouldStart {
+				if shouldStart && leader {
 					miner.SetEtherbase(miner.coinbase)
 					miner.worker.start()
 				}
@@ -142,15 +181,33 @@ func (miner *Miner) update() {
 			}
 		case addr := <-miner.startCh:
 			miner.SetEtherbase(addr)
-			if canStart {
+			if canStart && leader {
 				miner.worker.start()
 			}
 			shouldStart = true
 		case <-miner.stopCh:
 			shouldStart = false
 			miner.worker.stop()
+		case <-renew:
+			wasLeader := leader
+			leader = miner.lease.renew(canStart && shouldStart)
+			if leader && !wasLeader {
+				miner.SetEtherbase(miner.coinbase)
+				miner.worker.start()
+			} else if !leader && wasLeader {
+				miner.worker.stop()
+			}
 		case <-miner.exitCh:
 			miner.worker.close()
+			if miner.lease != nil {
+				// Hand over to a standby sequencer without waiting for the expiry
+				if !miner.lease.expiry.IsZero() {
+					ctx, cancel := context.WithTimeout(context.Background(), miner.lease.timeout/3)
+					miner.lease.release(ctx)
+					cancel()
+				}
+				miner.lease.backend.Close()
+			}
 			return
 		}
 	}
@@ -169,10 +226,33 @@ func (miner *Miner) Close() {
 	miner.wg.Wait()
 }
 
+// Preconfirmations returns the publisher of transaction preconfirmations, or
+// nil if preconfirmations are disabled.
+func (miner *Miner) Preconfirmations() *preconf.Publisher {
+	return miner.worker.preconfs
+}
+
 func (miner *Miner) Mining() bool {
 	return miner.worker.isRunning()
 }
 
+// SetMaintenance enters or leaves sequencer maintenance. Entering it seals the
+// block being built with the transactions included so far and pauses block
+// production until maintenance is left, without stopping the miner.
+func (miner *Miner) SetMaintenance(enable bool) error {
+	return miner.worker.setMaintenance(enable)
+}
+
+// InMaintenance returns whether the sequencer is in maintenance.
+func (miner *Miner) InMaintenance() bool {
+	return miner.worker.inMaintenance()
+}
+
+// Status returns the block production status of the miner.
+func (miner *Miner) Status() Status {
+	return miner.worker.status()
+}
+
 func (miner *Miner) Hashrate() uint64 {
 	if pow, ok := miner.engine.(consensus.PoW); ok {
 		return uint64(pow.Hashrate())
@@ -240,3 +320,10 @@ func (miner *Miner) DisablePreseal() {
 func (miner *Miner) SubscribePendingLogs(ch chan<- []*types.Log) event.Subscription {
 	return miner.worker.pendingLogsFeed.Subscribe(ch)
 }
+
+// SubscribePendingBlock starts delivering the transactions appended to the block
+// being built by the sequencer, followed by the sealing or abortion of the block.
+// Subscriptions only take effect from the next block.
+func (miner *Miner) SubscribePendingBlock(ch chan<- core.PendingBlockEvent) event.Subscription {
+	return miner.worker.pendingBlocks.Subscribe(ch)
+}
@@ -243,10 +243,16 @@ func waitForMiningState(t *testing.T, m *Miner, mining bool) {
 }
 
 func createMiner(t *testing.T) (*Miner, *event.TypeMux) {
+	return createMinerWithLease(t, nil, "", 0)
+}
+
+func createMinerWithLease(t *testing.T, lease Lease, holder string, timeout time.Duration) (*Miner, *event.TypeMux) {
 	// Create Ethash config
 	config := Config{
 		Etherbase:      common.HexToAddress("123456789"),
 		MaxAccountsNum: math.MaxInt,
+		HALeaseHolder:  holder,
+		HALeaseTimeout: timeout,
 	}
 	// Create chainConfig
 	memdb := memorydb.New()
@@ -271,5 +277,5 @@ func createMiner(t *testing.T) (*Miner, *event.TypeMux) {
 	// Create event Mux
 	mux := new(event.TypeMux)
 	// Create Miner
-	return New(backend, &config, chainConfig, mux, engine, nil), mux
+	return New(backend, &config, chainConfig, mux, engine, nil, lease), mux
 }
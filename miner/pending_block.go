@@ -0,0 +1,100 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"sync"
+
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/event"
+	"github.com/scroll-tech/go-ethereum/metrics"
+)
+
+// pendingBlockQueueSize is the number of pending block events waiting for slow
+// subscribers before the oldest ones are dropped.
+const pendingBlockQueueSize = 1024
+
+var pendingBlockDroppedMeter = metrics.NewRegisteredMeter("miner/pending/dropped", nil)
+
+// pendingBlockStream delivers the events of the block being built in the
+// background, so that slow subscribers never delay the pipeline.
+type pendingBlockStream struct {
+	feed  event.Feed
+	scope event.SubscriptionScope
+
+	queue chan core.PendingBlockEvent
+	quit  chan struct{}
+	wg    sync.WaitGroup
+}
+
+func newPendingBlockStream() *pendingBlockStream {
+	s := &pendingBlockStream{
+		queue: make(chan core.PendingBlockEvent, pendingBlockQueueSize),
+		quit:  make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.loop()
+	return s
+}
+
+// Subscribe registers a subscription for the pending block events.
+func (s *pendingBlockStream) Subscribe(ch chan<- core.PendingBlockEvent) event.Subscription {
+	return s.scope.Track(s.feed.Subscribe(ch))
+}
+
+// count returns the number of subscribers.
+func (s *pendingBlockStream) count() int {
+	return s.scope.Count()
+}
+
+// send queues an event without blocking. If subscribers fall behind, the oldest
+// queued events are dropped to make room, as they are the most outdated ones.
+func (s *pendingBlockStream) send(ev core.PendingBlockEvent) {
+	for {
+		select {
+		case s.queue <- ev:
+			return
+		default:
+		}
+		select {
+		case <-s.queue:
+			pendingBlockDroppedMeter.Mark(1)
+		default:
+		}
+	}
+}
+
+// close stops delivering events and ends all subscriptions. Ending them first
+// releases the delivery of an event to a stuck subscriber.
+func (s *pendingBlockStream) close() {
+	close(s.quit)
+	s.scope.Close()
+	s.wg.Wait()
+}
+
+func (s *pendingBlockStream) loop() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case ev := <-s.queue:
+			s.feed.Send(ev)
+		case <-s.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/core"
+)
+
+// Tests that a stuck subscriber never blocks the sender of pending block events,
+// and that the oldest events are dropped in favour of the latest ones.
+func TestPendingBlockStreamSlowSubscriber(t *testing.T) {
+	s := newPendingBlockStream()
+
+	events := make(chan core.PendingBlockEvent)
+	sub := s.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 2*pendingBlockQueueSize; i++ {
+			s.send(core.PendingBlockEvent{Index: i})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("sending blocked on a stuck subscriber")
+	}
+	// Drain the events, the last one sent must be delivered last
+	var last int
+	for {
+		select {
+		case ev := <-events:
+			last = ev.Index
+			continue
+		case <-time.After(100 * time.Millisecond):
+		}
+		break
+	}
+	if last != 2*pendingBlockQueueSize-1 {
+		t.Fatalf("last event mismatch: have %d, want %d", last, 2*pendingBlockQueueSize-1)
+	}
+	// Closing the stream releases a stuck delivery
+	s.send(core.PendingBlockEvent{})
+	s.send(core.PendingBlockEvent{})
+	time.Sleep(50 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		s.close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatalf("closing blocked on a stuck subscriber")
+	}
+}
@@ -19,6 +19,7 @@ package miner
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"sync"
@@ -26,6 +27,7 @@ import (
 	"time"
 
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/consensus"
 	"github.com/scroll-tech/go-ethereum/consensus/misc"
 	"github.com/scroll-tech/go-ethereum/core"
@@ -39,6 +41,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/rollup/ccc"
 	"github.com/scroll-tech/go-ethereum/rollup/fees"
 	"github.com/scroll-tech/go-ethereum/rollup/pipeline"
+	"github.com/scroll-tech/go-ethereum/rollup/preconf"
 	"github.com/scroll-tech/go-ethereum/trie"
 )
 
@@ -49,6 +52,10 @@ const (
 
 	// chainHeadChanSize is the size of channel listening to ChainHeadEvent.
 	chainHeadChanSize = 10
+
+	// sealRetryDelay is the delay before sealing a block again after the block
+	// signer failed to seal it.
+	sealRetryDelay = time.Second
 )
 
 var (
@@ -68,6 +75,7 @@ var (
 	commitReasonCCCCounter      = metrics.NewRegisteredCounter("miner/commit_reason_ccc", nil)
 	commitReasonDeadlineCounter = metrics.NewRegisteredCounter("miner/commit_reason_deadline", nil)
 	commitGasCounter            = metrics.NewRegisteredCounter("miner/commit_gas", nil)
+	heartbeatCounter            = metrics.NewRegisteredCounter("miner/heartbeat", nil)
 )
 
 // prioritizedTransaction represents a single transaction that
@@ -77,6 +85,12 @@ type prioritizedTransaction struct {
 	tx          *types.Transaction
 }
 
+// unsealedBlock is an assembled block whose sealing failed for lack of signature.
+type unsealedBlock struct {
+	res   *pipeline.Result
+	block *types.Block
+}
+
 // worker is the main object which takes care of submitting new work to consensus engine
 // and gathering the sealing result.
 type worker struct {
@@ -88,6 +102,7 @@ type worker struct {
 
 	// Feeds
 	pendingLogsFeed event.Feed
+	pendingBlocks   *pendingBlockStream
 
 	// Subscriptions
 	mux          *event.TypeMux
@@ -97,26 +112,39 @@ type worker struct {
 	chainHeadSub event.Subscription
 
 	// Channels
-	startCh chan struct{}
-	exitCh  chan struct{}
+	startCh       chan struct{}
+	maintenanceCh chan *maintenanceReq
+	exitCh        chan struct{}
 
 	wg sync.WaitGroup
 
 	currentPipelineStart time.Time
 	currentPipeline      *pipeline.Pipeline
+	streamingPending     bool // Whether the current pipeline is streamed to pending block subscribers
+	draining             bool // Whether the current pipeline is being drained to enter maintenance
+	heartbeat            bool // Whether the next pipeline is closed right away to keep up the heartbeat
+	heartbeatTimer       *time.Timer
+	unsealed             *unsealedBlock // Block the signer failed to seal, sealed again on retry
 
 	mu       sync.RWMutex // The lock used to protect the coinbase and extra fields
 	coinbase common.Address
 	extra    []byte
 
+	statusMu        sync.RWMutex    // The lock used to protect the status fields below
+	pipelineStatus  *PipelineStatus // Block being built, nil if none
+	lastCloseReason string          // Reason the last block built to be sealed was closed for
+	lastClosedAt    time.Time
+	lastCommitError error
+
 	snapshotMu       sync.RWMutex // The lock used to protect the snapshots below
 	snapshotBlock    *types.Block
 	snapshotReceipts types.Receipts
 	snapshotState    *state.StateDB
 
 	// atomic status counters
-	running int32 // The indicator whether the consensus engine is running or not.
-	newTxs  int32 // New arrival transaction count since last sealing work submitting.
+	running     int32 // The indicator whether the consensus engine is running or not.
+	maintenance int32 // The indicator whether the sequencer is in maintenance or not.
+	newTxs      int32 // New arrival transaction count since last sealing work submitting.
 
 	// noempty is the flag used to control whether the feature of pre-seal empty
 	// block is enabled. The default value is false(pre-seal is enabled by default).
@@ -130,6 +158,8 @@ type worker struct {
 
 	circuitCapacityChecker *ccc.Checker
 	prioritizedTx          *prioritizedTransaction
+	lease                  *sequencerLease    // Lease under which blocks are claimed, nil if not HA
+	preconfs               *preconf.Publisher // Publisher of transaction preconfirmations, nil if disabled
 
 	// Test hooks
 	beforeTxHook func() // Method to call before processing a transaction.
@@ -148,10 +178,16 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 		chainHeadCh:            make(chan core.ChainHeadEvent, chainHeadChanSize),
 		exitCh:                 make(chan struct{}),
 		startCh:                make(chan struct{}, 1),
+		maintenanceCh:          make(chan *maintenanceReq),
 		circuitCapacityChecker: ccc.NewChecker(true),
+		pendingBlocks:          newPendingBlockStream(),
 	}
 	log.Info("created new worker", "CircuitCapacityChecker ID", worker.circuitCapacityChecker.ID)
 
+	if config.Preconfirmations {
+		worker.preconfs = preconf.NewPublisher()
+	}
+
 	// Subscribe NewTxsEvent for tx pool
 	worker.txsSub = eth.TxPool().SubscribeNewTxsEvent(worker.txsCh)
 
@@ -248,6 +284,18 @@ func (w *worker) stop() {
 	atomic.StoreInt32(&w.running, 0)
 }
 
+// retry triggers building a new block if the worker is running and no other
+// request is pending.
+func (w *worker) retry() {
+	if !w.isSequencing() {
+		return
+	}
+	select {
+	case w.startCh <- struct{}{}:
+	default:
+	}
+}
+
 // isRunning returns an indicator whether worker is running or not.
 func (w *worker) isRunning() bool {
 	return atomic.LoadInt32(&w.running) == 1
@@ -259,6 +307,10 @@ func (w *worker) close() {
 	atomic.StoreInt32(&w.running, 0)
 	close(w.exitCh)
 	w.wg.Wait()
+	if w.preconfs != nil {
+		w.preconfs.Close()
+	}
+	w.pendingBlocks.close()
 }
 
 // mainLoop is a standalone goroutine to regenerate the sealing task based on the received event.
@@ -274,6 +326,13 @@ func (w *worker) mainLoop() {
 		}
 		return w.currentPipeline.ResultCh
 	}
+	heartbeatCh := func() <-chan time.Time {
+		if w.heartbeatTimer == nil {
+			return nil
+		}
+		return w.heartbeatTimer.C
+	}
+	defer w.stopHeartbeat()
 
 	for {
 		select {
@@ -283,6 +342,10 @@ func (w *worker) mainLoop() {
 			w.startNewPipeline(time.Now().Unix())
 		case result := <-pipelineResultCh():
 			w.handlePipelineResult(result)
+		case req := <-w.maintenanceCh:
+			w.handleMaintenance(req)
+		case <-heartbeatCh():
+			w.onHeartbeat()
 		case ev := <-w.txsCh:
 			// Apply transactions to the pending state
 			//
@@ -338,14 +401,36 @@ func (w *worker) collectPendingL1Messages(startIndex uint64) []types.L1MessageTx
 
 // startNewPipeline generates several new sealing tasks based on the parent block.
 func (w *worker) startNewPipeline(timestamp int64) {
+	heartbeat := w.heartbeat
+	w.heartbeat = false
+	w.stopHeartbeat()
 
 	if w.currentPipeline != nil {
 		w.currentPipeline.Release()
+		w.endPendingBlock(&w.currentPipeline.Header, common.Hash{})
+		w.closePipelineStatus(CloseReasonAborted)
 		w.currentPipeline = nil
 	}
 
 	parent := w.chain.CurrentBlock()
 
+	// Seal the block the signer failed to seal again rather than building a new
+	// one, since signers may refuse to sign two different blocks at the same height
+	if unsealed := w.unsealed; unsealed != nil {
+		w.unsealed = nil
+		if w.isSequencing() && unsealed.block.ParentHash() == parent.Hash() {
+			log.Info("Sealing block again", "number", unsealed.block.Number(), "sealhash", w.engine.SealHash(unsealed.block.Header()))
+			_, err := w.seal(unsealed.res, unsealed.block)
+			w.setCommitError(err)
+			if err == nil || w.unsealed != nil {
+				return
+			}
+			log.Error("Commit failed", "header", unsealed.block.Header(), "reason", err)
+		} else {
+			log.Info("Dropping unsealed block", "number", unsealed.block.Number(), "head", parent.Number())
+		}
+	}
+
 	num := parent.Number()
 	header := &types.Header{
 		ParentHash: parent.Hash(),
@@ -354,6 +439,10 @@ func (w *worker) startNewPipeline(timestamp int64) {
 		Extra:      w.extra,
 		Time:       uint64(timestamp),
 	}
+	// Reserve the start of the extra data for the L1 inclusion data committed to
+	if w.chainConfig.Scroll.IsL1MessageInclusion(header.Number) {
+		header.Extra = append(make([]byte, core.L1InclusionDataLength), w.extra...)
+	}
 	// Set baseFee if we are on an EIP-1559 chain
 	if w.chainConfig.IsCurie(header.Number) {
 		state, err := w.chain.StateAt(parent.Root())
@@ -365,7 +454,7 @@ func (w *worker) startNewPipeline(timestamp int64) {
 		header.BaseFee = misc.CalcBaseFee(w.chainConfig, parent.Header(), parentL1BaseFee)
 	}
 	// Only set the coinbase if our consensus engine is running (avoid spurious block rewards)
-	if w.isRunning() {
+	if w.isSequencing() {
 		if w.coinbase == (common.Address{}) {
 			log.Error("Refusing to mine without etherbase")
 			return
@@ -400,9 +489,15 @@ func (w *worker) startNewPipeline(timestamp int64) {
 		return
 	}
 
+	isFeynmanBlock := w.chainConfig.FeynmanBlock != nil && w.chainConfig.FeynmanBlock.Cmp(header.Number) == 0
+
 	// Apply special state transition at Curie block
 	if w.chainConfig.CurieBlock != nil && w.chainConfig.CurieBlock.Cmp(header.Number) == 0 {
 		misc.ApplyCurieHardFork(parentState)
+		// Feynman may be activated along with Curie
+		if isFeynmanBlock {
+			misc.ApplyFeynmanHardFork(parentState)
+		}
 
 		var nextL1MsgIndex uint64
 		if dbVal := rawdb.ReadFirstQueueIndexNotInL2Block(w.eth.ChainDb(), header.ParentHash); dbVal != nil {
@@ -432,6 +527,11 @@ func (w *worker) startNewPipeline(timestamp int64) {
 		return
 	}
 
+	// Apply special state transition at Feynman block
+	if isFeynmanBlock {
+		misc.ApplyFeynmanHardFork(parentState)
+	}
+
 	// fetch l1Txs
 	var l1Messages []types.L1MessageTx
 	if w.chainConfig.Scroll.ShouldIncludeL1Messages() {
@@ -466,10 +566,25 @@ func (w *worker) startNewPipeline(timestamp int64) {
 
 	w.currentPipelineStart = time.Now()
 	pipelineCCC := w.getCCC()
-	if !w.isRunning() {
+	if !w.isSequencing() {
 		pipelineCCC = nil
 	}
+	// Only compute state diffs while someone is following the pending block
+	w.streamingPending = w.isSequencing() && w.pendingBlocks.count() > 0
+	if w.streamingPending {
+		parentState.TrackTxDiffs()
+	}
 	w.currentPipeline = pipeline.NewPipeline(w.chain, *w.chain.GetVMConfig(), parentState, header, nextL1MsgIndex, pipelineCCC).WithBeforeTxHook(w.beforeTxHook)
+	heartbeat = heartbeat && pipelineCCC != nil
+	if heartbeat {
+		w.currentPipeline.WithEmptyBlocks()
+	}
+	if w.preconfs != nil && w.isSequencing() {
+		w.currentPipeline.WithPreconfirmHook(w.preconfs.Publish)
+	}
+	if w.streamingPending {
+		w.currentPipeline.WithAcceptedTxHook(w.streamPendingTx)
+	}
 
 	deadline := time.Unix(int64(header.Time), 0)
 	if w.chainConfig.Clique != nil && w.chainConfig.Clique.RelaxedPeriod {
@@ -481,11 +596,24 @@ func (w *worker) startNewPipeline(timestamp int64) {
 		log.Error("failed to start pipeline", "err", err)
 		return
 	}
+	w.setPipelineStatus(&PipelineStatus{
+		Number:     hexutil.Uint64(header.Number.Uint64()),
+		ParentHash: header.ParentHash,
+		Sequencing: pipelineCCC != nil,
+		Heartbeat:  heartbeat,
+		StartedAt:  uint64(w.currentPipelineStart.UnixMilli()),
+	})
+	if pipelineCCC != nil && !heartbeat && w.config.HeartbeatInterval > 0 {
+		w.heartbeatTimer = time.NewTimer(time.Until(time.Unix(int64(parent.Time()), 0).Add(w.config.HeartbeatInterval)))
+	}
 
 	// Short circuit if there is no available pending transactions.
 	// But if we disable empty precommit already, ignore it. Since
 	// empty block is necessary to keep the liveness of the network.
 	if len(localTxs) == 0 && len(remoteTxs) == 0 && len(l1Messages) == 0 && atomic.LoadUint32(&w.noempty) == 0 {
+		if heartbeat {
+			w.currentPipeline.Stop()
+		}
 		return
 	}
 
@@ -533,16 +661,51 @@ func (w *worker) startNewPipeline(timestamp int64) {
 	}
 
 	// pipelineCCC was nil, so the block was built for RPC purposes only. Stop the pipeline immediately
-	// and update the pending block.
-	if pipelineCCC == nil {
+	// and update the pending block. Heartbeat blocks are closed with whatever was pending.
+	if pipelineCCC == nil || heartbeat {
 		w.currentPipeline.Stop()
 	}
 }
 
+// onHeartbeat builds a block right away if none was sealed during the heartbeat
+// interval, so that block timestamps keep advancing in quiet periods. The block
+// includes the pending L1 messages and transactions, and is empty otherwise.
+func (w *worker) onHeartbeat() {
+	w.heartbeatTimer = nil
+	if w.currentPipeline == nil || !w.isSequencing() {
+		return
+	}
+	log.Debug("Building heartbeat block", "number", w.currentPipeline.Header.Number, "interval", w.config.HeartbeatInterval)
+	heartbeatCounter.Inc(1)
+
+	w.heartbeat = true
+	w.startNewPipeline(time.Now().Unix())
+}
+
+// stopHeartbeat disarms the heartbeat of the current pipeline.
+func (w *worker) stopHeartbeat() {
+	if w.heartbeatTimer != nil {
+		w.heartbeatTimer.Stop()
+		w.heartbeatTimer = nil
+	}
+}
+
 func (w *worker) handlePipelineResult(res *pipeline.Result) error {
 	startingHeader := w.currentPipeline.Header
 	w.currentPipeline.Release()
 	w.currentPipeline = nil
+	w.stopHeartbeat()
+
+	switch {
+	case res.CCCErr != nil:
+		w.closePipelineStatus(CloseReasonCircuitCapacity)
+	case w.draining:
+		w.closePipelineStatus(CloseReasonMaintenance)
+	case w.isHeartbeatPipeline():
+		w.closePipelineStatus(CloseReasonHeartbeat)
+	default:
+		w.closePipelineStatus(CloseReasonDeadline)
+	}
 
 	if res.FinalBlock != nil {
 		w.updateSnapshot(res.FinalBlock)
@@ -551,6 +714,11 @@ func (w *worker) handlePipelineResult(res *pipeline.Result) error {
 	// Rows being nil without an OverflowingTx means that block didn't go thru CCC,
 	// which means that we are not the sequencer. Do not attempt to commit.
 	if res.Rows == nil && res.OverflowingTx == nil {
+		if res.CCCErr != nil {
+			// An empty block failed the circuit capacity check, try again later
+			log.Error("Circuit capacity check failed for empty block", "number", startingHeader.Number, "err", res.CCCErr)
+			time.AfterFunc(sealRetryDelay, w.retry)
+		}
 		return nil
 	}
 
@@ -602,10 +770,15 @@ func (w *worker) handlePipelineResult(res *pipeline.Result) error {
 
 	var commitError error
 	if res.FinalBlock != nil {
-		if commitError = w.commit(res); commitError == nil {
+		commitError = w.commit(res)
+		w.setCommitError(commitError)
+		if commitError == nil {
 			return nil
 		}
 		log.Error("Commit failed", "header", res.FinalBlock.Header, "reason", commitError)
+	}
+	w.endPendingBlock(&startingHeader, common.Hash{})
+	if commitError != nil {
 		if _, isRetryable := commitError.(retryableCommitError); !isRetryable {
 			return commitError
 		}
@@ -630,7 +803,7 @@ func (e retryableCommitError) Unwrap() error {
 // commit runs any post-transaction state modifications, assembles the final block
 // and commits new work if consensus engine is running.
 func (w *worker) commit(res *pipeline.Result) error {
-	sealDelay := time.Duration(0)
+	var sealDelay time.Duration
 	defer func(t0 time.Time) {
 		l2CommitTimer.Update(time.Since(t0) - sealDelay)
 	}(time.Now())
@@ -642,35 +815,69 @@ func (w *worker) commit(res *pipeline.Result) error {
 	}
 	commitGasCounter.Inc(int64(res.FinalBlock.Header.GasUsed))
 
+	if w.chainConfig.Scroll.IsL1MessageInclusion(res.FinalBlock.Header.Number) {
+		w.commitL1InclusionHeight(res.FinalBlock.Header, res.FinalBlock.NextL1MsgIndex)
+	}
 	block, err := w.engine.FinalizeAndAssemble(w.chain, res.FinalBlock.Header, res.FinalBlock.State,
 		res.FinalBlock.Txs, nil, res.FinalBlock.Receipts)
 	if err != nil {
 		return err
 	}
+	// Never seal a block the other nodes would reject
+	if w.chainConfig.Scroll.IsL1MessageInclusion(block.Number()) {
+		if err := w.chain.VerifyL1Inclusion(block); err != nil {
+			return fmt.Errorf("block rejected by the L1 message inclusion rule: %w", err)
+		}
+	}
 
-	sealHash := w.engine.SealHash(block.Header())
-	log.Info("Committing new mining work", "number", block.Number(), "sealhash", sealHash,
+	log.Info("Committing new mining work", "number", block.Number(), "sealhash", w.engine.SealHash(block.Header()),
 		"txs", res.FinalBlock.Txs.Len(),
 		"gas", block.GasUsed(), "fees", totalFees(block, res.FinalBlock.Receipts),
 		"elapsed", common.PrettyDuration(time.Since(w.currentPipelineStart)))
 
+	sealDelay, err = w.seal(res, block)
+	return err
+}
+
+// seal seals an assembled block and commits it to the chain, returning the delay
+// introduced by the consensus engine. If the block signer is unavailable, the
+// block is kept to be sealed again after sealRetryDelay.
+func (w *worker) seal(res *pipeline.Result, block *types.Block) (time.Duration, error) {
+	sealHash := w.engine.SealHash(block.Header())
+
 	resultCh, stopCh := make(chan *types.Block), make(chan struct{})
 	if err := w.engine.Seal(w.chain, block, resultCh, stopCh); err != nil {
-		return err
+		if errors.Is(err, consensus.ErrSignerUnavailable) {
+			w.unsealed = &unsealedBlock{res: res, block: block}
+			time.AfterFunc(sealRetryDelay, w.retry)
+		}
+		return 0, err
 	}
 	// Clique.Seal() will only wait for a second before giving up on us. So make sure there is nothing computational heavy
 	// or a call that blocks between the call to Seal and the line below. Seal might introduce some delay, so we keep track of
 	// that artificially added delay and subtract it from overall runtime of commit().
 	sealStart := time.Now()
 	block = <-resultCh
-	sealDelay = time.Since(sealStart)
+	sealDelay := time.Since(sealStart)
 	if block == nil {
-		return errors.New("missed seal response from consensus engine")
+		return sealDelay, errors.New("missed seal response from consensus engine")
 	}
 
 	// verify the generated block with local consensus engine to make sure everything is as expected
-	if err = w.engine.VerifyHeader(w.chain, block.Header(), true); err != nil {
-		return retryableCommitError{inner: err}
+	if err := w.engine.VerifyHeader(w.chain, block.Header(), true); err != nil {
+		return sealDelay, retryableCommitError{inner: err}
+	}
+
+	// Claim the height under the sequencer lease, so that a standby sequencer
+	// taking over can never sign another block at the same height.
+	if w.lease != nil {
+		if err := w.lease.claim(block); err != nil {
+			// The height may have been claimed by a sequencer that stopped
+			// before publishing its block, publish it in its place
+			w.republishClaimed()
+			time.AfterFunc(leaseRetryDelay, w.retry)
+			return sealDelay, fmt.Errorf("failed to claim block under sequencer lease: %w", err)
+		}
 	}
 
 	blockHash := block.Hash()
@@ -728,17 +935,83 @@ func (w *worker) commit(res *pipeline.Result) error {
 
 	rawdb.WriteBlockRowConsumption(w.eth.ChainDb(), blockHash, res.Rows)
 	// Commit block and state to database.
-	_, err = w.chain.WriteBlockWithState(block, res.FinalBlock.Receipts, res.FinalBlock.CoalescedLogs, res.FinalBlock.State, true)
-	if err != nil {
-		return err
+	if _, err := w.chain.WriteBlockWithState(block, res.FinalBlock.Receipts, res.FinalBlock.CoalescedLogs, res.FinalBlock.State, true); err != nil {
+		return sealDelay, err
 	}
 
 	log.Info("Successfully sealed new block", "number", block.Number(), "sealhash", sealHash, "hash", blockHash)
+	w.endPendingBlock(block.Header(), blockHash)
 
 	// Broadcast the block and announce chain insertion event
 	w.mux.Post(core.NewMinedBlockEvent{Block: block})
 
-	return nil
+	return sealDelay, nil
+}
+
+// republishClaimed imports and broadcasts the last block claimed under the
+// sequencer lease, unless already in the local chain.
+func (w *worker) republishClaimed() {
+	block, err := w.lease.last()
+	if err != nil {
+		log.Warn("Failed to retrieve block claimed under sequencer lease", "err", err)
+		return
+	}
+	if block == nil || w.chain.HasBlock(block.Hash(), block.NumberU64()) {
+		return
+	}
+	if _, err := w.chain.InsertChain(types.Blocks{block}); err != nil {
+		log.Warn("Failed to import block claimed under sequencer lease", "number", block.Number(), "hash", block.Hash(), "err", err)
+		return
+	}
+	log.Info("Republished block claimed under sequencer lease", "number", block.Number(), "hash", block.Hash())
+	w.mux.Post(core.NewMinedBlockEvent{Block: block})
+}
+
+// commitL1InclusionHeight commits the header to the first L1 message queue index
+// left unprocessed by the block, skipped messages included, and to the highest L1
+// block height whose L1 messages are all processed by then.
+func (w *worker) commitL1InclusionHeight(header *types.Header, nextQueueIndex uint64) {
+	parent := w.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	var parentHeight uint64
+	if w.chainConfig.Scroll.IsL1MessageInclusion(parent.Number) {
+		parentHeight, _ = core.L1InclusionHeight(parent)
+	}
+	height := core.MaxL1InclusionHeight(w.eth.ChainDb(), parentHeight, nextQueueIndex)
+	core.SetL1Inclusion(header, height, nextQueueIndex)
+
+	synced := rawdb.ReadSyncedL1BlockNumber(w.eth.ChainDb())
+	if maxLag := w.chainConfig.Scroll.L1Config.L1MessageInclusionMaxLag; synced != nil && core.L1InclusionLagging(height, *synced, maxLag, header.Time, time.Now()) {
+		log.Warn("L1 inclusion height lagging behind L1", "number", header.Number, "height", height, "l1Head", *synced, "maxLag", maxLag)
+	}
+	log.Debug("Committing to L1 inclusion height", "number", header.Number, "height", height, "nextQueueIndex", nextQueueIndex)
+}
+
+// streamPendingTx queues the last transaction of the candidate, which is included
+// in the block being built, for pending block subscribers.
+func (w *worker) streamPendingTx(candidate *pipeline.BlockCandidate) {
+	index := candidate.Txs.Len() - 1
+	w.pendingBlocks.send(core.PendingBlockEvent{
+		Header:    candidate.Header,
+		Index:     index,
+		Tx:        candidate.Txs[index],
+		Receipt:   candidate.Receipts[index],
+		StateDiff: candidate.State.TxDiff(),
+	})
+}
+
+// endPendingBlock tells pending block subscribers that the block being built
+// was sealed with the given hash, or aborted if the hash is empty. It does
+// nothing if the block was not streamed or already ended.
+func (w *worker) endPendingBlock(header *types.Header, hash common.Hash) {
+	if !w.streamingPending {
+		return
+	}
+	w.streamingPending = false
+	w.pendingBlocks.send(core.PendingBlockEvent{
+		Header:  header,
+		Hash:    hash,
+		Aborted: hash == (common.Hash{}),
+	})
 }
 
 // copyReceipts makes a deep copy of the given receipts.
@@ -752,7 +1025,7 @@ func copyReceipts(receipts []*types.Receipt) []*types.Receipt {
 }
 
 func (w *worker) onTxFailingInPipeline(txIndex int, tx *types.Transaction, err error) bool {
-	if !w.isRunning() {
+	if !w.isSequencing() {
 		return false
 	}
 
@@ -783,6 +1056,10 @@ func (w *worker) onTxFailingInPipeline(txIndex int, tx *types.Transaction, err e
 		log.Trace("Skipping tx with insufficient funds", "tx", tx.Hash().String())
 		w.eth.TxPool().RemoveTx(tx.Hash(), true)
 
+	case errors.Is(err, core.ErrConditionNotMet):
+		log.Trace("Skipping conditional tx", "tx", tx.Hash().String(), "err", err)
+		w.eth.TxPool().RemoveTx(tx.Hash(), true)
+
 	case errors.Is(err, pipeline.ErrUnexpectedL1MessageIndex):
 		log.Warn(
 			"Unexpected L1 message queue index in worker",
@@ -17,9 +17,11 @@
 package miner
 
 import (
+	"errors"
 	"math"
 	"math/big"
 	"math/rand"
+	"sync"
 	"testing"
 	"time"
 
@@ -39,6 +41,8 @@ import (
 	"github.com/scroll-tech/go-ethereum/event"
 	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/rollup/ccc"
+	"github.com/scroll-tech/go-ethereum/rollup/preconf"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
 	"github.com/scroll-tech/go-ethereum/rollup/sync_service"
 )
 
@@ -79,6 +83,7 @@ var (
 func init() {
 	testTxPoolConfig = core.DefaultTxPoolConfig
 	testTxPoolConfig.Journal = ""
+	testTxPoolConfig.PolicyFile = ""
 	ethashChainConfig = new(params.ChainConfig)
 	*ethashChainConfig = *params.TestChainConfig
 	cliqueChainConfig = new(params.ChainConfig)
@@ -204,6 +209,16 @@ func (b *testWorkerBackend) newRandomTx(creation bool) *types.Transaction {
 	return tx
 }
 
+// newCliqueChainConfig returns a copy of the clique test chain config using the
+// given clique settings, with the fee vault enabled. The shared config is left
+// untouched, so that tests don't leak settings into each other.
+func newCliqueChainConfig(clique *params.CliqueConfig) *params.ChainConfig {
+	config := *params.AllCliqueProtocolChanges
+	config.Clique = clique
+	config.Scroll.FeeVaultAddress = &common.Address{}
+	return &config
+}
+
 func newTestWorker(t *testing.T, chainConfig *params.ChainConfig, engine consensus.Engine, db ethdb.Database, blocks int) (*worker, *testWorkerBackend) {
 	backend := newTestWorkerBackend(t, chainConfig, engine, db, blocks)
 	backend.txPool.AddLocals(pendingTxs)
@@ -775,6 +790,70 @@ func TestSkipAllL1MessagesInBlock(t *testing.T) {
 	})
 }
 
+// Tests that a block skipping the L1 messages at its end under the L1 message
+// inclusion rule commits to them, so that followers count them as processed.
+func TestL1InclusionSkippedMessages(t *testing.T) {
+	var (
+		db          = rawdb.NewMemoryDatabase()
+		chainConfig = newCliqueChainConfig(&params.CliqueConfig{Period: 1, Epoch: 30000})
+	)
+	chainConfig.Scroll.L1Config = &params.L1Config{NumL1MessagesPerBlock: 3, L1MessageInclusionBlock: new(uint64)}
+	engine := clique.New(chainConfig.Clique, db)
+
+	// messages are skipped because of `Value`
+	msgs := []types.L1MessageTx{
+		{QueueIndex: 0, Gas: 25100, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}, Value: big.NewInt(1)},
+		{QueueIndex: 1, Gas: 21016, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}, Value: big.NewInt(1)},
+	}
+	writeMsgs := func(db ethdb.Database) {
+		rawdb.WriteL1Messages(db, msgs)
+		rawdb.WriteL1MessageBlockNumber(db, 0, 10)
+		rawdb.WriteL1MessageBlockNumber(db, 1, 20)
+		rawdb.WriteSyncedL1BlockNumber(db, 30)
+	}
+	writeMsgs(db)
+
+	w, b := newTestWorker(t, chainConfig, engine, db, 0)
+	defer w.close()
+
+	sub := w.mux.Subscribe(core.NewMinedBlockEvent{})
+	defer sub.Unsubscribe()
+
+	b.txPool.AddLocal(b.newRandomTx(false))
+	w.start()
+
+	var block *types.Block
+	select {
+	case ev := <-sub.Chan():
+		block = ev.Data.(core.NewMinedBlockEvent).Block
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timeout")
+	}
+	if block.ContainsL1Messages() {
+		t.Fatalf("skipped messages included: %v", block.Transactions())
+	}
+	if index, err := core.L1InclusionQueueIndex(block.Header()); err != nil || index != 2 {
+		t.Fatalf("committed queue index mismatch: have %d (%v), want 2", index, err)
+	}
+	if height, err := core.L1InclusionHeight(block.Header()); err != nil || height != 30 {
+		t.Fatalf("committed height mismatch: have %d (%v), want 30", height, err)
+	}
+
+	// A follower knowing the same messages accepts the block
+	followerDb := rawdb.NewMemoryDatabase()
+	writeMsgs(followerDb)
+	b.genesis.MustCommit(followerDb)
+	follower, _ := core.NewBlockChain(followerDb, nil, chainConfig, clique.New(chainConfig.Clique, followerDb), vm.Config{}, nil, nil)
+	defer follower.Stop()
+
+	if _, err := follower.InsertChain(types.Blocks{block}); err != nil {
+		t.Fatalf("follower rejected block: %v", err)
+	}
+	if index := rawdb.ReadFirstQueueIndexNotInL2Block(followerDb, block.Hash()); index == nil || *index != 2 {
+		t.Fatalf("follower queue index mismatch: have %v, want 2", index)
+	}
+}
+
 func TestOversizedTxThenNormal(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1044,6 +1123,108 @@ func TestSealBlockAfterCliquePeriod(t *testing.T) {
 	}
 }
 
+// Tests that the sequencer applies both the Curie and the Feynman transitions when
+// they are activated in the same block, as followers do.
+func TestCurieFeynmanSameBlock(t *testing.T) {
+	var (
+		db          = rawdb.NewMemoryDatabase()
+		chainConfig = newCliqueChainConfig(&params.CliqueConfig{Period: 1, Epoch: 30000})
+	)
+	chainConfig.CurieBlock = big.NewInt(1)
+	chainConfig.FeynmanBlock = big.NewInt(1)
+	engine := clique.New(chainConfig.Clique, db)
+
+	w, b := newTestWorker(t, chainConfig, engine, db, 0)
+	defer w.close()
+
+	sub := w.mux.Subscribe(core.NewMinedBlockEvent{})
+	defer sub.Unsubscribe()
+
+	w.start()
+
+	var block *types.Block
+	select {
+	case ev := <-sub.Chan():
+		block = ev.Data.(core.NewMinedBlockEvent).Block
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timeout")
+	}
+	if block.NumberU64() != 1 {
+		t.Fatalf("block number mismatch: have %d, want 1", block.NumberU64())
+	}
+	statedb, err := w.chain.StateAt(block.Root())
+	if err != nil {
+		t.Fatalf("failed to retrieve state: %v", err)
+	}
+	for name, slot := range map[string]common.Hash{"Curie": rcfg.IsCurieSlot, "Feynman": rcfg.IsFeynmanSlot} {
+		if value := statedb.GetState(rcfg.L1GasPriceOracleAddress, slot); value != common.BytesToHash([]byte{1}) {
+			t.Fatalf("%s flag not set: %x", name, value)
+		}
+	}
+
+	// A follower reaches the same state root
+	followerDb := rawdb.NewMemoryDatabase()
+	b.genesis.MustCommit(followerDb)
+	follower, _ := core.NewBlockChain(followerDb, nil, chainConfig, clique.New(chainConfig.Clique, followerDb), vm.Config{}, nil, nil)
+	defer follower.Stop()
+
+	if _, err := follower.InsertChain(types.Blocks{block}); err != nil {
+		t.Fatalf("follower rejected block: %v", err)
+	}
+}
+
+// Tests that a block whose signature timed out is sealed again as is, so that the
+// signer is never asked to sign two different blocks at the same height.
+func TestSealRetryAfterSignerTimeout(t *testing.T) {
+	var (
+		db          = rawdb.NewMemoryDatabase()
+		chainConfig = newCliqueChainConfig(&params.CliqueConfig{Period: 1, Epoch: 30000})
+		engine      = clique.New(chainConfig.Clique, db)
+	)
+	w, b := newTestWorker(t, chainConfig, engine, db, 0)
+	defer w.close()
+
+	// The signer answers the first request only after the sign timeout
+	var (
+		lock     sync.Mutex
+		requests = make(map[common.Hash]int)
+	)
+	engine.SetSignTimeout(100 * time.Millisecond)
+	engine.Authorize(testBankAddress, func(account accounts.Account, s string, data []byte) ([]byte, error) {
+		lock.Lock()
+		first := len(requests) == 0
+		requests[crypto.Keccak256Hash(data)]++
+		lock.Unlock()
+
+		if first {
+			time.Sleep(300 * time.Millisecond)
+		}
+		return crypto.Sign(crypto.Keccak256(data), testBankKey)
+	})
+	sub := w.mux.Subscribe(core.NewMinedBlockEvent{})
+	defer sub.Unsubscribe()
+
+	b.txPool.AddLocal(b.newRandomTx(false))
+	w.start()
+
+	var block *types.Block
+	select {
+	case ev := <-sub.Chan():
+		block = ev.Data.(core.NewMinedBlockEvent).Block
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout")
+	}
+	if block.NumberU64() != 1 {
+		t.Fatalf("block number mismatch: have %d, want 1", block.NumberU64())
+	}
+	lock.Lock()
+	defer lock.Unlock()
+
+	if sealHash := clique.SealHash(block.Header()); len(requests) != 1 || requests[sealHash] != 1 {
+		t.Fatalf("signature requests mismatch: have %v, want one for %x", requests, sealHash)
+	}
+}
+
 func TestPending(t *testing.T) {
 	var (
 		engine      consensus.Engine
@@ -1087,3 +1268,240 @@ func TestPending(t *testing.T) {
 	assert.NotNil(t, pending)
 	assert.NotEmpty(t, pending.Transactions())
 }
+
+func TestPreconfirmations(t *testing.T) {
+	var (
+		db          = rawdb.NewMemoryDatabase()
+		chainConfig = newCliqueChainConfig(&params.CliqueConfig{Period: 1, Epoch: 30000})
+	)
+	engine := clique.New(chainConfig.Clique, db)
+
+	config := *testConfig
+	config.Preconfirmations = true
+	b := newTestWorkerBackend(t, chainConfig, engine, db, 0)
+	w := newWorker(&config, chainConfig, engine, b, new(event.TypeMux), nil, false)
+	w.setEtherbase(testBankAddress)
+	defer w.close()
+
+	w.preconfs.Authorize(testBankAddress, func(account accounts.Account, text []byte) ([]byte, error) {
+		return crypto.Sign(accounts.TextHash(text), testBankKey)
+	})
+	preconfs := make(chan *preconf.Preconfirmation, 16)
+	preconfSub := w.preconfs.Subscribe(preconfs)
+	defer preconfSub.Unsubscribe()
+
+	sub := w.mux.Subscribe(core.NewMinedBlockEvent{})
+	defer sub.Unsubscribe()
+
+	w.start()
+	b.txPool.AddLocal(b.newRandomTx(true))
+	b.txPool.AddLocal(b.newRandomTx(false))
+
+	var block *types.Block
+	select {
+	case ev := <-sub.Chan():
+		block = ev.Data.(core.NewMinedBlockEvent).Block
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timeout")
+	}
+	// Every transaction of the block is preconfirmed at its position
+	for i, tx := range block.Transactions() {
+		select {
+		case p := <-preconfs:
+			if p.TxHash != tx.Hash() || int(p.TransactionIndex) != i || uint64(p.BlockNumber) != block.NumberU64() || p.ParentHash != block.ParentHash() {
+				t.Fatalf("preconfirmation %d mismatch: %+v", i, p)
+			}
+			if signer, err := p.Signer(); err != nil || signer != testBankAddress {
+				t.Fatalf("preconfirmation %d signer mismatch: have %v, want %v, err %v", i, signer, testBankAddress, err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("transaction %d not preconfirmed", i)
+		}
+	}
+}
+
+func TestPendingBlockStream(t *testing.T) {
+	var (
+		db          = rawdb.NewMemoryDatabase()
+		chainConfig = newCliqueChainConfig(&params.CliqueConfig{Period: 1, Epoch: 30000})
+	)
+	engine := clique.New(chainConfig.Clique, db)
+
+	b := newTestWorkerBackend(t, chainConfig, engine, db, 0)
+	w := newWorker(testConfig, chainConfig, engine, b, new(event.TypeMux), nil, false)
+	w.setEtherbase(testBankAddress)
+	defer w.close()
+
+	events := make(chan core.PendingBlockEvent, 16)
+	pendingSub := w.pendingBlocks.Subscribe(events)
+	defer pendingSub.Unsubscribe()
+
+	sub := w.mux.Subscribe(core.NewMinedBlockEvent{})
+	defer sub.Unsubscribe()
+
+	w.start()
+	b.txPool.AddLocal(b.newRandomTx(true))
+	b.txPool.AddLocal(b.newRandomTx(false))
+
+	var block *types.Block
+	select {
+	case ev := <-sub.Chan():
+		block = ev.Data.(core.NewMinedBlockEvent).Block
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timeout")
+	}
+	// Every transaction of the block is streamed with the state of the sender,
+	// followed by the sealing of the block
+	for i, tx := range block.Transactions() {
+		select {
+		case ev := <-events:
+			if ev.Tx == nil || ev.Tx.Hash() != tx.Hash() || ev.Index != i || ev.Header.Number.Uint64() != block.NumberU64() {
+				t.Fatalf("pending transaction %d mismatch: %+v", i, ev)
+			}
+			if ev.Receipt == nil || ev.Receipt.TxHash != tx.Hash() {
+				t.Fatalf("pending transaction %d receipt mismatch: %+v", i, ev.Receipt)
+			}
+			sender := ev.StateDiff[testBankAddress]
+			if sender == nil || uint64(sender.Nonce) != tx.Nonce()+1 {
+				t.Fatalf("pending transaction %d sender diff mismatch: %+v", i, sender)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("transaction %d not streamed", i)
+		}
+	}
+	select {
+	case ev := <-events:
+		if ev.Tx != nil || ev.Aborted || ev.Hash != block.Hash() {
+			t.Fatalf("sealed event mismatch: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("sealing not streamed")
+	}
+}
+
+func TestSequencerMaintenance(t *testing.T) {
+	var (
+		db          = rawdb.NewMemoryDatabase()
+		chainConfig = newCliqueChainConfig(&params.CliqueConfig{Period: 2, Epoch: 30000, RelaxedPeriod: true})
+	)
+	engine := clique.New(chainConfig.Clique, db)
+
+	b := newTestWorkerBackend(t, chainConfig, engine, db, 0)
+	w := newWorker(testConfig, chainConfig, engine, b, new(event.TypeMux), nil, false)
+	w.setEtherbase(testBankAddress)
+	defer w.close()
+
+	sub := w.mux.Subscribe(core.NewMinedBlockEvent{})
+	defer sub.Unsubscribe()
+
+	w.start()
+	tx := b.newRandomTx(false)
+	b.txPool.AddLocal(tx)
+	time.Sleep(200 * time.Millisecond)
+
+	// Entering maintenance seals the block being built before its deadline
+	errc := make(chan error, 1)
+	go func() { errc <- w.setMaintenance(true) }()
+	select {
+	case ev := <-sub.Chan():
+		block := ev.Data.(core.NewMinedBlockEvent).Block
+		if len(block.Transactions()) != 1 || block.Transactions()[0].Hash() != tx.Hash() {
+			t.Fatalf("drained block mismatch: %v", block.Transactions())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("block not sealed on entering maintenance")
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("failed to enter maintenance: %v", err)
+	}
+	status := w.status()
+	if !status.Running || !status.Maintenance || status.LastCloseReason != CloseReasonMaintenance {
+		t.Fatalf("status mismatch: %+v", status)
+	}
+	if status.Pipeline != nil && status.Pipeline.Sequencing {
+		t.Fatalf("sequencing in maintenance: %+v", status.Pipeline)
+	}
+
+	// No transaction is queued and no block is produced in maintenance
+	tx = b.newRandomTx(false)
+	if err := b.txPool.AddLocal(tx); !errors.Is(err, core.ErrSequencerMaintenance) {
+		t.Fatalf("local transaction error mismatch: have %v, want %v", err, core.ErrSequencerMaintenance)
+	}
+	if errs := b.txPool.AddRemotes([]*types.Transaction{tx}); !errors.Is(errs[0], core.ErrSequencerMaintenance) {
+		t.Fatalf("remote transaction error mismatch: have %v, want %v", errs[0], core.ErrSequencerMaintenance)
+	}
+	select {
+	case <-sub.Chan():
+		t.Fatalf("block sealed in maintenance")
+	case <-time.After(3 * time.Second):
+	}
+
+	// Leaving maintenance resumes block production
+	if err := w.setMaintenance(false); err != nil {
+		t.Fatalf("failed to leave maintenance: %v", err)
+	}
+	if err := b.txPool.AddLocal(tx); err != nil {
+		t.Fatalf("failed to add transaction after maintenance: %v", err)
+	}
+	select {
+	case ev := <-sub.Chan():
+		if block := ev.Data.(core.NewMinedBlockEvent).Block; len(block.Transactions()) != 1 {
+			t.Fatalf("resumed block mismatch: %v", block.Transactions())
+		}
+	case <-time.After(4 * time.Second):
+		t.Fatalf("block production not resumed")
+	}
+	if status := w.status(); status.Maintenance || status.LastCloseReason != CloseReasonDeadline {
+		t.Fatalf("status mismatch: %+v", status)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	var (
+		db          = rawdb.NewMemoryDatabase()
+		chainConfig = newCliqueChainConfig(&params.CliqueConfig{Period: 1, Epoch: 30000})
+	)
+	chainConfig.Scroll.L1Config = &params.L1Config{NumL1MessagesPerBlock: 10}
+	engine := clique.New(chainConfig.Clique, db)
+
+	config := *testConfig
+	config.HeartbeatInterval = 2 * time.Second
+	b := newTestWorkerBackend(t, chainConfig, engine, db, 0)
+	w := newWorker(&config, chainConfig, engine, b, new(event.TypeMux), nil, false)
+	w.setEtherbase(testBankAddress)
+	defer w.close()
+
+	sub := w.mux.Subscribe(core.NewMinedBlockEvent{})
+	defer sub.Unsubscribe()
+
+	w.start()
+
+	// An empty block is sealed once the heartbeat interval elapsed
+	select {
+	case ev := <-sub.Chan():
+		block := ev.Data.(core.NewMinedBlockEvent).Block
+		if len(block.Transactions()) != 0 {
+			t.Fatalf("heartbeat block not empty: %v", block.Transactions())
+		}
+		if rawdb.ReadBlockRowConsumption(db, block.Hash()) == nil {
+			t.Fatalf("heartbeat block not checked against circuit capacity")
+		}
+	case <-time.After(4 * time.Second):
+		t.Fatalf("no heartbeat block")
+	}
+	if status := w.status(); status.LastCloseReason != CloseReasonHeartbeat {
+		t.Fatalf("close reason mismatch: have %q, want %q", status.LastCloseReason, CloseReasonHeartbeat)
+	}
+
+	// L1 messages synced in the meantime are included by the next heartbeat
+	rawdb.WriteL1Messages(db, []types.L1MessageTx{{QueueIndex: 0, Gas: 21016, To: &common.Address{3}, Data: []byte{0x01}, Sender: common.Address{4}}})
+	select {
+	case ev := <-sub.Chan():
+		block := ev.Data.(core.NewMinedBlockEvent).Block
+		if len(block.Transactions()) != 1 || !block.Transactions()[0].IsL1MessageTx() {
+			t.Fatalf("heartbeat block mismatch: %v", block.Transactions())
+		}
+	case <-time.After(4 * time.Second):
+		t.Fatalf("no heartbeat block")
+	}
+}
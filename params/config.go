@@ -285,6 +285,7 @@ var (
 		CurieBlock:          nil,
 		DarwinTime:          nil,
 		DarwinV2Time:        nil,
+		FeynmanBlock:        nil,
 		Clique: &CliqueConfig{
 			Period: 3,
 			Epoch:  30000,
@@ -325,6 +326,7 @@ var (
 		CurieBlock:          big.NewInt(4740239),
 		DarwinTime:          newUint64(1723622400),
 		DarwinV2Time:        newUint64(1724832000),
+		FeynmanBlock:        nil,
 		Clique: &CliqueConfig{
 			Period: 3,
 			Epoch:  30000,
@@ -365,6 +367,7 @@ var (
 		CurieBlock:          big.NewInt(7096836),
 		DarwinTime:          newUint64(1724227200),
 		DarwinV2Time:        newUint64(1725264000),
+		FeynmanBlock:        nil,
 		Clique: &CliqueConfig{
 			Period: 3,
 			Epoch:  30000,
@@ -633,6 +636,7 @@ type ChainConfig struct {
 	CurieBlock          *big.Int `json:"curieBlock,omitempty"`          // Curie switch block (nil = no fork, 0 = already on curie)
 	DarwinTime          *uint64  `json:"darwinTime,omitempty"`          // Darwin switch time (nil = no fork, 0 = already on darwin)
 	DarwinV2Time        *uint64  `json:"darwinv2Time,omitempty"`        // DarwinV2 switch time (nil = no fork, 0 = already on darwinv2)
+	FeynmanBlock        *big.Int `json:"feynmanBlock,omitempty"`        // Feynman switch block (nil = no fork, 0 = already on feynman)
 
 	// TerminalTotalDifficulty is the amount of total difficulty reached by
 	// the network that triggers the consensus upgrade.
@@ -669,6 +673,18 @@ type L1Config struct {
 	L1MessageQueueAddress common.Address `json:"l1MessageQueueAddress,omitempty"`
 	NumL1MessagesPerBlock uint64         `json:"numL1MessagesPerBlock,string,omitempty"`
 	ScrollChainAddress    common.Address `json:"scrollChainAddress,omitempty"`
+	SignerRegistryAddress common.Address `json:"signerRegistryAddress,omitempty"` // L1 contract announcing sequencer signer set updates (optional)
+
+	// L1MessageInclusionBlock enables the L1 message inclusion rule: from this
+	// block on, every header commits to an L1 block height and to the first L1
+	// message left unprocessed, and all the L1 messages up to that height must be
+	// included or skipped (nil = disabled).
+	L1MessageInclusionBlock *uint64 `json:"l1MessageInclusionBlock,omitempty"`
+
+	// L1MessageInclusionMaxLag is the number of L1 blocks the committed L1 block
+	// height may lag behind the L1 head seen by the sequencer before it warns
+	// (0 = never). It is not enforced on blocks, as the L1 head differs per node.
+	L1MessageInclusionMaxLag uint64 `json:"l1MessageInclusionMaxLag,omitempty"`
 }
 
 func (c *L1Config) String() string {
@@ -676,8 +692,12 @@ func (c *L1Config) String() string {
 		return "<nil>"
 	}
 
-	return fmt.Sprintf("{l1ChainId: %v, l1MessageQueueAddress: %v, numL1MessagesPerBlock: %v, ScrollChainAddress: %v}",
-		c.L1ChainId, c.L1MessageQueueAddress.Hex(), c.NumL1MessagesPerBlock, c.ScrollChainAddress.Hex())
+	inclusionBlock := "<nil>"
+	if c.L1MessageInclusionBlock != nil {
+		inclusionBlock = fmt.Sprintf("%d", *c.L1MessageInclusionBlock)
+	}
+	return fmt.Sprintf("{l1ChainId: %v, l1MessageQueueAddress: %v, numL1MessagesPerBlock: %v, ScrollChainAddress: %v, SignerRegistryAddress: %v, L1MessageInclusionBlock: %v, L1MessageInclusionMaxLag: %v}",
+		c.L1ChainId, c.L1MessageQueueAddress.Hex(), c.NumL1MessagesPerBlock, c.ScrollChainAddress.Hex(), c.SignerRegistryAddress.Hex(), inclusionBlock, c.L1MessageInclusionMaxLag)
 }
 
 func (s ScrollConfig) FeeVaultEnabled() bool {
@@ -692,6 +712,12 @@ func (s ScrollConfig) ShouldIncludeL1Messages() bool {
 	return s.L1Config != nil && s.L1Config.NumL1MessagesPerBlock > 0
 }
 
+// IsL1MessageInclusion returns whether the L1 message inclusion rule applies to
+// the given block.
+func (s ScrollConfig) IsL1MessageInclusion(num *big.Int) bool {
+	return s.L1Config != nil && s.L1Config.L1MessageInclusionBlock != nil && num.Uint64() >= *s.L1Config.L1MessageInclusionBlock
+}
+
 func (s ScrollConfig) String() string {
 	maxTxPerBlock := "<nil>"
 	if s.MaxTxPerBlock != nil {
@@ -733,6 +759,19 @@ type CliqueConfig struct {
 	RelaxedPeriod    bool           `json:"relaxed_period"`     // Relaxes the period to be just an upper bound
 	ShadowForkHeight uint64         `json:"shadow_fork_height"` // Allows shadow forking consensus layer at given height
 	ShadowForkSigner common.Address `json:"shadow_fork_signer"` // Sets the address to be the authorized signer after the shadow fork
+
+	// SignerRotationBlock enables the signer set updates announced by the L1
+	// signer registry for blocks from this height onwards. Updates replace the
+	// signer set at the L2 block they name, so the registry must announce them
+	// well ahead of it for all nodes to sync them from L1 in time. It requires
+	// the L1 message inclusion rule to be enabled by then.
+	SignerRotationBlock *uint64 `json:"signer_rotation_block,omitempty"`
+
+	// SignerRotationLead is the minimum number of blocks between the first L2
+	// block committing to the L1 block announcing a signer set update and the
+	// start block of the update. Late updates are ignored by all nodes. Zero
+	// means the default lead of the clique engine.
+	SignerRotationLead uint64 `json:"signer_rotation_lead,omitempty"`
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -759,7 +798,7 @@ func (c *ChainConfig) String() string {
 	if c.DarwinV2Time != nil {
 		darwinV2Time = fmt.Sprintf("@%v", *c.DarwinV2Time)
 	}
-	return fmt.Sprintf("{ChainID: %v Homestead: %v DAO: %v DAOSupport: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v, Muir Glacier: %v, Berlin: %v, London: %v, Arrow Glacier: %v, Archimedes: %v, Shanghai: %v, Bernoulli: %v, Curie: %v, Darwin: %v, DarwinV2: %v, Engine: %v, Scroll config: %v}",
+	return fmt.Sprintf("{ChainID: %v Homestead: %v DAO: %v DAOSupport: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v, Muir Glacier: %v, Berlin: %v, London: %v, Arrow Glacier: %v, Archimedes: %v, Shanghai: %v, Bernoulli: %v, Curie: %v, Darwin: %v, DarwinV2: %v, Feynman: %v, Engine: %v, Scroll config: %v}",
 		c.ChainID,
 		c.HomesteadBlock,
 		c.DAOForkBlock,
@@ -781,6 +820,7 @@ func (c *ChainConfig) String() string {
 		c.CurieBlock,
 		darwinTime,
 		darwinV2Time,
+		c.FeynmanBlock,
 		engine,
 		c.Scroll,
 	)
@@ -883,6 +923,11 @@ func (c *ChainConfig) IsDarwinV2(now uint64) bool {
 	return isForkedTime(now, c.DarwinV2Time)
 }
 
+// IsFeynman returns whether num is either equal to the Feynman fork block or greater.
+func (c *ChainConfig) IsFeynman(num *big.Int) bool {
+	return isForked(c.FeynmanBlock, num)
+}
+
 // IsTerminalPoWBlock returns whether the given block is the last block of PoW stage.
 func (c *ChainConfig) IsTerminalPoWBlock(parentTotalDiff *big.Int, totalDiff *big.Int) bool {
 	if c.TerminalTotalDifficulty == nil {
@@ -936,6 +981,7 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 		{name: "shanghaiBlock", block: c.ShanghaiBlock, optional: true},
 		{name: "bernoulliBlock", block: c.BernoulliBlock, optional: true},
 		{name: "curieBlock", block: c.CurieBlock, optional: true},
+		{name: "feynmanBlock", block: c.FeynmanBlock, optional: true},
 	} {
 		if lastFork.name != "" {
 			// Next one must be higher number
@@ -955,6 +1001,14 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	// Signer set updates only take effect once an L1 inclusion height commits to
+	// them, so signer rotation cannot be enabled before the inclusion rule
+	if c.Clique != nil && c.Clique.SignerRotationBlock != nil {
+		rotation := *c.Clique.SignerRotationBlock
+		if !c.Scroll.IsL1MessageInclusion(new(big.Int).SetUint64(rotation)) {
+			return fmt.Errorf("unsupported fork ordering: signer_rotation_block enabled at %v, but l1MessageInclusionBlock not enabled by then", rotation)
+		}
+	}
 	return nil
 }
 
@@ -1020,6 +1074,9 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.CurieBlock, newcfg.CurieBlock, head) {
 		return newCompatError("Curie fork block", c.CurieBlock, newcfg.CurieBlock)
 	}
+	if isForkIncompatible(c.FeynmanBlock, newcfg.FeynmanBlock, head) {
+		return newCompatError("Feynman fork block", c.FeynmanBlock, newcfg.FeynmanBlock)
+	}
 	return nil
 }
 
@@ -1095,7 +1152,7 @@ type Rules struct {
 	IsHomestead, IsEIP150, IsEIP155, IsEIP158               bool
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
 	IsBerlin, IsLondon, IsArchimedes, IsShanghai            bool
-	IsBernoulli, IsCurie, IsDarwin                          bool
+	IsBernoulli, IsCurie, IsDarwin, IsFeynman               bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -1121,5 +1178,6 @@ func (c *ChainConfig) Rules(num *big.Int, time uint64) Rules {
 		IsBernoulli:      c.IsBernoulli(num),
 		IsCurie:          c.IsCurie(num),
 		IsDarwin:         c.IsDarwin(time),
+		IsFeynman:        c.IsFeynman(num),
 	}
 }
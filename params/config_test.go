@@ -99,6 +99,23 @@ func TestCheckCompatible(t *testing.T) {
 	}
 }
 
+func TestCheckConfigForkOrderSignerRotation(t *testing.T) {
+	rotation, inclusion := uint64(10), uint64(10)
+	config := *TestChainConfig
+	config.Clique = &CliqueConfig{Period: 3, Epoch: 30000, SignerRotationBlock: &rotation}
+	if err := config.CheckConfigForkOrder(); err == nil {
+		t.Fatalf("signer rotation accepted without the L1 message inclusion rule")
+	}
+	config.Scroll.L1Config = &L1Config{L1MessageInclusionBlock: &inclusion}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		t.Fatalf("signer rotation rejected: %v", err)
+	}
+	inclusion = 11
+	if err := config.CheckConfigForkOrder(); err == nil {
+		t.Fatalf("signer rotation accepted before the L1 message inclusion rule")
+	}
+}
+
 func TestIsForkedTime(t *testing.T) {
 	timePtr := func(t uint64) *uint64 {
 		return &t
@@ -0,0 +1,127 @@
+package ccc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/consensus/misc"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+	"github.com/scroll-tech/go-ethereum/rollup/fees"
+	"github.com/scroll-tech/go-ethereum/rollup/tracing"
+)
+
+var txCheckTimer = metrics.NewRegisteredTimer("ccc/tx/check", nil)
+
+// Bounds used to estimate the circuit usage of a transaction without tracing it.
+// They are not read from libzkp, and must be revisited whenever the circuits it
+// links are upgraded:
+//   - txRowLimit is the row limit per sub-circuit applied by the circuit capacity
+//     checker, kept below the 2^20 rows of the circuits for the prover's margin.
+//   - keccakRowsPerBlock is the height of one keccak-f permutation in the keccak
+//     circuit, 25 rounds of 12 rows, each absorbing a 136 byte block.
+//   - maxRowsPerGas is not derived from the circuits but a conservative guess of
+//     the rows any sub-circuit uses per unit of gas. Overestimating it only makes
+//     the pool trace more transactions, underestimating it only skips tracing
+//     transactions the sequencer still checks when building blocks.
+const (
+	txRowLimit         = 1_000_000 // Row capacity of each sub-circuit
+	keccakRowsPerBlock = 300       // Rows used by the keccak circuit per 136 byte block of hashed data
+	maxRowsPerGas      = 32        // Assumed upper bound of the rows used by any sub-circuit per unit of gas
+)
+
+// TxChecker checks single transactions against the circuit capacity of an
+// empty block on top of the chain head. It implements core.CircuitChecker,
+// allowing the transaction pool to reject transactions that could never be
+// included in a block.
+type TxChecker struct {
+	bc       Blockchain
+	checkers chan *Checker
+}
+
+// NewTxChecker creates a TxChecker running up to numWorkers checks at once.
+// The checkers run in light mode.
+func NewTxChecker(bc Blockchain, numWorkers int) *TxChecker {
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+	checkers := make(chan *Checker, numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		checkers <- NewChecker(true)
+	}
+	return &TxChecker{bc: bc, checkers: checkers}
+}
+
+// EstimateTx bounds the circuit usage of the transaction from its size and gas
+// limit. The keccak rows hashing the transaction are a lower bound of its usage,
+// over which it surely overflows, and adding its gas limit at the highest row
+// cost per unit of gas gives an upper bound, under which it surely fits.
+func (c *TxChecker) EstimateTx(tx *types.Transaction) (bool, error) {
+	hashRows := (uint64(tx.Size())/136 + 1) * keccakRowsPerBlock
+	if hashRows > txRowLimit {
+		return false, fmt.Errorf("%w: %d keccak rows estimated", core.ErrCircuitCapacityOverflow, hashRows)
+	}
+	return tx.Gas() <= (txRowLimit-hashRows)/maxRowsPerGas, nil
+}
+
+// CheckTx traces the transaction as the only one of the next block and applies
+// the trace to an empty circuit capacity checker. The nonce of the sender is
+// set to the one of the transaction, so that queued transactions are checked
+// too. Transactions that cannot be traced are not rejected, as the pool
+// validates them on its own.
+func (c *TxChecker) CheckTx(tx *types.Transaction) error {
+	defer txCheckTimer.UpdateSince(time.Now())
+
+	head := c.bc.CurrentHeader()
+	parent := c.bc.GetBlock(head.Hash(), head.Number.Uint64())
+	if parent == nil {
+		return nil
+	}
+	statedb, err := c.bc.StateAt(parent.Root())
+	if err != nil {
+		log.Debug("State not available for circuit capacity check", "number", parent.Number(), "err", err)
+		return nil
+	}
+	config := c.bc.Config()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(head.Number, common.Big1),
+		GasLimit:   parent.GasLimit(),
+		Difficulty: parent.Difficulty(),
+		Time:       uint64(time.Now().Unix()),
+	}
+	if header.Time <= parent.Time() {
+		header.Time = parent.Time() + 1
+	}
+	if config.IsCurie(header.Number) {
+		header.BaseFee = misc.CalcBaseFee(config, parent.Header(), fees.GetL1BaseFee(statedb))
+	}
+	from, err := types.Sender(types.MakeSigner(config, header.Number), tx)
+	if err != nil {
+		return nil
+	}
+	statedb.SetNonce(from, tx.Nonce())
+
+	checker := <-c.checkers
+	defer func() { c.checkers <- checker }()
+	checker.Reset()
+
+	trace, err := tracing.NewTracerWrapper().CreateTraceEnvAndGetBlockTrace(config, c.bc, c.bc.Engine(), c.bc.Database(),
+		statedb, parent, types.NewBlockWithHeader(header).WithBody([]*types.Transaction{tx}, nil), false)
+	if err != nil {
+		log.Debug("Failed to trace transaction for circuit capacity check", "hash", tx.Hash(), "err", err)
+		return nil
+	}
+	if _, err := checker.ApplyTransaction(trace); err != nil {
+		if errors.Is(err, ErrBlockRowConsumptionOverflow) {
+			return fmt.Errorf("%w: %v", core.ErrCircuitCapacityOverflow, err)
+		}
+		log.Debug("Failed to check circuit capacity of transaction", "hash", tx.Hash(), "err", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package ccc
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/consensus/ethash"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/core/vm"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
+)
+
+func TestTxChecker(t *testing.T) {
+	testKey, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr := crypto.PubkeyToAddress(testKey.PublicKey)
+
+	db := rawdb.NewMemoryDatabase()
+	(&core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc:  core.GenesisAlloc{testAddr: {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))}},
+	}).MustCommit(db)
+	chain, _ := core.NewBlockChain(db, nil, params.TestChainConfig, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	txChecker := NewTxChecker(chain, 1)
+	signer := types.LatestSigner(params.TestChainConfig)
+	newTx := func(nonce uint64) *types.Transaction {
+		tx, err := types.SignTx(types.NewTransaction(nonce, testAddr, big.NewInt(1000), params.TxGas, big.NewInt(params.GWei), nil), signer, testKey)
+		require.NoError(t, err)
+		return tx
+	}
+
+	// Small transactions are estimated to fit, large ones need a check
+	fits, err := txChecker.EstimateTx(newTx(0))
+	require.NoError(t, err)
+	require.True(t, fits)
+	large, err := types.SignTx(types.NewTransaction(0, testAddr, big.NewInt(1000), 1_000_000, big.NewInt(params.GWei), nil), signer, testKey)
+	require.NoError(t, err)
+	fits, err = txChecker.EstimateTx(large)
+	require.NoError(t, err)
+	require.False(t, fits)
+
+	// Executable and queued transactions fitting in the circuits are accepted
+	require.NoError(t, txChecker.CheckTx(newTx(0)))
+	require.NoError(t, txChecker.CheckTx(newTx(5)))
+
+	// Transactions overflowing the circuits are rejected
+	overflowing := newTx(1)
+	checker := <-txChecker.checkers
+	checker.Skip(overflowing.Hash(), ErrBlockRowConsumptionOverflow)
+	txChecker.checkers <- checker
+	err = txChecker.CheckTx(overflowing)
+	require.True(t, errors.Is(err, core.ErrCircuitCapacityOverflow), "unexpected error: %v", err)
+
+	// Other checker failures do not reject the transaction
+	checker = <-txChecker.checkers
+	checker.ScheduleError(1, ErrUnknown)
+	txChecker.checkers <- checker
+	require.NoError(t, txChecker.CheckTx(newTx(2)))
+}
@@ -0,0 +1,120 @@
+package fees
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/params"
+)
+
+// FeeModel computes the L1 data fee of an RLP-encoded signed transaction from
+// the parameters of the L1GasPriceOracle contract. The results are not capped.
+type FeeModel interface {
+	// L1DataFee returns the L1 data fee of the encoded transaction data.
+	L1DataFee(data []byte, params *types.L1FeeParams) *big.Int
+
+	// L1DataFeeForSize returns an upper bound of the L1 data fee of any
+	// transaction with the given encoded size.
+	L1DataFeeForSize(size uint64, params *types.L1FeeParams) *big.Int
+}
+
+// FeeModelAt returns the fee model in effect at the given block.
+func FeeModelAt(config *params.ChainConfig, blockNumber *big.Int) FeeModel {
+	switch {
+	case config.IsFeynman(blockNumber):
+		return compressedFeeModel{}
+	case config.IsCurie(blockNumber):
+		return curieFeeModel{}
+	default:
+		return calldataFeeModel{}
+	}
+}
+
+// calldataFeeModel charges for the transaction as L1 calldata, as done before
+// Curie.
+type calldataFeeModel struct{}
+
+func (calldataFeeModel) L1DataFee(data []byte, p *types.L1FeeParams) *big.Int {
+	return calculateEncodedL1DataFee(data, p.Overhead, p.L1BaseFee, p.Scalar)
+}
+
+// L1DataFeeForSize charges all bytes as non-zero.
+func (calldataFeeModel) L1DataFeeForSize(size uint64, p *types.L1FeeParams) *big.Int {
+	return calculateL1DataFee(calculateL1GasUsedForCounts(0, size, p.Overhead), p.L1BaseFee, p.Scalar)
+}
+
+// curieFeeModel charges a fixed commit cost plus the uncompressed size of the
+// transaction in blob space.
+type curieFeeModel struct{}
+
+func (curieFeeModel) L1DataFee(data []byte, p *types.L1FeeParams) *big.Int {
+	return calculateEncodedL1DataFeeCurie(data, p.L1BaseFee, p.L1BlobBaseFee, p.CommitScalar, p.BlobScalar)
+}
+
+func (curieFeeModel) L1DataFeeForSize(size uint64, p *types.L1FeeParams) *big.Int {
+	return calculateL1DataFeeCurie(size, p.L1BaseFee, p.L1BlobBaseFee, p.CommitScalar, p.BlobScalar)
+}
+
+// compressedFeeModel is the Curie model applied to the estimated compressed
+// size of the transaction, since batches are compressed before being posted
+// in blobs.
+type compressedFeeModel struct{}
+
+func (compressedFeeModel) L1DataFee(data []byte, p *types.L1FeeParams) *big.Int {
+	return calculateL1DataFeeCurie(EstimateCompressedSize(data), p.L1BaseFee, p.L1BlobBaseFee, p.CommitScalar, p.BlobScalar)
+}
+
+// L1DataFeeForSize charges the data as incompressible, since the estimate of
+// the compressed size never exceeds the size of the data.
+func (compressedFeeModel) L1DataFeeForSize(size uint64, p *types.L1FeeParams) *big.Int {
+	return calculateL1DataFeeCurie(size, p.L1BaseFee, p.L1BlobBaseFee, p.CommitScalar, p.BlobScalar)
+}
+
+const (
+	compressionMinMatch  = 4       // Shortest repetition replaced by a back-reference
+	compressionWindow    = 1 << 16 // Maximum distance of a back-reference
+	compressionHashBits  = 12      // Size of the match finder table
+	compressionMatchCost = 3       // Estimated encoded size of a back-reference
+)
+
+// EstimateCompressedSize returns an estimate of the number of bytes the data
+// takes once compressed in a batch. It runs a greedy LZ77 match finder and
+// charges one byte per literal and a fixed cost per back-reference, ignoring
+// entropy coding. The estimate never exceeds the size of the data.
+//
+// The fee of a transaction is consensus critical, so the estimate is fully
+// specified here rather than delegated to a compression library whose output
+// may change between versions.
+func EstimateCompressedSize(data []byte) uint64 {
+	var (
+		table [1 << compressionHashBits]int32 // last position+1 of each hashed sequence
+		size  uint64
+	)
+	for i := 0; i < len(data); {
+		if i+compressionMinMatch <= len(data) {
+			h := compressionHash(data[i:])
+			candidate := int(table[h]) - 1
+			table[h] = int32(i + 1)
+			if candidate >= 0 && i-candidate <= compressionWindow && bytes.Equal(data[candidate:candidate+compressionMinMatch], data[i:i+compressionMinMatch]) {
+				n := compressionMinMatch
+				for i+n < len(data) && data[candidate+n] == data[i+n] {
+					n++
+				}
+				size += compressionMatchCost
+				i += n
+				continue
+			}
+		}
+		size++
+		i++
+	}
+	return size
+}
+
+// compressionHash hashes the first compressionMinMatch bytes of b into an
+// index of the match finder table.
+func compressionHash(b []byte) uint32 {
+	v := uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+	return (v * 2654435761) >> (32 - compressionHashBits)
+}
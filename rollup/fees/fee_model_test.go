@@ -0,0 +1,47 @@
+package fees
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/params"
+)
+
+func TestFeeModelAt(t *testing.T) {
+	config := &params.ChainConfig{CurieBlock: big.NewInt(10), FeynmanBlock: big.NewInt(20)}
+	assert.Equal(t, calldataFeeModel{}, FeeModelAt(config, big.NewInt(9)))
+	assert.Equal(t, curieFeeModel{}, FeeModelAt(config, big.NewInt(10)))
+	assert.Equal(t, curieFeeModel{}, FeeModelAt(config, big.NewInt(19)))
+	assert.Equal(t, compressedFeeModel{}, FeeModelAt(config, big.NewInt(20)))
+}
+
+func TestEstimateCompressedSize(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want uint64
+	}{
+		{nil, 0},
+		{[]byte{1, 2, 3, 4}, 4},
+		{make([]byte, 100), 4},              // 1 literal + 1 back-reference
+		{[]byte("abcdabcd"), 7},             // 4 literals + 1 back-reference
+		{[]byte("abcdefgh-abcdefgh-!"), 13}, // 9 literals + 1 back-reference + 1 literal
+	}
+	for i, tt := range tests {
+		assert.Equal(t, tt.want, EstimateCompressedSize(tt.data), "test %d", i)
+	}
+}
+
+func TestCompressedFeeModel(t *testing.T) {
+	params := &types.L1FeeParams{
+		L1BaseFee:     new(big.Int).SetUint64(1500000000),
+		L1BlobBaseFee: new(big.Int).SetUint64(150000000),
+		CommitScalar:  new(big.Int).SetUint64(10),
+		BlobScalar:    new(big.Int).SetUint64(10),
+	}
+	data := make([]byte, 100)
+	assert.Equal(t, big.NewInt(165), curieFeeModel{}.L1DataFee(data, params))
+	assert.Equal(t, big.NewInt(21), compressedFeeModel{}.L1DataFee(data, params))
+}
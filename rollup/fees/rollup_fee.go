@@ -52,6 +52,31 @@ type gpoState struct {
 	blobScalar    *big.Int
 }
 
+// ReadL1FeeParams reads the L1 data fee parameters from the L1GasPriceOracle
+// contract in the given state.
+func ReadL1FeeParams(state StateDB) *types.L1FeeParams {
+	gpoState := readGPOStorageSlots(rcfg.L1GasPriceOracleAddress, state)
+	return &types.L1FeeParams{
+		L1BaseFee:     gpoState.l1BaseFee,
+		Overhead:      gpoState.overhead,
+		Scalar:        gpoState.scalar,
+		L1BlobBaseFee: gpoState.l1BlobBaseFee,
+		CommitScalar:  gpoState.commitScalar,
+		BlobScalar:    gpoState.blobScalar,
+	}
+}
+
+// L1DataFeeForSize returns an upper bound of the L1 data fee of a signed
+// transaction with the given encoded size in the given block under the
+// parameters p, using the fee model in effect at the block.
+func L1DataFeeForSize(config *params.ChainConfig, blockNumber *big.Int, p *types.L1FeeParams, size uint64) *big.Int {
+	l1DataFee := FeeModelAt(config, blockNumber).L1DataFeeForSize(size, p)
+	if !l1DataFee.IsUint64() {
+		l1DataFee.SetUint64(math.MaxUint64)
+	}
+	return l1DataFee
+}
+
 func EstimateL1DataFeeForMessage(msg Message, baseFee *big.Int, config *params.ChainConfig, signer types.Signer, state StateDB, blockNumber *big.Int) (*big.Int, error) {
 	if msg.IsL1MessageTx() {
 		return big.NewInt(0), nil
@@ -69,15 +94,7 @@ func EstimateL1DataFeeForMessage(msg Message, baseFee *big.Int, config *params.C
 		return nil, err
 	}
 
-	gpoState := readGPOStorageSlots(rcfg.L1GasPriceOracleAddress, state)
-
-	var l1DataFee *big.Int
-
-	if !config.IsCurie(blockNumber) {
-		l1DataFee = calculateEncodedL1DataFee(raw, gpoState.overhead, gpoState.l1BaseFee, gpoState.scalar)
-	} else {
-		l1DataFee = calculateEncodedL1DataFeeCurie(raw, gpoState.l1BaseFee, gpoState.l1BlobBaseFee, gpoState.commitScalar, gpoState.blobScalar)
-	}
+	l1DataFee := FeeModelAt(config, blockNumber).L1DataFee(raw, ReadL1FeeParams(state))
 
 	return l1DataFee, nil
 }
@@ -146,18 +163,28 @@ func readGPOStorageSlots(addr common.Address, state StateDB) gpoState {
 
 // calculateEncodedL1DataFee computes the L1 fee for an RLP-encoded tx
 func calculateEncodedL1DataFee(data []byte, overhead, l1BaseFee *big.Int, scalar *big.Int) *big.Int {
-	l1GasUsed := calculateL1GasUsed(data, overhead)
+	return calculateL1DataFee(calculateL1GasUsed(data, overhead), l1BaseFee, scalar)
+}
+
+// calculateL1DataFee computes the L1 fee for the given L1 gas, pre Curie
+func calculateL1DataFee(l1GasUsed, l1BaseFee *big.Int, scalar *big.Int) *big.Int {
 	l1DataFee := new(big.Int).Mul(l1GasUsed, l1BaseFee)
 	return mulAndScale(l1DataFee, scalar, rcfg.Precision)
 }
 
 // calculateEncodedL1DataFeeCurie computes the L1 fee for an RLP-encoded tx, post Curie
 func calculateEncodedL1DataFeeCurie(data []byte, l1BaseFee *big.Int, l1BlobBaseFee *big.Int, commitScalar *big.Int, blobScalar *big.Int) *big.Int {
+	return calculateL1DataFeeCurie(uint64(len(data)), l1BaseFee, l1BlobBaseFee, commitScalar, blobScalar)
+}
+
+// calculateL1DataFeeCurie computes the L1 fee for the given number of bytes
+// posted in blobs, post Curie
+func calculateL1DataFeeCurie(blobBytes uint64, l1BaseFee *big.Int, l1BlobBaseFee *big.Int, commitScalar *big.Int, blobScalar *big.Int) *big.Int {
 	// calldata component of commit fees (calldata gas + execution)
 	calldataGas := new(big.Int).Mul(commitScalar, l1BaseFee)
 
 	// blob component of commit fees
-	blobGas := big.NewInt(int64(len(data)))
+	blobGas := new(big.Int).SetUint64(blobBytes)
 	blobGas = new(big.Int).Mul(blobGas, l1BlobBaseFee)
 	blobGas = new(big.Int).Mul(blobGas, blobScalar)
 
@@ -174,6 +201,12 @@ func calculateEncodedL1DataFeeCurie(data []byte, l1BaseFee *big.Int, l1BlobBaseF
 // under standard network conditions.
 func calculateL1GasUsed(data []byte, overhead *big.Int) *big.Int {
 	zeroes, ones := zeroesAndOnes(data)
+	return calculateL1GasUsedForCounts(zeroes, ones, overhead)
+}
+
+// calculateL1GasUsedForCounts computes the L1 gas used by calldata with the
+// given number of zero and non-zero bytes.
+func calculateL1GasUsedForCounts(zeroes, ones uint64, overhead *big.Int) *big.Int {
 	zeroesGas := zeroes * params.TxDataZeroGas
 	onesGas := (ones + txExtraDataBytes) * params.TxDataNonZeroGasEIP2028
 	l1Gas := new(big.Int).SetUint64(zeroesGas + onesGas)
@@ -211,15 +244,7 @@ func CalculateL1DataFee(tx *types.Transaction, state StateDB, config *params.Cha
 		return nil, err
 	}
 
-	gpoState := readGPOStorageSlots(rcfg.L1GasPriceOracleAddress, state)
-
-	var l1DataFee *big.Int
-
-	if !config.IsCurie(blockNumber) {
-		l1DataFee = calculateEncodedL1DataFee(raw, gpoState.overhead, gpoState.l1BaseFee, gpoState.scalar)
-	} else {
-		l1DataFee = calculateEncodedL1DataFeeCurie(raw, gpoState.l1BaseFee, gpoState.l1BlobBaseFee, gpoState.commitScalar, gpoState.blobScalar)
-	}
+	l1DataFee := FeeModelAt(config, blockNumber).L1DataFee(raw, ReadL1FeeParams(state))
 
 	// ensure l1DataFee fits into uint64 for circuit compatibility
 	// (note: in practice this value should never be this big)
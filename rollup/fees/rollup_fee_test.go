@@ -5,6 +5,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
 )
 
 func TestL1DataFeeBeforeCurie(t *testing.T) {
@@ -31,3 +36,51 @@ func TestL1DataFeeAfterCurie(t *testing.T) {
 	actual := calculateEncodedL1DataFeeCurie(data, l1BaseFee, l1BlobBaseFee, commitScalar, blobScalar)
 	assert.Equal(t, expected, actual)
 }
+
+type testStateDB map[common.Hash]common.Hash
+
+func (s testStateDB) GetState(addr common.Address, slot common.Hash) common.Hash {
+	if addr != rcfg.L1GasPriceOracleAddress {
+		return common.Hash{}
+	}
+	return s[slot]
+}
+
+func TestReadL1FeeParams(t *testing.T) {
+	state := testStateDB{
+		rcfg.L1BaseFeeSlot:     common.BigToHash(big.NewInt(1)),
+		rcfg.OverheadSlot:      common.BigToHash(big.NewInt(2)),
+		rcfg.ScalarSlot:        common.BigToHash(big.NewInt(3)),
+		rcfg.L1BlobBaseFeeSlot: common.BigToHash(big.NewInt(5)),
+		rcfg.CommitScalarSlot:  common.BigToHash(big.NewInt(6)),
+		rcfg.BlobScalarSlot:    common.BigToHash(big.NewInt(7)),
+	}
+	params := ReadL1FeeParams(state)
+	assert.Equal(t, big.NewInt(1), params.L1BaseFee)
+	assert.Equal(t, big.NewInt(2), params.Overhead)
+	assert.Equal(t, big.NewInt(3), params.Scalar)
+	assert.Equal(t, big.NewInt(5), params.L1BlobBaseFee)
+	assert.Equal(t, big.NewInt(6), params.CommitScalar)
+	assert.Equal(t, big.NewInt(7), params.BlobScalar)
+}
+
+func TestL1DataFeeForSize(t *testing.T) {
+	p := &types.L1FeeParams{
+		L1BaseFee:     new(big.Int).SetUint64(1500000000),
+		Overhead:      new(big.Int).SetUint64(100),
+		Scalar:        new(big.Int).SetUint64(10),
+		L1BlobBaseFee: new(big.Int).SetUint64(150000000),
+		CommitScalar:  new(big.Int).SetUint64(10),
+		BlobScalar:    new(big.Int).SetUint64(10),
+	}
+	config := &params.ChainConfig{CurieBlock: big.NewInt(10), FeynmanBlock: big.NewInt(20)}
+	data := []byte{1, 10, 1, 1}
+	assert.Equal(t, calculateEncodedL1DataFee(data, p.Overhead, p.L1BaseFee, p.Scalar), L1DataFeeForSize(config, big.NewInt(9), p, 4))
+	assert.Equal(t, calculateEncodedL1DataFeeCurie(data, p.L1BaseFee, p.L1BlobBaseFee, p.CommitScalar, p.BlobScalar), L1DataFeeForSize(config, big.NewInt(10), p, 4))
+
+	// The size based fee bounds the fee of compressible data from above
+	compressible := make([]byte, 100)
+	bound := L1DataFeeForSize(config, big.NewInt(20), p, uint64(len(compressible)))
+	assert.Equal(t, calculateEncodedL1DataFeeCurie(compressible, p.L1BaseFee, p.L1BlobBaseFee, p.CommitScalar, p.BlobScalar), bound)
+	assert.Equal(t, -1, compressedFeeModel{}.L1DataFee(compressible, p).Cmp(bound))
+}
@@ -77,6 +77,12 @@ type Pipeline struct {
 	applyStageRespCh <-chan error
 	ResultCh         <-chan *Result
 
+	// Methods to call with every transaction that passed the circuit capacity check
+	preconfirmHook func(header *types.Header, index int, receipt *types.Receipt)
+	acceptedTxHook func(candidate *BlockCandidate)
+
+	emptyBlocks bool // Whether to close an empty block if stopped before including any transaction
+
 	// Test hooks
 	beforeTxHook func() // Method to call before processing a transaction.
 }
@@ -114,6 +120,30 @@ func (p *Pipeline) WithBeforeTxHook(beforeTxHook func()) *Pipeline {
 	return p
 }
 
+// WithPreconfirmHook sets a method to call with every transaction that passed
+// execution and the circuit capacity check, along with its index in the block
+// and its receipt.
+func (p *Pipeline) WithPreconfirmHook(preconfirmHook func(header *types.Header, index int, receipt *types.Receipt)) *Pipeline {
+	p.preconfirmHook = preconfirmHook
+	return p
+}
+
+// WithAcceptedTxHook sets a method to call with every transaction that passed
+// execution and the circuit capacity check. The transaction is the last one of
+// the candidate.
+func (p *Pipeline) WithAcceptedTxHook(acceptedTxHook func(candidate *BlockCandidate)) *Pipeline {
+	p.acceptedTxHook = acceptedTxHook
+	return p
+}
+
+// WithEmptyBlocks lets the pipeline close an empty block if it is stopped before
+// including any transaction. The empty block is checked against the circuit
+// capacity like any other.
+func (p *Pipeline) WithEmptyBlocks() *Pipeline {
+	p.emptyBlocks = true
+	return p
+}
+
 func (p *Pipeline) Start(deadline time.Time) error {
 	p.start = time.Now()
 	p.txnQueue = make(chan *types.Transaction)
@@ -246,6 +276,9 @@ func (p *Pipeline) traceAndApplyStage(txsIn <-chan *types.Transaction) (<-chan e
 			select {
 			case tx = <-txsIn:
 				if tx == nil {
+					if p.emptyBlocks && p.txs.Len() == 0 {
+						p.sendEmptyCandidate(downstreamCh)
+					}
 					return
 				}
 			case <-p.ctx.Done():
@@ -329,6 +362,33 @@ func (p *Pipeline) traceAndApplyStage(txsIn <-chan *types.Transaction) (<-chan e
 	return resCh, downstreamCh, nil
 }
 
+// sendEmptyCandidate sends an empty block candidate downstream, traced for the
+// circuit capacity check.
+func (p *Pipeline) sendEmptyCandidate(downstreamCh chan *BlockCandidate) {
+	var trace *types.BlockTrace
+	if p.ccc != nil {
+		var err error
+		snap := p.state.Snapshot()
+		trace, err = tracing.NewTracerWrapper().CreateTraceEnvAndGetBlockTrace(p.chain.Config(), p.chain, p.chain.Engine(), p.chain.Database(),
+			p.state, p.parent, types.NewBlockWithHeader(&p.Header), false)
+		p.state.RevertToSnapshot(snap)
+		if err != nil {
+			log.Error("Failed to trace empty block", "number", p.Header.Number, "err", err)
+			return
+		}
+	}
+	sendCancellable(downstreamCh, &BlockCandidate{
+		NextL1MsgIndex: p.nextL1MsgIndex,
+		LastTrace:      trace,
+
+		Header:        types.CopyHeader(&p.Header),
+		State:         p.state.Copy(),
+		Txs:           types.Transactions{},
+		Receipts:      types.Receipts{},
+		CoalescedLogs: []*types.Log{},
+	}, p.ctx.Done())
+}
+
 type Result struct {
 	OverflowingTx    *types.Transaction
 	OverflowingTrace *types.BlockTrace
@@ -361,7 +421,7 @@ func (p *Pipeline) encodeStage(traces <-chan *BlockCandidate) <-chan *BlockCandi
 				if p.ccc != nil {
 					trace.RustTrace = ccc.MakeRustTrace(trace.LastTrace, buffer)
 					if trace.RustTrace == nil {
-						log.Error("making rust trace", "txHash", trace.LastTrace.Transactions[0].TxHash)
+						log.Error("making rust trace", "number", trace.Header.Number, "txs", trace.Txs.Len())
 						// ignore the error here, CCC stage will catch it and treat it as a CCC error
 					}
 				}
@@ -416,7 +476,7 @@ func (p *Pipeline) cccStage(candidates <-chan *BlockCandidate, deadline time.Tim
 				return
 			case <-deadlineTimer.C:
 				cccIdleTimer.UpdateSince(idleStart)
-				// note: currently we don't allow empty blocks, but if we ever do; make sure to CCC check it first
+				// note: empty blocks are only closed when the pipeline is stopped, see WithEmptyBlocks
 				if lastCandidate != nil {
 					resultCh <- &Result{
 						Rows:       lastAccRows,
@@ -436,7 +496,10 @@ func (p *Pipeline) cccStage(candidates <-chan *BlockCandidate, deadline time.Tim
 					} else {
 						err = errors.New("no rust trace")
 					}
-					lastTxn := candidate.Txs[candidate.Txs.Len()-1]
+					var lastTxn *types.Transaction
+					if n := candidate.Txs.Len(); n > 0 {
+						lastTxn = candidate.Txs[n-1]
+					}
 					cccTimer.UpdateSince(cccStart)
 					if err != nil {
 						resultCh <- &Result{
@@ -451,8 +514,10 @@ func (p *Pipeline) cccStage(candidates <-chan *BlockCandidate, deadline time.Tim
 
 					lastCandidate = candidate
 					lastAccRows = accRows
+					p.acceptTx(candidate)
 				} else if candidate != nil && p.ccc == nil {
 					lastCandidate = candidate
+					p.acceptTx(candidate)
 				}
 
 				// immediately close the block if deadline reached or apply stage is done
@@ -469,6 +534,21 @@ func (p *Pipeline) cccStage(candidates <-chan *BlockCandidate, deadline time.Tim
 	return resultCh
 }
 
+// acceptTx calls the accepted transaction hooks with the candidate, whose last
+// transaction is included in the block being built.
+func (p *Pipeline) acceptTx(candidate *BlockCandidate) {
+	if candidate.Txs.Len() == 0 {
+		return
+	}
+	if p.preconfirmHook != nil {
+		index := candidate.Txs.Len() - 1
+		p.preconfirmHook(candidate.Header, index, candidate.Receipts[index])
+	}
+	if p.acceptedTxHook != nil {
+		p.acceptedTxHook(candidate)
+	}
+}
+
 func (p *Pipeline) traceAndApply(tx *types.Transaction) (*types.Receipt, *types.BlockTrace, error) {
 	var trace *types.BlockTrace
 	var err error
@@ -482,6 +562,13 @@ func (p *Pipeline) traceAndApply(tx *types.Transaction) (*types.Receipt, *types.
 		return nil, nil, core.ErrGasLimitReached
 	}
 
+	// the conditions of conditional transactions must hold right before their execution
+	if cond := tx.Conditional(); cond != nil {
+		if err := core.CheckConditional(cond, p.Header.Number, p.Header.Time, p.state); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	if p.ccc != nil {
 		// don't commit the state during tracing for circuit capacity checker, otherwise we cannot revert.
 		// and even if we don't commit the state, the `refund` value will still be correct, as explained in `CommitTransaction`
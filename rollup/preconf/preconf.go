@@ -0,0 +1,338 @@
+package preconf
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/accounts"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/event"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+	"github.com/scroll-tech/go-ethereum/rlp"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+const (
+	// publishQueueSize is the number of preconfirmations waiting to be signed,
+	// beyond which new ones are dropped rather than stalling block building.
+	publishQueueSize = 4096
+
+	// followerRetryDelay is the delay before subscribing to the sequencer again
+	// after the subscription failed.
+	followerRetryDelay = 5 * time.Second
+)
+
+var (
+	publishedMeter = metrics.NewRegisteredMeter("preconf/published", nil)
+	droppedMeter   = metrics.NewRegisteredMeter("preconf/dropped", nil)
+	relayedMeter   = metrics.NewRegisteredMeter("preconf/relayed", nil)
+	invalidMeter   = metrics.NewRegisteredMeter("preconf/invalid", nil)
+	staleMeter     = metrics.NewRegisteredMeter("preconf/stale", nil)
+	signTimer      = metrics.NewRegisteredTimer("preconf/sign", nil)
+)
+
+var (
+	// ErrInvalidSignature is returned when a preconfirmation is not signed by an
+	// authorized sequencer.
+	ErrInvalidSignature = errors.New("invalid preconfirmation signature")
+
+	// ErrStale is returned when a preconfirmation is for a block already in the
+	// local chain, or for a block built on another parent than the local head.
+	ErrStale = errors.New("stale preconfirmation")
+)
+
+// Preconfirmation is the promise of a sequencer that a transaction passed
+// execution and the circuit capacity check at a position of the block it is
+// building. It is soft: the block may still fail to be sealed.
+type Preconfirmation struct {
+	TxHash            common.Hash    `json:"transactionHash"`
+	BlockNumber       hexutil.Uint64 `json:"blockNumber"`
+	ParentHash        common.Hash    `json:"parentHash"`
+	TransactionIndex  hexutil.Uint   `json:"transactionIndex"`
+	Status            hexutil.Uint64 `json:"status"`
+	GasUsed           hexutil.Uint64 `json:"gasUsed"`
+	CumulativeGasUsed hexutil.Uint64 `json:"cumulativeGasUsed"`
+	Logs              []*types.Log   `json:"logs"`
+	Signature         hexutil.Bytes  `json:"signature"`
+}
+
+// New creates the unsigned preconfirmation of a transaction applied at the
+// given index of the block with the given header.
+func New(header *types.Header, index int, receipt *types.Receipt) *Preconfirmation {
+	logs := receipt.Logs
+	if logs == nil {
+		logs = []*types.Log{}
+	}
+	return &Preconfirmation{
+		TxHash:            receipt.TxHash,
+		BlockNumber:       hexutil.Uint64(header.Number.Uint64()),
+		ParentHash:        header.ParentHash,
+		TransactionIndex:  hexutil.Uint(index),
+		Status:            hexutil.Uint64(receipt.Status),
+		GasUsed:           hexutil.Uint64(receipt.GasUsed),
+		CumulativeGasUsed: hexutil.Uint64(receipt.CumulativeGasUsed),
+		Logs:              logs,
+	}
+}
+
+// Hash returns the hash signed by the sequencer, covering all fields but the
+// signature. Logs are covered by their consensus encoding.
+func (p *Preconfirmation) Hash() common.Hash {
+	data, _ := rlp.EncodeToBytes([]interface{}{
+		p.TxHash,
+		uint64(p.BlockNumber),
+		p.ParentHash,
+		uint64(p.TransactionIndex),
+		uint64(p.Status),
+		uint64(p.GasUsed),
+		uint64(p.CumulativeGasUsed),
+		p.Logs,
+	})
+	return crypto.Keccak256Hash(data)
+}
+
+// Signer recovers the address of the sequencer that signed the preconfirmation.
+// The hash is signed as a text message, so that it can be signed by any wallet.
+// Every preconfirmation is signed on its own, see cmd/clef/rules.md for a clef
+// rule approving them.
+func (p *Preconfirmation) Signer() (common.Address, error) {
+	if len(p.Signature) != crypto.SignatureLength {
+		return common.Address{}, ErrInvalidSignature
+	}
+	hash := p.Hash()
+	pub, err := crypto.SigToPub(accounts.TextHash(hash[:]), p.Signature)
+	if err != nil {
+		return common.Address{}, ErrInvalidSignature
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// SignerFn signs a text message with the given account.
+type SignerFn func(account accounts.Account, text []byte) ([]byte, error)
+
+// Sign signs the preconfirmation with the given account.
+func (p *Preconfirmation) Sign(signer common.Address, signFn SignerFn) error {
+	hash := p.Hash()
+	sig, err := signFn(accounts.Account{Address: signer}, hash[:])
+	if err != nil {
+		return err
+	}
+	if len(sig) != crypto.SignatureLength {
+		return ErrInvalidSignature
+	}
+	p.Signature = sig
+	return nil
+}
+
+// Source delivers preconfirmations to local subscribers.
+type Source interface {
+	Subscribe(ch chan<- *Preconfirmation) event.Subscription
+}
+
+// source delivers preconfirmations for both publishers and followers.
+type source struct {
+	feed  event.Feed
+	scope event.SubscriptionScope
+}
+
+// Subscribe registers a subscription for preconfirmations.
+func (s *source) Subscribe(ch chan<- *Preconfirmation) event.Subscription {
+	return s.scope.Track(s.feed.Subscribe(ch))
+}
+
+// Publisher signs and delivers the preconfirmations of the sequencer. Signing
+// is done in the background, so that a slow signer never delays blocks.
+type Publisher struct {
+	source
+
+	signer common.Address
+	signFn SignerFn
+	lock   sync.RWMutex // Protects the signer fields
+
+	queue chan *Preconfirmation
+	quit  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewPublisher creates a publisher of preconfirmations.
+func NewPublisher() *Publisher {
+	p := &Publisher{
+		queue: make(chan *Preconfirmation, publishQueueSize),
+		quit:  make(chan struct{}),
+	}
+	p.wg.Add(1)
+	go p.loop()
+	return p
+}
+
+// Authorize sets the account signing the preconfirmations.
+func (p *Publisher) Authorize(signer common.Address, signFn SignerFn) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.signer, p.signFn = signer, signFn
+}
+
+// Publish queues the preconfirmation of a transaction applied at the given index
+// of the block being built. Nothing is published without subscribers.
+func (p *Publisher) Publish(header *types.Header, index int, receipt *types.Receipt) {
+	if p.scope.Count() == 0 {
+		return
+	}
+	select {
+	case p.queue <- New(header, index, receipt):
+	default:
+		droppedMeter.Mark(1)
+	}
+}
+
+// Close stops the publisher.
+func (p *Publisher) Close() {
+	close(p.quit)
+	p.wg.Wait()
+	p.scope.Close()
+}
+
+func (p *Publisher) loop() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case preconf := <-p.queue:
+			p.lock.RLock()
+			signer, signFn := p.signer, p.signFn
+			p.lock.RUnlock()
+
+			if signFn == nil {
+				droppedMeter.Mark(1)
+				continue
+			}
+			start := time.Now()
+			if err := preconf.Sign(signer, signFn); err != nil {
+				log.Warn("Failed to sign preconfirmation", "tx", preconf.TxHash, "err", err)
+				droppedMeter.Mark(1)
+				continue
+			}
+			signTimer.UpdateSince(start)
+			publishedMeter.Mark(1)
+			p.feed.Send(preconf)
+
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// Follower relays the preconfirmations of a sequencer to local subscribers,
+// dropping those not signed by an authorized sequencer and the stale ones.
+type Follower struct {
+	source
+
+	url        string
+	authorized func(signer common.Address) bool
+	head       func() *types.Header
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewFollower creates a follower of the preconfirmations published over the
+// given sequencer RPC endpoint, which must support subscriptions. Preconfirmations
+// are checked against the local chain head returned by the given function.
+func NewFollower(url string, authorized func(signer common.Address) bool, head func() *types.Header) *Follower {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &Follower{url: url, authorized: authorized, head: head, cancel: cancel}
+	f.wg.Add(1)
+	go f.loop(ctx)
+	return f
+}
+
+// Close stops relaying preconfirmations.
+func (f *Follower) Close() {
+	f.cancel()
+	f.wg.Wait()
+	f.scope.Close()
+}
+
+// loop keeps a subscription to the sequencer, resubscribing on failures.
+func (f *Follower) loop(ctx context.Context) {
+	defer f.wg.Done()
+
+	for {
+		if err := f.follow(ctx); err != nil {
+			log.Warn("Preconfirmation subscription failed", "endpoint", f.url, "err", err)
+		}
+		select {
+		case <-time.After(followerRetryDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// follow relays the preconfirmations of the sequencer until the subscription
+// fails or the follower is closed.
+func (f *Follower) follow(ctx context.Context) error {
+	client, err := rpc.DialContext(ctx, f.url)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ch := make(chan *Preconfirmation, 256)
+	sub, err := client.EthSubscribe(ctx, ch, "preconfirmations")
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	log.Info("Following sequencer preconfirmations", "endpoint", f.url)
+	for {
+		select {
+		case preconf := <-ch:
+			if err := f.verify(preconf); err != nil {
+				log.Debug("Dropping invalid preconfirmation", "tx", preconf.TxHash, "err", err)
+				if err == ErrStale {
+					staleMeter.Mark(1)
+				} else {
+					invalidMeter.Mark(1)
+				}
+				continue
+			}
+			relayedMeter.Mark(1)
+			f.feed.Send(preconf)
+
+		case err := <-sub.Err():
+			return err
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// verify checks that the preconfirmation is signed by an authorized sequencer,
+// and is for the block following the local head. Preconfirmations beyond it are
+// relayed, the local chain may be lagging behind the sequencer.
+func (f *Follower) verify(preconf *Preconfirmation) error {
+	if head := f.head(); head != nil {
+		number := uint64(preconf.BlockNumber)
+		if number <= head.Number.Uint64() || (number == head.Number.Uint64()+1 && preconf.ParentHash != head.Hash()) {
+			return ErrStale
+		}
+	}
+	signer, err := preconf.Signer()
+	if err != nil {
+		return err
+	}
+	if !f.authorized(signer) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
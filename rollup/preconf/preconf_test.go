@@ -0,0 +1,170 @@
+package preconf
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/accounts"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddress = crypto.PubkeyToAddress(testKey.PublicKey)
+)
+
+func signText(account accounts.Account, text []byte) ([]byte, error) {
+	return crypto.Sign(accounts.TextHash(text), testKey)
+}
+
+func testPreconfirmation(index int) *Preconfirmation {
+	header := &types.Header{Number: big.NewInt(10), ParentHash: common.Hash{1}}
+	receipt := &types.Receipt{
+		TxHash:            common.Hash{2},
+		Status:            types.ReceiptStatusSuccessful,
+		GasUsed:           21000,
+		CumulativeGasUsed: 42000,
+		Logs: []*types.Log{{
+			Address: common.Address{3},
+			Topics:  []common.Hash{{4}},
+			Data:    []byte{5},
+			TxHash:  common.Hash{2},
+		}},
+	}
+	return New(header, index, receipt)
+}
+
+func TestPreconfirmationSignature(t *testing.T) {
+	preconf := testPreconfirmation(1)
+	if _, err := preconf.Signer(); err != ErrInvalidSignature {
+		t.Fatalf("unsigned preconfirmation error mismatch: have %v, want %v", err, ErrInvalidSignature)
+	}
+	if err := preconf.Sign(testAddress, signText); err != nil {
+		t.Fatalf("failed to sign preconfirmation: %v", err)
+	}
+	// Signatures survive the RPC encoding
+	data, err := json.Marshal(preconf)
+	if err != nil {
+		t.Fatalf("failed to encode preconfirmation: %v", err)
+	}
+	decoded := new(Preconfirmation)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("failed to decode preconfirmation: %v", err)
+	}
+	if signer, err := decoded.Signer(); err != nil || signer != testAddress {
+		t.Fatalf("signer mismatch: have %v, want %v, err %v", signer, testAddress, err)
+	}
+	// Tampered preconfirmations are not attributed to the sequencer
+	decoded.TransactionIndex++
+	if signer, _ := decoded.Signer(); signer == testAddress {
+		t.Fatalf("tampered preconfirmation attributed to the sequencer")
+	}
+}
+
+// testService serves the preconfirmations of a source like the filter API.
+type testService struct {
+	source Source
+}
+
+func (s *testService) Preconfirmations(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		preconfs := make(chan *Preconfirmation, 16)
+		sub := s.source.Subscribe(preconfs)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case p := <-preconfs:
+				notifier.Notify(rpcSub.ID, p)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+func TestFollower(t *testing.T) {
+	publisher := NewPublisher()
+	defer publisher.Close()
+	publisher.Authorize(testAddress, signText)
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", &testService{publisher}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer httpServer.Close()
+
+	head := &types.Header{Number: big.NewInt(9)}
+	follower := NewFollower("ws://"+strings.TrimPrefix(httpServer.URL, "http://"), func(signer common.Address) bool {
+		return signer == testAddress
+	}, func() *types.Header { return head })
+	defer follower.Close()
+
+	preconfs := make(chan *Preconfirmation, 16)
+	sub := follower.Subscribe(preconfs)
+	defer sub.Unsubscribe()
+
+	// Publish until the follower is subscribed to the sequencer
+	header := &types.Header{Number: big.NewInt(10), ParentHash: head.Hash()}
+	receipt := &types.Receipt{TxHash: common.Hash{2}, Status: types.ReceiptStatusSuccessful}
+	timeout := time.After(5 * time.Second)
+	for {
+		publisher.Publish(header, 0, receipt)
+		select {
+		case preconf := <-preconfs:
+			if preconf.TxHash != receipt.TxHash || preconf.BlockNumber != 10 {
+				t.Fatalf("unexpected preconfirmation: %+v", preconf)
+			}
+			// Preconfirmations of other signers are dropped
+			other := testPreconfirmation(0)
+			other.ParentHash = head.Hash()
+			otherKey, _ := crypto.GenerateKey()
+			other.Sign(crypto.PubkeyToAddress(otherKey.PublicKey), func(account accounts.Account, text []byte) ([]byte, error) {
+				return crypto.Sign(accounts.TextHash(text), otherKey)
+			})
+			if err := follower.verify(other); err != ErrInvalidSignature {
+				t.Fatalf("verification error mismatch: have %v, want %v", err, ErrInvalidSignature)
+			}
+			// Preconfirmations for blocks in the local chain or built on another
+			// parent are stale, the ones beyond the next block are relayed
+			for _, tt := range []struct {
+				number uint64
+				parent common.Hash
+				err    error
+			}{
+				{9, common.Hash{}, ErrStale},
+				{10, common.Hash{1}, ErrStale},
+				{10, head.Hash(), nil},
+				{11, common.Hash{1}, nil},
+			} {
+				preconf := testPreconfirmation(0)
+				preconf.BlockNumber, preconf.ParentHash = hexutil.Uint64(tt.number), tt.parent
+				preconf.Sign(testAddress, signText)
+				if err := follower.verify(preconf); err != tt.err {
+					t.Fatalf("#%d parent %x: verification error mismatch: have %v, want %v", tt.number, tt.parent, err, tt.err)
+				}
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		case <-timeout:
+			t.Fatalf("preconfirmation not relayed")
+		}
+	}
+}
@@ -36,6 +36,12 @@ var (
 	BlobScalarSlot    = common.BigToHash(big.NewInt(7))
 	IsCurieSlot       = common.BigToHash(big.NewInt(8))
 
+	// New fields added in the Feynman hard fork. Once set, the L1 data fee is
+	// charged for the estimated compressed size of the transaction, see
+	// fees.EstimateCompressedSize. The getL1Fee view of the contract is not
+	// upgraded and is deprecated from then on.
+	IsFeynmanSlot = common.BigToHash(big.NewInt(9))
+
 	InitialCommitScalar = big.NewInt(230759955285)
 	InitialBlobScalar   = big.NewInt(417565260)
 
@@ -0,0 +1,170 @@
+package sync_service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+)
+
+const (
+	// DefaultBacklogThreshold is the default age of the oldest unprocessed L1
+	// message beyond which the backlog watchdog raises an alert.
+	DefaultBacklogThreshold = 10 * time.Minute
+
+	// BacklogCheckInterval is the frequency at which we check the L1 message backlog.
+	BacklogCheckInterval = time.Second * 10
+)
+
+var (
+	backlogCountGauge = metrics.NewRegisteredGauge("rollup/l1/backlog/count", nil)
+	backlogAgeGauge   = metrics.NewRegisteredGauge("rollup/l1/backlog/age", nil) // seconds
+	backlogAlertGauge = metrics.NewRegisteredGauge("rollup/l1/backlog/alert", nil)
+)
+
+// Backlog describes the L1 messages synced from L1 that are neither included
+// nor skipped by the local L2 chain yet.
+type Backlog struct {
+	HighestSyncedQueueIndex *uint64 `json:"highestSyncedQueueIndex"` // nil if no message was synced
+	NextQueueIndex          uint64  `json:"nextQueueIndex"`          // First message not processed by the L2 chain head
+	Count                   uint64  `json:"count"`
+	OldestSyncedAt          uint64  `json:"oldestSyncedAt,omitempty"` // Unix time the oldest unprocessed message was first seen
+	OldestAge               uint64  `json:"oldestAge"`                // Seconds since the oldest unprocessed message was first seen
+	Threshold               uint64  `json:"threshold"`                // Age in seconds beyond which the backlog is alerted
+	Alert                   bool    `json:"alert"`
+}
+
+// backlogMark records that all the messages below a queue index were synced at
+// the given time.
+type backlogMark struct {
+	queueIndex uint64
+	time       time.Time
+}
+
+// BacklogWatchdog tracks the L1 messages that the sequencer keeps not processing,
+// to detect censored deposits or a stalled sequencer. As messages do not carry
+// the time they were sent, their age is counted from when the watchdog first
+// saw them synced. Messages already synced at startup are aged from startup.
+type BacklogWatchdog struct {
+	db        ethdb.Database
+	head      func() *types.Header
+	threshold time.Duration
+
+	marks   []backlogMark // Increasing synced queue indexes with the time they were first seen
+	backlog Backlog
+	lock    sync.RWMutex // Protects the backlog fields
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewBacklogWatchdog creates a watchdog of the L1 messages not processed by
+// the chain whose head is given, alerting beyond the given age. A non-positive
+// threshold selects DefaultBacklogThreshold.
+func NewBacklogWatchdog(db ethdb.Database, head func() *types.Header, threshold time.Duration) *BacklogWatchdog {
+	if threshold <= 0 {
+		threshold = DefaultBacklogThreshold
+	}
+	return &BacklogWatchdog{
+		db:        db,
+		head:      head,
+		threshold: threshold,
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start starts checking the backlog in the background.
+func (w *BacklogWatchdog) Start() {
+	log.Info("Starting L1 message backlog watchdog", "threshold", w.threshold)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		t := time.NewTicker(BacklogCheckInterval)
+		defer t.Stop()
+
+		for {
+			w.check(time.Now())
+
+			select {
+			case <-t.C:
+			case <-w.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the watchdog.
+func (w *BacklogWatchdog) Stop() {
+	log.Info("Stopping L1 message backlog watchdog")
+
+	close(w.quit)
+	w.wg.Wait()
+}
+
+// Backlog returns the backlog as of the last check.
+func (w *BacklogWatchdog) Backlog() Backlog {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return w.backlog
+}
+
+// check updates the backlog, alerting if its oldest message crossed the threshold.
+func (w *BacklogWatchdog) check(now time.Time) {
+	head := w.head()
+	if head == nil {
+		return
+	}
+	next := rawdb.ReadFirstQueueIndexNotInL2Block(w.db, head.Hash())
+	if next == nil {
+		// Unknown while the head is being imported, retry later
+		return
+	}
+	// Messages are synced in order, mark the new ones as seen now
+	backlog := Backlog{NextQueueIndex: *next, Threshold: uint64(w.threshold / time.Second)}
+	highest := rawdb.ReadHighestSyncedQueueIndex(w.db)
+	if rawdb.ReadL1Message(w.db, highest) != nil {
+		backlog.HighestSyncedQueueIndex = &highest
+		if len(w.marks) == 0 || w.marks[len(w.marks)-1].queueIndex <= highest {
+			w.marks = append(w.marks, backlogMark{queueIndex: highest + 1, time: now})
+		}
+	}
+	// Forget about processed messages, the first remaining mark dates the
+	// oldest unprocessed message
+	for len(w.marks) > 0 && w.marks[0].queueIndex <= *next {
+		w.marks = w.marks[1:]
+	}
+	if len(w.marks) > 0 {
+		oldest := w.marks[0].time
+		backlog.Count = w.marks[len(w.marks)-1].queueIndex - *next
+		backlog.OldestSyncedAt = uint64(oldest.Unix())
+		backlog.OldestAge = uint64(now.Sub(oldest) / time.Second)
+		backlog.Alert = now.Sub(oldest) >= w.threshold
+	}
+	backlogCountGauge.Update(int64(backlog.Count))
+	backlogAgeGauge.Update(int64(backlog.OldestAge))
+
+	w.lock.Lock()
+	alerted := w.backlog.Alert
+	w.backlog = backlog
+	w.lock.Unlock()
+
+	switch {
+	case backlog.Alert:
+		backlogAlertGauge.Update(1)
+		if !alerted {
+			log.Warn("L1 messages not processed by the sequencer", "next", backlog.NextQueueIndex, "count", backlog.Count,
+				"age", time.Duration(backlog.OldestAge)*time.Second, "threshold", w.threshold)
+		}
+	case alerted:
+		backlogAlertGauge.Update(0)
+		log.Info("L1 message backlog recovered", "next", backlog.NextQueueIndex, "count", backlog.Count)
+	}
+}
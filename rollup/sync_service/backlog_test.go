@@ -0,0 +1,53 @@
+package sync_service
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+)
+
+func TestBacklogWatchdog(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	head := &types.Header{Number: big.NewInt(1)}
+	w := NewBacklogWatchdog(db, func() *types.Header { return head }, time.Minute)
+
+	// Nothing is reported without messages
+	start := time.Now()
+	rawdb.WriteFirstQueueIndexNotInL2Block(db, head.Hash(), 0)
+	w.check(start)
+	if backlog := w.Backlog(); backlog.HighestSyncedQueueIndex != nil || backlog.Count != 0 || backlog.Alert {
+		t.Fatalf("unexpected backlog: %+v", backlog)
+	}
+	// Synced messages age until the threshold is crossed
+	for i := uint64(0); i < 3; i++ {
+		rawdb.WriteL1Message(db, types.L1MessageTx{QueueIndex: i, To: &common.Address{}, Value: new(big.Int)})
+	}
+	w.check(start)
+	w.check(start.Add(30 * time.Second))
+	if backlog := w.Backlog(); backlog.Count != 3 || backlog.OldestAge != 30 || backlog.Alert {
+		t.Fatalf("unexpected backlog: %+v", backlog)
+	}
+	rawdb.WriteL1Message(db, types.L1MessageTx{QueueIndex: 3, To: &common.Address{}, Value: new(big.Int)})
+	w.check(start.Add(time.Minute))
+	if backlog := w.Backlog(); backlog.Count != 4 || *backlog.HighestSyncedQueueIndex != 3 || !backlog.Alert {
+		t.Fatalf("unexpected backlog: %+v", backlog)
+	}
+	// Processing the old messages ages the backlog from the newer ones
+	head = &types.Header{Number: big.NewInt(2)}
+	rawdb.WriteFirstQueueIndexNotInL2Block(db, head.Hash(), 3)
+	w.check(start.Add(90 * time.Second))
+	if backlog := w.Backlog(); backlog.Count != 1 || backlog.NextQueueIndex != 3 || backlog.OldestAge != 30 || backlog.Alert {
+		t.Fatalf("unexpected backlog: %+v", backlog)
+	}
+	// Processing all the messages clears the backlog
+	head = &types.Header{Number: big.NewInt(3)}
+	rawdb.WriteFirstQueueIndexNotInL2Block(db, head.Hash(), 4)
+	w.check(start.Add(3 * time.Minute))
+	if backlog := w.Backlog(); backlog.Count != 0 || backlog.OldestAge != 0 || backlog.Alert {
+		t.Fatalf("unexpected backlog: %+v", backlog)
+	}
+}
@@ -20,9 +20,10 @@ type BridgeClient struct {
 	confirmations         rpc.BlockNumber
 	l1MessageQueueAddress common.Address
 	filterer              *L1MessageQueueFilterer
+	signerRegistry        *signerRegistry // nil if signer set updates are not synced
 }
 
-func newBridgeClient(ctx context.Context, l1Client EthClient, l1ChainId uint64, confirmations rpc.BlockNumber, l1MessageQueueAddress common.Address) (*BridgeClient, error) {
+func newBridgeClient(ctx context.Context, l1Client EthClient, l1ChainId uint64, confirmations rpc.BlockNumber, l1MessageQueueAddress common.Address, signerRegistryAddress common.Address) (*BridgeClient, error) {
 	if l1MessageQueueAddress == (common.Address{}) {
 		return nil, errors.New("must pass non-zero l1MessageQueueAddress to BridgeClient")
 	}
@@ -47,13 +48,19 @@ func newBridgeClient(ctx context.Context, l1Client EthClient, l1ChainId uint64,
 		l1MessageQueueAddress: l1MessageQueueAddress,
 		filterer:              filterer,
 	}
+	if signerRegistryAddress != (common.Address{}) {
+		if client.signerRegistry, err = newSignerRegistry(signerRegistryAddress); err != nil {
+			return nil, fmt.Errorf("failed to initialize signer registry, err = %w", err)
+		}
+	}
 
 	return &client, nil
 }
 
 // fetchMessagesInRange retrieves and parses all L1 messages between the
-// provided from and to L1 block numbers (inclusive).
-func (c *BridgeClient) fetchMessagesInRange(ctx context.Context, from, to uint64) ([]types.L1MessageTx, error) {
+// provided from and to L1 block numbers (inclusive), along with the number of
+// the L1 block that emitted each of them.
+func (c *BridgeClient) fetchMessagesInRange(ctx context.Context, from, to uint64) ([]types.L1MessageTx, []uint64, error) {
 	log.Trace("BridgeClient fetchMessagesInRange", "fromBlock", from, "toBlock", to)
 
 	opts := bind.FilterOpts{
@@ -63,17 +70,20 @@ func (c *BridgeClient) fetchMessagesInRange(ctx context.Context, from, to uint64
 	}
 	it, err := c.filterer.FilterQueueTransaction(&opts, nil, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var msgs []types.L1MessageTx
+	var (
+		msgs     []types.L1MessageTx
+		l1Blocks []uint64
+	)
 
 	for it.Next() {
 		event := it.Event
 		log.Trace("Received new L1 QueueTransaction event", "event", event)
 
 		if !event.GasLimit.IsUint64() {
-			return nil, fmt.Errorf("invalid QueueTransaction event: QueueIndex = %v, GasLimit = %v", event.QueueIndex, event.GasLimit)
+			return nil, nil, fmt.Errorf("invalid QueueTransaction event: QueueIndex = %v, GasLimit = %v", event.QueueIndex, event.GasLimit)
 		}
 
 		msgs = append(msgs, types.L1MessageTx{
@@ -84,13 +94,14 @@ func (c *BridgeClient) fetchMessagesInRange(ctx context.Context, from, to uint64
 			Data:       event.Data,
 			Sender:     event.Sender,
 		})
+		l1Blocks = append(l1Blocks, event.Raw.BlockNumber)
 	}
 
 	if err := it.Error(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return msgs, nil
+	return msgs, l1Blocks, nil
 }
 
 func (c *BridgeClient) getLatestConfirmedBlockNumber(ctx context.Context) (uint64, error) {
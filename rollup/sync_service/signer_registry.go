@@ -0,0 +1,83 @@
+package sync_service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// signerRegistryABI contains the events of the L1 contract announcing the
+// updates of the sequencer signer set.
+const signerRegistryABI = `[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint64","name":"startBlock","type":"uint64"},{"indexed":false,"internalType":"address[]","name":"signers","type":"address[]"}],"name":"SignerSetUpdated","type":"event"}]`
+
+// SignerUpdate is an update of the sequencer signer set announced on L1, which
+// authorizes the signers from the L2 start block onwards. It only applies if the
+// L2 chain commits to the L1 block announcing it early enough, see
+// params.CliqueConfig.SignerRotationLead.
+type SignerUpdate struct {
+	StartBlock uint64
+	L1Block    uint64
+	Signers    []common.Address
+}
+
+// signerRegistry parses the events of the L1 signer registry.
+type signerRegistry struct {
+	address common.Address
+	abi     abi.ABI
+}
+
+func newSignerRegistry(address common.Address) (*signerRegistry, error) {
+	parsed, err := abi.JSON(strings.NewReader(signerRegistryABI))
+	if err != nil {
+		return nil, err
+	}
+	return &signerRegistry{address: address, abi: parsed}, nil
+}
+
+// fetchSignerUpdatesInRange retrieves and parses all signer set updates between
+// the provided from and to L1 block numbers (inclusive). Empty signer sets are
+// ignored since they would halt the chain.
+func (c *BridgeClient) fetchSignerUpdatesInRange(ctx context.Context, from, to uint64) ([]SignerUpdate, error) {
+	if c.signerRegistry == nil {
+		return nil, nil
+	}
+	log.Trace("BridgeClient fetchSignerUpdatesInRange", "fromBlock", from, "toBlock", to)
+
+	event := c.signerRegistry.abi.Events["SignerSetUpdated"]
+	logs, err := c.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from), // inclusive
+		ToBlock:   new(big.Int).SetUint64(to),   // inclusive
+		Addresses: []common.Address{c.signerRegistry.address},
+		Topics:    [][]common.Hash{{event.ID}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter signer registry logs: %w", err)
+	}
+	var updates []SignerUpdate
+	for _, l := range logs {
+		if len(l.Topics) != 2 {
+			return nil, fmt.Errorf("invalid SignerSetUpdated event in tx %x: %d topics", l.TxHash, len(l.Topics))
+		}
+		startBlock := new(big.Int).SetBytes(l.Topics[1].Bytes())
+		if !startBlock.IsUint64() {
+			return nil, fmt.Errorf("invalid SignerSetUpdated event in tx %x: start block %v", l.TxHash, startBlock)
+		}
+		values, err := event.Inputs.NonIndexed().Unpack(l.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack SignerSetUpdated event in tx %x: %w", l.TxHash, err)
+		}
+		signers := values[0].([]common.Address)
+		if len(signers) == 0 {
+			log.Error("Ignoring empty L1 signer set update", "startBlock", startBlock, "tx", l.TxHash)
+			continue
+		}
+		updates = append(updates, SignerUpdate{StartBlock: startBlock.Uint64(), L1Block: l.BlockNumber, Signers: signers})
+	}
+	return updates, nil
+}
@@ -49,7 +49,9 @@ type SyncService struct {
 	msgCountFeed         event.Feed
 	pollInterval         time.Duration
 	latestProcessedBlock uint64
+	deploymentBlock      uint64
 	scope                event.SubscriptionScope
+	signerUpdateHook     func(startBlock uint64) // Called once a signer set update is stored, nil if unset
 }
 
 func NewSyncService(ctx context.Context, genesisConfig *params.ChainConfig, nodeConfig *node.Config, db ethdb.Database, l1Client EthClient) (*SyncService, error) {
@@ -63,7 +65,8 @@ func NewSyncService(ctx context.Context, genesisConfig *params.ChainConfig, node
 		return nil, fmt.Errorf("missing L1 config in genesis")
 	}
 
-	client, err := newBridgeClient(ctx, l1Client, genesisConfig.Scroll.L1Config.L1ChainId, nodeConfig.L1Confirmations, genesisConfig.Scroll.L1Config.L1MessageQueueAddress)
+	l1Config := genesisConfig.Scroll.L1Config
+	client, err := newBridgeClient(ctx, l1Client, l1Config.L1ChainId, nodeConfig.L1Confirmations, l1Config.L1MessageQueueAddress, l1Config.SignerRegistryAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize bridge client: %w", err)
 	}
@@ -76,6 +79,17 @@ func NewSyncService(ctx context.Context, genesisConfig *params.ChainConfig, node
 		latestProcessedBlock = *block
 	}
 
+	// messages synced by older versions lack their L1 block number, which the
+	// L1 message inclusion rule needs, schedule them for backfilling once
+	if rawdb.ReadL1MessageBlockBackfill(db) == nil {
+		backfill := nodeConfig.L1DeploymentBlock
+		highest := rawdb.ReadHighestSyncedQueueIndex(db)
+		if block != nil && rawdb.ReadL1MessageRLP(db, highest) != nil && rawdb.ReadL1MessageBlockNumber(db, highest) == nil {
+			backfill = *block
+		}
+		rawdb.WriteL1MessageBlockBackfill(db, backfill)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 
 	service := SyncService{
@@ -85,6 +99,7 @@ func NewSyncService(ctx context.Context, genesisConfig *params.ChainConfig, node
 		db:                   db,
 		pollInterval:         DefaultPollInterval,
 		latestProcessedBlock: latestProcessedBlock,
+		deploymentBlock:      nodeConfig.L1DeploymentBlock,
 	}
 
 	return &service, nil
@@ -98,6 +113,8 @@ func (s *SyncService) Start() {
 	// wait for initial sync before starting node
 	log.Info("Starting L1 message sync service", "latestProcessedBlock", s.latestProcessedBlock)
 
+	go s.backfillMessageBlocks()
+
 	// block node startup during initial sync and print some helpful logs
 	latestConfirmed, err := s.client.getLatestConfirmedBlockNumber(s.ctx)
 	if err == nil && latestConfirmed > s.latestProcessedBlock+1000 {
@@ -139,12 +156,72 @@ func (s *SyncService) Stop() {
 	}
 }
 
+// SetSignerUpdateHook sets the function called with the L2 start block of every
+// signer set update once stored, so that state derived from the previous signer
+// set can be dropped. It must be called before Start.
+func (s *SyncService) SetSignerUpdateHook(hook func(startBlock uint64)) {
+	if s == nil {
+		return
+	}
+	s.signerUpdateHook = hook
+}
+
 // SubscribeNewL1MsgsEvent registers a subscription of NewL1MsgsEvent and
 // starts sending event to the given channel.
 func (s *SyncService) SubscribeNewL1MsgsEvent(ch chan<- core.NewL1MsgsEvent) event.Subscription {
 	return s.scope.Track(s.msgCountFeed.Subscribe(ch))
 }
 
+// backfillMessageBlocks stores the L1 block numbers of the messages synced by older
+// versions. Recent messages go first, as only the ones not yet processed by the
+// chain are needed to validate new blocks.
+func (s *SyncService) backfillMessageBlocks() {
+	to := rawdb.ReadL1MessageBlockBackfill(s.db)
+	if to == nil || *to <= s.deploymentBlock {
+		return
+	}
+	log.Info("Backfilling L1 blocks of L1 messages", "from", *to, "to", s.deploymentBlock+1)
+
+	t := time.NewTicker(LogProgressInterval)
+	defer t.Stop()
+
+	for *to > s.deploymentBlock {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-t.C:
+			log.Info("Backfilling L1 blocks of L1 messages", "remaining", *to-s.deploymentBlock)
+		default:
+		}
+
+		from := s.deploymentBlock + 1
+		if *to-from >= DefaultFetchBlockRange {
+			from = *to - DefaultFetchBlockRange + 1
+		}
+		msgs, l1Blocks, err := s.client.fetchMessagesInRange(s.ctx, from, *to)
+		if err != nil {
+			log.Warn("Failed to fetch L1 messages to backfill", "fromBlock", from, "toBlock", *to, "err", err)
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(s.pollInterval):
+				continue
+			}
+		}
+
+		batchWriter := s.db.NewBatch()
+		for i, msg := range msgs {
+			rawdb.WriteL1MessageBlockNumber(batchWriter, msg.QueueIndex, l1Blocks[i])
+		}
+		rawdb.WriteL1MessageBlockBackfill(batchWriter, from-1)
+		if err := batchWriter.Write(); err != nil {
+			log.Crit("Failed to write L1 message blocks to database", "err", err)
+		}
+		*to = from - 1
+	}
+	log.Info("Backfilled L1 blocks of L1 messages")
+}
+
 func (s *SyncService) fetchMessages() {
 	latestConfirmed, err := s.client.getLatestConfirmedBlockNumber(s.ctx)
 	if err != nil {
@@ -160,6 +237,7 @@ func (s *SyncService) fetchMessages() {
 	batchWriter := s.db.NewBatch()
 	numBlocksPendingDbWrite := uint64(0)
 	numMessagesPendingDbWrite := 0
+	var pendingSignerUpdates []uint64
 
 	// helper function to flush database writes cached in memory
 	flush := func(lastBlock uint64) {
@@ -181,6 +259,12 @@ func (s *SyncService) fetchMessages() {
 			s.msgCountFeed.Send(core.NewL1MsgsEvent{Count: numMessagesPendingDbWrite})
 			numMessagesPendingDbWrite = 0
 		}
+		if s.signerUpdateHook != nil {
+			for _, startBlock := range pendingSignerUpdates {
+				s.signerUpdateHook(startBlock)
+			}
+		}
+		pendingSignerUpdates = nil
 
 		s.latestProcessedBlock = lastBlock
 	}
@@ -209,7 +293,7 @@ func (s *SyncService) fetchMessages() {
 			to = latestConfirmed
 		}
 
-		msgs, err := s.client.fetchMessagesInRange(s.ctx, from, to)
+		msgs, l1Blocks, err := s.client.fetchMessagesInRange(s.ctx, from, to)
 		if err != nil {
 			// flush pending writes to database
 			if from > 0 {
@@ -222,6 +306,9 @@ func (s *SyncService) fetchMessages() {
 		if len(msgs) > 0 {
 			log.Debug("Received new L1 events", "fromBlock", from, "toBlock", to, "count", len(msgs))
 			rawdb.WriteL1Messages(batchWriter, msgs) // collect messages in memory
+			for i, msg := range msgs {
+				rawdb.WriteL1MessageBlockNumber(batchWriter, msg.QueueIndex, l1Blocks[i])
+			}
 			numMsgsCollected += len(msgs)
 		}
 
@@ -236,6 +323,21 @@ func (s *SyncService) fetchMessages() {
 			}
 		}
 
+		updates, err := s.client.fetchSignerUpdatesInRange(s.ctx, from, to)
+		if err != nil {
+			// flush pending writes to database
+			if from > 0 {
+				flush(from - 1)
+			}
+			log.Warn("Failed to fetch L1 signer set updates in range", "fromBlock", from, "toBlock", to, "err", err)
+			return
+		}
+		for _, update := range updates {
+			log.Info("Received L1 signer set update", "startBlock", update.StartBlock, "l1Block", update.L1Block, "signers", update.Signers)
+			rawdb.WriteSignerUpdate(batchWriter, update.StartBlock, &rawdb.SignerUpdate{L1Block: update.L1Block, Signers: update.Signers})
+			pendingSignerUpdates = append(pendingSignerUpdates, update.StartBlock)
+		}
+
 		numBlocksPendingDbWrite += to - from + 1
 		numMessagesPendingDbWrite += len(msgs)
 
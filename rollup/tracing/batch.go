@@ -0,0 +1,216 @@
+package tracing
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/consensus"
+	"github.com/scroll-tech/go-ethereum/consensus/misc"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+	"github.com/scroll-tech/go-ethereum/params"
+)
+
+var getBatchWitnessTimer = metrics.NewRegisteredTimer("rollup/tracing/get_batch_witness", nil)
+
+// CreateTraceEnvAndGetBatchWitness traces a range of consecutive blocks on top of
+// the state of the parent of the first block and merges the per-block traces
+// into a single deduplicated witness. statedb must be the state of parent and
+// is advanced to the state of the last block.
+func (tw *TracerWrapper) CreateTraceEnvAndGetBatchWitness(chainConfig *params.ChainConfig, chainContext core.ChainContext, engine consensus.Engine, chaindb ethdb.Database, statedb *state.StateDB, parent *types.Block, blocks []*types.Block) (*types.BatchWitness, error) {
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("no blocks to trace")
+	}
+	defer func(t time.Time) {
+		getBatchWitnessTimer.Update(time.Since(t))
+	}(time.Now())
+
+	initial := statedb.Copy()
+	merger := newBatchWitnessMerger(chainConfig, parent.Root())
+	for _, block := range blocks {
+		if block.ParentHash() != parent.Hash() {
+			return nil, fmt.Errorf("block %d (%x) is not a child of %d (%x)", block.NumberU64(), block.Hash(), parent.NumberU64(), parent.Hash())
+		}
+		// Apply the same irregular state changes as the state processor, the
+		// roots of the later blocks would not match otherwise.
+		if chainConfig.DAOForkSupport && chainConfig.DAOForkBlock != nil && chainConfig.DAOForkBlock.Cmp(block.Number()) == 0 {
+			misc.ApplyDAOHardFork(statedb)
+		}
+		if chainConfig.CurieBlock != nil && chainConfig.CurieBlock.Cmp(block.Number()) == 0 {
+			misc.ApplyCurieHardFork(statedb)
+		}
+		if chainConfig.FeynmanBlock != nil && chainConfig.FeynmanBlock.Cmp(block.Number()) == 0 {
+			misc.ApplyFeynmanHardFork(statedb)
+		}
+		env, err := CreateTraceEnv(chainConfig, chainContext, engine, chaindb, statedb, parent, block, true)
+		if err != nil {
+			return nil, err
+		}
+		trace, err := env.GetBlockTrace(block)
+		if err != nil {
+			return nil, err
+		}
+		if root := statedb.IntermediateRoot(chainConfig.IsEIP158(block.Number())); root != block.Root() {
+			return nil, fmt.Errorf("state root mismatch after block %d: have %x, want %x", block.NumberU64(), root, block.Root())
+		}
+		merger.addBlock(trace, env.ZkTrieTracer)
+		parent = block
+	}
+	return merger.witness(initial)
+}
+
+// batchWitnessMerger accumulates the per-block traces of a block range.
+type batchWitnessMerger struct {
+	result *types.BatchWitness
+
+	accounts map[string]struct{}            // Accounts touched in any block
+	slots    map[string]map[string]struct{} // Storage slots touched in any block
+	codes    map[common.Hash]struct{}       // Keccak hashes of collected bytecodes
+	deletion map[string]struct{}            // Collected deletion proof nodes
+
+	// tracers holds the zktrie proof tracers of all blocks, merged per account
+	// and storage root; tracers over different roots cannot be merged.
+	tracers map[string][]state.ZktrieProofTracer
+}
+
+func newBatchWitnessMerger(chainConfig *params.ChainConfig, rootBefore common.Hash) *batchWitnessMerger {
+	var chainID uint64
+	if chainConfig.ChainID != nil {
+		chainID = chainConfig.ChainID.Uint64()
+	}
+	return &batchWitnessMerger{
+		result: &types.BatchWitness{
+			ChainID: chainID,
+			Version: params.ArchiveVersion(params.CommitHash),
+			StorageTrace: &types.StorageTrace{
+				RootBefore:    rootBefore,
+				Proofs:        make(map[string][]hexutil.Bytes),
+				StorageProofs: make(map[string]map[string][]hexutil.Bytes),
+			},
+		},
+		accounts: make(map[string]struct{}),
+		slots:    make(map[string]map[string]struct{}),
+		codes:    make(map[common.Hash]struct{}),
+		deletion: make(map[string]struct{}),
+		tracers:  make(map[string][]state.ZktrieProofTracer),
+	}
+}
+
+func (m *batchWitnessMerger) addBlock(trace *types.BlockTrace, tracers map[string]state.ZktrieProofTracer) {
+	m.result.Blocks = append(m.result.Blocks, &types.BatchWitnessBlock{
+		Coinbase:          trace.Coinbase,
+		Header:            trace.Header,
+		Transactions:      trace.Transactions,
+		ExecutionResults:  trace.ExecutionResults,
+		WithdrawTrieRoot:  trace.WithdrawTrieRoot,
+		StartL1QueueIndex: trace.StartL1QueueIndex,
+	})
+	m.result.StorageTrace.RootAfter = trace.StorageTrace.RootAfter
+
+	for addr := range trace.StorageTrace.Proofs {
+		m.accounts[addr] = struct{}{}
+	}
+	for addr, slots := range trace.StorageTrace.StorageProofs {
+		m.accounts[addr] = struct{}{}
+		if _, ok := m.slots[addr]; !ok {
+			m.slots[addr] = make(map[string]struct{})
+		}
+		for slot := range slots {
+			m.slots[addr][slot] = struct{}{}
+		}
+	}
+	for _, code := range trace.Bytecodes {
+		if _, ok := m.codes[code.KeccakCodeHash]; !ok {
+			m.codes[code.KeccakCodeHash] = struct{}{}
+			m.result.Bytecodes = append(m.result.Bytecodes, code)
+		}
+	}
+	m.addDeletionProofs(trace.StorageTrace.DeletionProofs)
+
+	for addr, tracer := range tracers {
+		if !tracer.Available() {
+			continue
+		}
+		merged := false
+		for _, existing := range m.tracers[addr] {
+			if existing.Hash() == tracer.Hash() {
+				existing.Merge(tracer)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			m.tracers[addr] = append(m.tracers[addr], tracer)
+		}
+	}
+}
+
+func (m *batchWitnessMerger) addDeletionProofs(proofs []hexutil.Bytes) {
+	for _, proof := range proofs {
+		if _, ok := m.deletion[string(proof)]; !ok {
+			m.deletion[string(proof)] = struct{}{}
+			m.result.StorageTrace.DeletionProofs = append(m.result.StorageTrace.DeletionProofs, proof)
+		}
+	}
+}
+
+// witness finalizes the merged witness, proving every touched account and slot
+// against the initial state of the range.
+func (m *batchWitnessMerger) witness(initial *state.StateDB) (*types.BatchWitness, error) {
+	trace := m.result.StorageTrace
+	for addrStr := range m.accounts {
+		addr := common.HexToAddress(addrStr)
+		proof, err := initial.GetProof(addr)
+		if err != nil {
+			log.Error("Proof not available", "address", addrStr, "error", err)
+			// but we still mark the proofs map with nil array
+		}
+		trace.Proofs[addrStr] = types.WrapProof(proof)
+	}
+	for addrStr, slots := range m.slots {
+		addr := common.HexToAddress(addrStr)
+		storageProofs := make(map[string][]hexutil.Bytes)
+		trace.StorageProofs[addrStr] = storageProofs
+
+		trie, err := initial.GetStorageTrieForProof(addr)
+		if err != nil {
+			return nil, fmt.Errorf("storage trie of %s not available: %w", addrStr, err)
+		}
+		for slotStr := range slots {
+			proof, err := initial.GetSecureTrieProof(trie, common.HexToHash(slotStr))
+			if err != nil {
+				log.Error("Storage proof not available", "error", err, "address", addrStr, "key", slotStr)
+				// but we still mark the proofs map with nil array
+			}
+			storageProofs[slotStr] = types.WrapProof(proof)
+		}
+	}
+
+	// Deletions of different blocks on the same storage trie may only be
+	// provable together, so collect the proofs of the merged tracers as well.
+	for _, tracers := range m.tracers {
+		for _, tracer := range tracers {
+			proofs, err := tracer.GetDeletionProofs()
+			if err != nil {
+				log.Error("deletion proof failure", "error", err)
+				continue
+			}
+			m.addDeletionProofs(types.WrapProof(proofs))
+		}
+	}
+	sort.Slice(trace.DeletionProofs, func(i, j int) bool {
+		return bytes.Compare(trace.DeletionProofs[i], trace.DeletionProofs[j]) < 0
+	})
+	sort.Slice(m.result.Bytecodes, func(i, j int) bool {
+		return bytes.Compare(m.result.Bytecodes[i].KeccakCodeHash[:], m.result.Bytecodes[j].KeccakCodeHash[:]) < 0
+	})
+	return m.result, nil
+}
@@ -538,6 +538,7 @@ func (env *TraceEnv) fillBlockTrace(block *types.Block) (*types.BlockTrace, erro
 			rcfg.CommitScalarSlot,
 			rcfg.BlobScalarSlot,
 			rcfg.IsCurieSlot,
+			rcfg.IsFeynmanSlot,
 		},
 	}
 
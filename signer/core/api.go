@@ -43,7 +43,7 @@ const (
 	// ExternalAPIVersion -- see extapi_changelog.md
 	ExternalAPIVersion = "6.1.0"
 	// InternalAPIVersion -- see intapi_changelog.md
-	InternalAPIVersion = "7.0.1"
+	InternalAPIVersion = "7.1.0"
 )
 
 // ExternalAPI defines the external API through which signing requests are made.
@@ -239,6 +239,15 @@ type (
 		Callinfo    []apitypes.ValidationInfo `json:"call_info"`
 		Hash        hexutil.Bytes             `json:"hash"`
 		Meta        Metadata                  `json:"meta"`
+		Clique      *CliqueHeader             `json:"clique_header,omitempty"`
+	}
+	// CliqueHeader summarizes the clique header of a signing request, so that
+	// rules can check the blocks signed by an account
+	CliqueHeader struct {
+		Number     uint64         `json:"number"`
+		ParentHash common.Hash    `json:"parent_hash"`
+		Time       uint64         `json:"time"`
+		Coinbase   common.Address `json:"coinbase"`
 	}
 	SignDataResponse struct {
 		Approved bool `json:"approved"`
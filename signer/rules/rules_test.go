@@ -626,3 +626,92 @@ function ApproveSignData(r){
 		t.Fatalf("Expected approved")
 	}
 }
+
+func TestSignCliqueHeader(t *testing.T) {
+	js := `function ApproveSignData(r){
+    if (r.content_type != "application/x-clique-header") {
+        return "Reject"
+    }
+    var last = storage.get("clique_number")
+    if (last != "") {
+        var number = parseInt(last)
+        if (r.clique_header.number < number) {
+            return "Reject"
+        }
+        if (r.clique_header.number == number) {
+            return r.hash == storage.get("clique_hash") ? "Approve" : "Reject"
+        }
+    }
+    storage.put("clique_number", String(r.clique_header.number))
+    storage.put("clique_hash", r.hash)
+    return "Approve"
+}`
+	r, err := initRuleEngine(js)
+	if err != nil {
+		t.Fatalf("Couldn't create evaluator %v", err)
+	}
+	addr, _ := mixAddr("0x694267f14675d7e1b9494fd8d72fefe1755710fa")
+	sign := func(number uint64, hash byte) bool {
+		resp, err := r.ApproveSignData(&core.SignDataRequest{
+			ContentType: accounts.MimetypeClique,
+			Address:     *addr,
+			Hash:        []byte{hash},
+			Meta:        core.Metadata{Remote: "remoteip", Local: "localip", Scheme: "inproc"},
+			Clique:      &core.CliqueHeader{Number: number},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		return resp.Approved
+	}
+	if !sign(1, 1) {
+		t.Fatalf("Expected first header approved")
+	}
+	if !sign(1, 1) {
+		t.Fatalf("Expected identical header at the last height approved")
+	}
+	if sign(1, 2) {
+		t.Fatalf("Expected different header at a signed height rejected")
+	}
+	if !sign(2, 3) {
+		t.Fatalf("Expected next header approved")
+	}
+	if sign(1, 1) {
+		t.Fatalf("Expected header below the last height rejected")
+	}
+}
+
+func TestSignPreconfirmation(t *testing.T) {
+	js := `function ApproveSignData(r){
+    if (r.content_type == "text/plain") {
+        return r.raw_data.length == 80 && r.raw_data.charAt(79) != "=" ? "Approve" : "Reject"
+    }
+    return "Reject"
+}`
+	r, err := initRuleEngine(js)
+	if err != nil {
+		t.Fatalf("Couldn't create evaluator %v", err)
+	}
+	addr, _ := mixAddr("0x694267f14675d7e1b9494fd8d72fefe1755710fa")
+	sign := func(text []byte) bool {
+		_, msg := accounts.TextAndHash(text)
+		resp, err := r.ApproveSignData(&core.SignDataRequest{
+			ContentType: accounts.MimetypeTextPlain,
+			Address:     *addr,
+			Rawdata:     []byte(msg),
+			Meta:        core.Metadata{Remote: "remoteip", Local: "localip", Scheme: "inproc"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		return resp.Approved
+	}
+	if !sign(make([]byte, 32)) {
+		t.Fatalf("Expected 32 byte hash approved")
+	}
+	for _, n := range []int{0, 30, 31, 33, 64} {
+		if sign(make([]byte, n)) {
+			t.Fatalf("Expected %d byte text rejected", n)
+		}
+	}
+}
@@ -65,7 +65,9 @@ func (it *Iterator) Prove() [][]byte {
 	return it.nodeIt.LeafProof()
 }
 
-// NodeIterator is an iterator to traverse the trie pre-order.
+// NodeIterator is an iterator to traverse the trie pre-order. Leaves of the
+// Merkle Patricia trie are returned in ascending order of their keys, leaves of
+// the zktrie in little-endian bit-path order of their hashed keys.
 type NodeIterator interface {
 	// Next moves the iterator to the next node. If the parameter is false, any child
 	// nodes will be skipped.
@@ -111,11 +111,9 @@ func (t *ZkTrie) GetKey(kHashBytes []byte) []byte {
 	k, err := zkt.NewBigIntFromHashBytes(kHashBytes)
 	if err != nil {
 		log.Error(fmt.Sprintf("Unhandled trie error: %v", err))
+		return nil
 	}
-	if t.db.db.preimages != nil {
-		return t.db.db.preimages.preimage(common.BytesToHash(k.Bytes()))
-	}
-	return nil
+	return t.db.Preimage(k)
 }
 
 // Commit writes all nodes and the secure hash pre-images to the trie's database.
@@ -144,10 +142,15 @@ func (t *ZkTrie) Copy() *ZkTrie {
 }
 
 // NodeIterator returns an iterator that returns nodes of the underlying trie. Iteration
-// starts at the key after the given start key.
+// starts at the given hashed key, or at the first leaf after it in path order.
+//
+// Unlike the iterator of the Merkle Patricia trie, leaves are not returned in
+// ascending order of their hashed keys: the path of a leaf is the bit sequence
+// of its hashed key read from the least significant bit, and leaves come in
+// that order. Callers paging through the trie must resume from the last key
+// returned rather than compare keys numerically.
 func (t *ZkTrie) NodeIterator(start []byte) NodeIterator {
-	/// FIXME
-	panic("not implemented")
+	return newZkNodeIterator(t, start)
 }
 
 // hashKey returns the hash of key as an ephemeral buffer.
@@ -83,6 +83,15 @@ func (l *ZktrieDatabase) UpdatePreimage(preimage []byte, hashField *big.Int) {
 	}
 }
 
+// Preimage retrieves the preimage of a hashed key, or nil if preimage recording
+// is disabled or the preimage is unknown.
+func (l *ZktrieDatabase) Preimage(hashField *big.Int) []byte {
+	if l.db.preimages == nil {
+		return nil
+	}
+	return l.db.preimages.preimage(common.BytesToHash(hashField.Bytes()))
+}
+
 // Iterate implements the method Iterate of the interface Storage
 func (l *ZktrieDatabase) Iterate(f func([]byte, []byte) (bool, error)) error {
 	iter := l.db.diskdb.NewIterator(l.prefix, nil)
@@ -0,0 +1,243 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	zktrie "github.com/scroll-tech/zktrie/trie"
+	zkt "github.com/scroll-tech/zktrie/types"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+)
+
+// zkIteratorState represents the iteration state at one particular node of the
+// zktrie, which can be resumed at a later invocation.
+type zkIteratorState struct {
+	hash    *zkt.Hash    // Hash of the node being iterated
+	node    *zktrie.Node // Zktrie node being iterated
+	bit     byte         // Path bit leading from the parent to this node
+	index   int          // Child to be processed next (0: left, 1: right, 2: done)
+	visited bool         // Whether the node itself has been returned by Next
+}
+
+// zkNodeIterator is a NodeIterator over a zktrie. The zktrie is a binary tree
+// whose path is the little-endian bit sequence of the hashed key, so leaves are
+// returned in path order rather than in numeric order of the hashed key. Keys
+// returned by LeafKey are always valid start positions for a new iterator.
+type zkNodeIterator struct {
+	trie  *ZkTrie            // Zktrie being iterated
+	stack []*zkIteratorState // Hierarchy of zktrie nodes persisting the iteration state
+	err   error              // Failure set in case of an internal error in the iterator
+
+	resolver ethdb.KeyValueStore // Optional intermediate resolver above the disk layer
+}
+
+func newZkNodeIterator(t *ZkTrie, start []byte) NodeIterator {
+	it := &zkNodeIterator{trie: t}
+	root, err := t.Tree().Root()
+	if err != nil {
+		it.err = err
+		return it
+	}
+	if *root == zkt.HashZero {
+		it.err = errIteratorEnd
+		return it
+	}
+	if err := it.seek(root, start); err != nil {
+		it.err = seekError{start, err}
+	}
+	return it
+}
+
+// seek positions the iterator so that the next call to Next returns the first
+// node whose path is not before the path of the given hashed key.
+func (it *zkNodeIterator) seek(root *zkt.Hash, start []byte) error {
+	node, err := it.resolve(root)
+	if err != nil {
+		return err
+	}
+	state := &zkIteratorState{hash: root, node: node}
+	if len(start) == 0 {
+		it.stack = append(it.stack, state)
+		return nil
+	}
+	key := zkt.NewHashFromBytes(start)
+	for depth := 0; ; depth++ {
+		switch state.node.Type {
+		case zktrie.NodeTypeLeaf_New:
+			state.visited = compareZkPath(state.node.NodeKey, key) < 0
+			it.stack = append(it.stack, state)
+			return nil
+		case zktrie.NodeTypeEmpty_New:
+			return nil
+		}
+		// Nodes on the path towards the start key precede it in pre-order, so
+		// they are considered visited together with their left child if the
+		// path turns right.
+		bit := byte(0)
+		child := state.node.ChildL
+		if zkt.TestBit(key[:], uint(depth)) {
+			bit, child = 1, state.node.ChildR
+		}
+		state.visited, state.index = true, int(bit)+1
+		it.stack = append(it.stack, state)
+		if *child == zkt.HashZero {
+			return nil
+		}
+		node, err := it.resolve(child)
+		if err != nil {
+			return err
+		}
+		state = &zkIteratorState{hash: child, node: node, bit: bit}
+	}
+}
+
+// compareZkPath compares the zktrie paths of two hashed keys.
+func compareZkPath(a, b *zkt.Hash) int {
+	for i := uint(0); i < zkt.HashByteLen*8; i++ {
+		abit, bbit := zkt.TestBit(a[:], i), zkt.TestBit(b[:], i)
+		if abit != bbit {
+			if abit {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+func (it *zkNodeIterator) resolve(hash *zkt.Hash) (*zktrie.Node, error) {
+	if it.resolver != nil {
+		if blob, err := it.resolver.Get(hash[:]); err == nil && len(blob) > 0 {
+			if node, err := zktrie.NewNodeFromBytes(blob); err == nil {
+				return node, nil
+			}
+		}
+	}
+	return it.trie.Tree().GetNode(hash)
+}
+
+func (it *zkNodeIterator) Next(descend bool) bool {
+	if it.err != nil {
+		return false
+	}
+	if !descend && len(it.stack) > 0 {
+		if top := it.stack[len(it.stack)-1]; top.visited {
+			top.index = 2
+		}
+	}
+	for len(it.stack) > 0 {
+		top := it.stack[len(it.stack)-1]
+		if !top.visited {
+			top.visited = true
+			return true
+		}
+		if top.node.IsTerminal() || top.index > 1 {
+			it.stack = it.stack[:len(it.stack)-1]
+			continue
+		}
+		bit, child := byte(top.index), top.node.ChildL
+		if bit == 1 {
+			child = top.node.ChildR
+		}
+		if *child == zkt.HashZero {
+			top.index++
+			continue
+		}
+		node, err := it.resolve(child)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		top.index++
+		it.stack = append(it.stack, &zkIteratorState{hash: child, node: node, bit: bit})
+	}
+	it.err = errIteratorEnd
+	return false
+}
+
+func (it *zkNodeIterator) Error() error {
+	if it.err == errIteratorEnd {
+		return nil
+	}
+	if seek, ok := it.err.(seekError); ok {
+		return seek.err
+	}
+	return it.err
+}
+
+func (it *zkNodeIterator) Hash() common.Hash {
+	if len(it.stack) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(it.stack[len(it.stack)-1].hash.Bytes())
+}
+
+func (it *zkNodeIterator) Parent() common.Hash {
+	if len(it.stack) < 2 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(it.stack[len(it.stack)-2].hash.Bytes())
+}
+
+// Path returns the bit path to the current node, one byte (0 or 1) per level.
+func (it *zkNodeIterator) Path() []byte {
+	if len(it.stack) == 0 {
+		return nil
+	}
+	path := make([]byte, 0, len(it.stack)-1)
+	for _, state := range it.stack[1:] {
+		path = append(path, state.bit)
+	}
+	return path
+}
+
+func (it *zkNodeIterator) Leaf() bool {
+	return len(it.stack) > 0 && it.stack[len(it.stack)-1].node.Type == zktrie.NodeTypeLeaf_New
+}
+
+// LeafKey returns the hashed key of the leaf as a big-endian integer, which is
+// the representation accepted by ZkTrie.GetKey.
+func (it *zkNodeIterator) LeafKey() []byte {
+	if it.Leaf() {
+		return it.stack[len(it.stack)-1].node.NodeKey.Bytes()
+	}
+	panic("not at leaf")
+}
+
+// LeafBlob returns the value of the leaf in the same format as ZkTrie.TryGet.
+func (it *zkNodeIterator) LeafBlob() []byte {
+	if it.Leaf() {
+		return common.CopyBytes(it.stack[len(it.stack)-1].node.Data())
+	}
+	panic("not at leaf")
+}
+
+func (it *zkNodeIterator) LeafProof() [][]byte {
+	if !it.Leaf() {
+		panic("not at leaf")
+	}
+	proofs := make([][]byte, 0, len(it.stack))
+	for _, state := range it.stack {
+		proofs = append(proofs, state.node.Value())
+	}
+	return proofs
+}
+
+func (it *zkNodeIterator) AddResolver(resolver ethdb.KeyValueStore) {
+	it.resolver = resolver
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+)
+
+func TestZkTrieIterator(t *testing.T) {
+	trie := newEmptyZkTrie()
+	content := make(map[string][]byte)
+	for i := byte(0); i < 200; i++ {
+		key, val := common.LeftPadBytes([]byte{1, i}, 32), common.LeftPadBytes([]byte{i + 1}, 32)
+		content[string(key)] = val
+		trie.Update(key, val)
+	}
+	if _, _, err := trie.Commit(nil); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+
+	var keys [][]byte
+	it := NewIterator(trie.NodeIterator(nil))
+	for it.Next() {
+		preimage := trie.GetKey(it.Key)
+		if preimage == nil {
+			t.Fatalf("missing preimage for key %x", it.Key)
+		}
+		want, ok := content[string(preimage)]
+		if !ok {
+			t.Fatalf("unexpected key %x", preimage)
+		}
+		if !bytes.Equal(it.Value, want) {
+			t.Fatalf("value mismatch for key %x: have %x, want %x", preimage, it.Value, want)
+		}
+		keys = append(keys, common.CopyBytes(it.Key))
+	}
+	if it.Err != nil {
+		t.Fatalf("iterator error: %v", it.Err)
+	}
+	if len(keys) != len(content) {
+		t.Fatalf("leaf count mismatch: have %d, want %d", len(keys), len(content))
+	}
+
+	// Resuming from any returned key must continue with that very key.
+	for _, i := range []int{0, 1, len(keys) / 2, len(keys) - 1} {
+		it := NewIterator(trie.NodeIterator(keys[i]))
+		for j := i; j < len(keys); j++ {
+			if !it.Next() {
+				t.Fatalf("resume at %d: iterator ended early at %d", i, j)
+			}
+			if !bytes.Equal(it.Key, keys[j]) {
+				t.Fatalf("resume at %d: key %d mismatch: have %x, want %x", i, j, it.Key, keys[j])
+			}
+		}
+		if it.Next() {
+			t.Fatalf("resume at %d: unexpected extra key %x", i, it.Key)
+		}
+	}
+}
+
+func TestZkTrieIteratorEmpty(t *testing.T) {
+	trie := newEmptyZkTrie()
+	it := trie.NodeIterator(nil)
+	if it.Next(true) {
+		t.Fatal("empty trie iterator returned a node")
+	}
+	if err := it.Error(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
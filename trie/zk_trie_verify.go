@@ -0,0 +1,151 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"errors"
+	"fmt"
+
+	zktrie "github.com/scroll-tech/zktrie/trie"
+	zkt "github.com/scroll-tech/zktrie/types"
+
+	"github.com/scroll-tech/go-ethereum/common"
+)
+
+var (
+	// errZkNodeMissing is returned if a referenced zktrie node is not in the database.
+	errZkNodeMissing = errors.New("missing node")
+
+	// errZkNodeHashMismatch is returned if the hash recomputed from a zktrie node
+	// differs from the hash it is referenced by.
+	errZkNodeHashMismatch = errors.New("node hash mismatch")
+
+	// errZkNodeTypeMismatch is returned if a branch node type does not agree with
+	// the terminal-ness of its children.
+	errZkNodeTypeMismatch = errors.New("branch type mismatch")
+
+	// errZkLeafPathMismatch is returned if a leaf is stored at a path that is not
+	// a prefix of its key.
+	errZkLeafPathMismatch = errors.New("leaf path mismatch")
+)
+
+// ZkTrieNodeError describes a zktrie node that is missing from the database or
+// whose content is inconsistent with the way it is referenced.
+type ZkTrieNodeError struct {
+	Hash common.Hash // Hash the node is referenced by
+	Path []byte      // Bit path to the node, one byte (0 or 1) per level
+	Err  error
+}
+
+func (e *ZkTrieNodeError) Error() string {
+	return fmt.Sprintf("zktrie node %x (depth %d): %v", e.Hash, len(e.Path), e.Err)
+}
+
+// VerifyZkTrie walks every node reachable from the given root, recomputing the
+// Poseidon hash of each node and checking it against the hash it is referenced
+// by. onLeaf is invoked for every intact leaf; the path passed to it must not be
+// retained after the callback returns. Missing or corrupt nodes are reported
+// through onError and their subtrees are skipped, so a single walk reports every
+// damaged part of the trie. The walk is aborted as soon as either callback
+// returns an error. The number of visited nodes is returned.
+func VerifyZkTrie(db *ZktrieDatabase, root common.Hash, onLeaf func(path []byte, leaf *zktrie.Node) error, onError func(*ZkTrieNodeError) error) (int, error) {
+	v := &zkTrieVerifier{db: db, onLeaf: onLeaf, onError: onError}
+	hash := zkt.NewHashFromBytes(root.Bytes())
+	if *hash == zkt.HashZero {
+		return 0, nil
+	}
+	_, err := v.verify(hash, nil)
+	return v.nodes, err
+}
+
+type zkTrieVerifier struct {
+	db      *ZktrieDatabase
+	onLeaf  func([]byte, *zktrie.Node) error
+	onError func(*ZkTrieNodeError) error
+	nodes   int
+}
+
+func (v *zkTrieVerifier) report(hash *zkt.Hash, path []byte, err error) error {
+	return v.onError(&ZkTrieNodeError{Hash: common.BytesToHash(hash.Bytes()), Path: common.CopyBytes(path), Err: err})
+}
+
+// verify checks the subtree referenced by hash and returns its root node, or nil
+// if the subtree could not be loaded.
+func (v *zkTrieVerifier) verify(hash *zkt.Hash, path []byte) (*zktrie.Node, error) {
+	if *hash == zkt.HashZero {
+		return zktrie.NewEmptyNode(), nil
+	}
+	blob, err := v.db.Get(hash[:])
+	if err == nil && len(blob) == 0 || err == zktrie.ErrKeyNotFound {
+		err = errZkNodeMissing
+	}
+	if err != nil {
+		return nil, v.report(hash, path, err)
+	}
+	v.nodes++
+
+	node, err := zktrie.NewNodeFromBytes(blob)
+	if err != nil {
+		return nil, v.report(hash, path, err)
+	}
+	have, err := node.NodeHash()
+	if err != nil {
+		return nil, v.report(hash, path, err)
+	}
+	if *have != *hash {
+		return nil, v.report(hash, path, fmt.Errorf("%w: recomputed %x", errZkNodeHashMismatch, have.Bytes()))
+	}
+	switch node.Type {
+	case zktrie.NodeTypeLeaf_New:
+		for i, bit := range path {
+			if zkt.TestBit(node.NodeKey[:], uint(i)) != (bit == 1) {
+				return nil, v.report(hash, path, errZkLeafPathMismatch)
+			}
+		}
+		return node, v.onLeaf(path, node)
+	case zktrie.NodeTypeEmpty_New:
+		return node, nil
+	case zktrie.NodeTypeBranch_0, zktrie.NodeTypeBranch_1, zktrie.NodeTypeBranch_2, zktrie.NodeTypeBranch_3:
+		left, err := v.verify(node.ChildL, append(path, 0))
+		if err != nil {
+			return nil, err
+		}
+		right, err := v.verify(node.ChildR, append(path, 1))
+		if err != nil {
+			return nil, err
+		}
+		if left != nil && right != nil {
+			var want zktrie.NodeType
+			switch {
+			case left.IsTerminal() && right.IsTerminal():
+				want = zktrie.NodeTypeBranch_0
+			case left.IsTerminal():
+				want = zktrie.NodeTypeBranch_1
+			case right.IsTerminal():
+				want = zktrie.NodeTypeBranch_2
+			default:
+				want = zktrie.NodeTypeBranch_3
+			}
+			if node.Type != want {
+				return nil, v.report(hash, path, fmt.Errorf("%w: have %d, want %d", errZkNodeTypeMismatch, node.Type, want))
+			}
+		}
+		return node, nil
+	default:
+		return nil, v.report(hash, path, fmt.Errorf("unexpected node type %d", node.Type))
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"errors"
+	"testing"
+
+	zktrie "github.com/scroll-tech/zktrie/trie"
+	zkt "github.com/scroll-tech/zktrie/types"
+)
+
+func TestVerifyZkTrie(t *testing.T) {
+	triedb, trie, content := makeTestZkTrie()
+	root := trie.Hash()
+
+	var leaves int
+	onLeaf := func(path []byte, leaf *zktrie.Node) error {
+		leaves++
+		return nil
+	}
+	var reports []*ZkTrieNodeError
+	onError := func(err *ZkTrieNodeError) error {
+		reports = append(reports, err)
+		return nil
+	}
+	nodes, err := VerifyZkTrie(triedb, root, onLeaf, onError)
+	if err != nil {
+		t.Fatalf("verification failed: %v", err)
+	}
+	if len(reports) != 0 {
+		t.Fatalf("unexpected reports on intact trie: %v", reports)
+	}
+	if leaves != len(content) {
+		t.Fatalf("leaf count mismatch: have %d, want %d", leaves, len(content))
+	}
+	if nodes <= leaves {
+		t.Fatalf("node count %d not above leaf count %d", nodes, leaves)
+	}
+
+	// Overwrite the first leaf found with a different leaf and make sure the
+	// hash mismatch is detected while the rest of the trie is still walked.
+	it := trie.NodeIterator(nil)
+	for it.Next(true) && !it.Leaf() {
+	}
+	target := zkt.NewHashFromBytes(it.Hash().Bytes())
+	forged := zktrie.NewLeafNode(zkt.NewHashFromBytes(it.LeafKey()), 1, []zkt.Byte32{{1}})
+	if err := triedb.Put(target[:], forged.Value()); err != nil {
+		t.Fatalf("failed to corrupt node: %v", err)
+	}
+	leaves, reports = 0, nil
+	if _, err := VerifyZkTrie(triedb, root, onLeaf, onError); err != nil {
+		t.Fatalf("verification failed: %v", err)
+	}
+	if len(reports) != 1 || !errors.Is(reports[0].Err, errZkNodeHashMismatch) {
+		t.Fatalf("unexpected reports on corrupt trie: %v", reports)
+	}
+	if leaves != len(content)-1 {
+		t.Fatalf("leaf count mismatch: have %d, want %d", leaves, len(content)-1)
+	}
+}